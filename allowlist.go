@@ -0,0 +1,108 @@
+package htmlcleaner
+
+import (
+	"regexp"
+	"sort"
+
+	"golang.org/x/net/html/atom"
+)
+
+// AllowList is a declarative form of the element and attribute rules of a
+// Config, using names instead of atoms, so that a policy can be written as a
+// struct literal or loaded from a file. Config.Allow adds an AllowList to a
+// Config, and Config.AllowList returns the rules of a Config.
+type AllowList struct {
+	// The allowed elements, each with the attributes allowed on it.
+	Elems map[string][]string
+
+	// Regular expressions that the value of an attribute must match, as
+	// with ElemAttrMatch. The attribute is allowed on the element even if
+	// it is not listed in Elems.
+	AttrMatch map[ElemAttr]*regexp.Regexp
+
+	// Attributes allowed on all allowed elements.
+	GlobalAttrs []string
+
+	// Elements whose children are treated like root nodes by WrapText.
+	WrapTextInside []string
+
+	// Disallowed elements that are replaced by their contents instead of
+	// being escaped.
+	Unwrap []string
+}
+
+// Allow adds the rules in list to the Config, as if the corresponding
+// builder methods were called. The receiver is returned to allow call
+// chaining.
+func (c *Config) Allow(list AllowList) *Config {
+	for elem, attrs := range list.Elems {
+		c.Elem(elem).ElemAttr(elem, attrs...)
+	}
+	for ea, re := range list.AttrMatch {
+		c.ElemAttrMatch(ea.Elem, ea.Attr, re)
+	}
+	return c.GlobalAttr(list.GlobalAttrs...).
+		WrapTextInside(list.WrapTextInside...).
+		Unwrap(list.Unwrap...)
+}
+
+// AllowList returns the element and attribute rules of the Config. Names are
+// sorted, and each attribute with a regular expression is listed in both
+// Elems and AttrMatch.
+func (c *Config) AllowList() AllowList {
+	var list AllowList
+
+	add := func(elem, attr string, re *regexp.Regexp) {
+		if list.Elems == nil {
+			list.Elems = make(map[string][]string)
+		}
+		if _, ok := list.Elems[elem]; !ok {
+			list.Elems[elem] = []string{}
+		}
+		if attr == "" {
+			return
+		}
+		list.Elems[elem] = append(list.Elems[elem], attr)
+		if re != nil {
+			if list.AttrMatch == nil {
+				list.AttrMatch = make(map[ElemAttr]*regexp.Regexp)
+			}
+			list.AttrMatch[ElemAttr{Elem: elem, Attr: attr}] = re
+		}
+	}
+
+	for e, attrs := range c.elem {
+		add(e.String(), "", nil)
+		for a, re := range attrs {
+			add(e.String(), a.String(), re)
+		}
+	}
+	for e, attrs := range c.elemCustom {
+		add(e, "", nil)
+		for a, re := range attrs {
+			add(e, a, re)
+		}
+	}
+	for _, attrs := range list.Elems {
+		sort.Strings(attrs)
+	}
+
+	list.GlobalAttrs = atomSetNames(c.attr, c.attrCustom)
+	list.WrapTextInside = atomSetNames(c.wrap, c.wrapCustom)
+	list.Unwrap = atomSetNames(c.unwrap, c.unwrapCustom)
+
+	return list
+}
+
+// atomSetNames returns the sorted names in a pair of atom and string sets.
+func atomSetNames(atoms map[atom.Atom]struct{}, custom map[string]struct{}) []string {
+	var names []string
+	for a := range atoms {
+		names = append(names, a.String())
+	}
+	for name := range custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
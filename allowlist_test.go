@@ -0,0 +1,49 @@
+package htmlcleaner
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+var testAllowList = AllowList{
+	Elems: map[string][]string{
+		"a":      {"href"},
+		"b":      {},
+		"my-tag": {"data-x", "size"},
+		"span":   {"class"},
+	},
+	AttrMatch: map[ElemAttr]*regexp.Regexp{
+		{Elem: "span", Attr: "class"}: regexp.MustCompile(`\Afa-spin\z`),
+	},
+	GlobalAttrs:    []string{"data-y", "title"},
+	WrapTextInside: []string{"my-tag"},
+	Unwrap:         []string{"div", "font"},
+}
+
+var allowListConfig = (&Config{}).Allow(testAllowList)
+
+var testTableAllowList = []testTable{
+	{"Declarative", `<a href="/" title="t" class="x">a</a><span class="fa-spin">b</span><span class="x">c</span><div><font>d</font></div>`, `<a href="/" title="t">a</a><span class="fa-spin">b</span><span>c</span>d`, allowListConfig},
+	{"Custom", `<my-tag data-x="1" data-y="2" data-z="3">a</my-tag>`, `<my-tag data-x="1" data-y="2">a</my-tag>`, allowListConfig},
+}
+
+func TestAllowList(t *testing.T) {
+	doTableTest(Clean, t, testTableAllowList)
+
+	if list := allowListConfig.AllowList(); !reflect.DeepEqual(list, testAllowList) {
+		t.Errorf("expected %+v\nactual   %+v", testAllowList, list)
+	}
+
+	builder := (&Config{}).
+		ElemAttr("a", "href").
+		Elem("b").
+		ElemAttr("my-tag", "size", "data-x").
+		ElemAttrMatch("span", "class", regexp.MustCompile(`\Afa-spin\z`)).
+		GlobalAttr("title", "data-y").
+		WrapTextInside("my-tag").
+		Unwrap("font", "div")
+	if list := builder.AllowList(); !reflect.DeepEqual(list, testAllowList) {
+		t.Errorf("expected %+v\nactual   %+v", testAllowList, list)
+	}
+}
@@ -0,0 +1,60 @@
+package htmlcleaner
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// AltAction is what happens to <img> elements without an alt attribute.
+// See Config.MissingAlt.
+type AltAction int
+
+const (
+	// AltIgnore leaves images without alt text alone.
+	AltIgnore AltAction = iota
+
+	// AltEmpty adds alt="", which marks the image as decorative.
+	AltEmpty
+
+	// AltFromTitle copies the title attribute of the image to its alt
+	// attribute, or adds alt="" if it has no title.
+	AltFromTitle
+
+	// AltDrop removes the image.
+	AltDrop
+)
+
+// checkAlt applies AltText and MissingAlt to n if it is an image without
+// alt text, and reports whether n should be kept.
+func (c *cleaner) checkAlt(n *html.Node) bool {
+	if n.DataAtom != atom.Img || (c.MissingAlt == AltIgnore && c.AltText == nil) {
+		return true
+	}
+	if _, ok := getAttr(n, "alt"); ok {
+		return true
+	}
+
+	if c.AltText != nil {
+		src, _ := getAttr(n, "src")
+		if alt, ok := c.AltText(src); ok {
+			c.explainf(n, "added alt: AltText")
+			n.Attr = append(n.Attr, html.Attribute{Key: "alt", Val: alt})
+			return true
+		}
+	}
+
+	switch c.MissingAlt {
+	case AltEmpty:
+		c.explainf(n, "added alt: MissingAlt")
+		n.Attr = append(n.Attr, html.Attribute{Key: "alt"})
+	case AltFromTitle:
+		title, _ := getAttr(n, "title")
+		c.explainf(n, "added alt: MissingAlt")
+		n.Attr = append(n.Attr, html.Attribute{Key: "alt", Val: title})
+	case AltDrop:
+		c.explainf(n, "removed: no alt")
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,81 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+)
+
+var altEmptyConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.MissingAlt = AltEmpty
+
+	return c
+}()
+
+var altFromTitleConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.MissingAlt = AltFromTitle
+
+	return c
+}()
+
+var altDropConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.MissingAlt = AltDrop
+
+	return c
+}()
+
+var altLookupConfig = func() *Config {
+	c := altDropConfig.Clone()
+
+	c.AltText = lookupAlt
+
+	return c
+}()
+
+var altLookupOnlyConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.AltText = lookupAlt
+
+	return c
+}()
+
+func lookupAlt(src string) (string, bool) {
+	if strings.HasPrefix(src, "/known/") {
+		return "known image", true
+	}
+	return "", false
+}
+
+var testTableAlt = []testTable{
+	{"Ignore", `<img src="/a.png">`, `<img src="/a.png"/>`, nil},
+	{"Empty", `<img src="/a.png">`, `<img src="/a.png" alt=""/>`, altEmptyConfig},
+	{"Present", `<img src="/a.png" alt="A">`, `<img src="/a.png" alt="A"/>`, altEmptyConfig},
+	{"PresentEmpty", `<img src="/a.png" alt="">`, `<img src="/a.png" alt=""/>`, altDropConfig},
+	{"FromTitle", `<img src="/a.png" title="A">`, `<img src="/a.png" title="A" alt="A"/>`, altFromTitleConfig},
+	{"FromNoTitle", `<img src="/a.png">`, `<img src="/a.png" alt=""/>`, altFromTitleConfig},
+	{"Drop", `a<img src="/a.png">b`, `ab`, altDropConfig},
+	{"Callback", `<img src="/known/a.png">`, `<img src="/known/a.png" alt="known image"/>`, altLookupConfig},
+	{"CallbackFails", `a<img src="/a.png">b`, `ab`, altLookupConfig},
+	{"CallbackOnly", `<img src="/a.png"><img src="/known/b.png">`, `<img src="/a.png"/><img src="/known/b.png" alt="known image"/>`, altLookupOnlyConfig},
+}
+
+func TestMissingAlt(t *testing.T) {
+	doTableTest(Clean, t, testTableAlt)
+
+	// The alt attribute is added even if it is not allowed.
+	c := (&Config{ValidateURL: SafeURLScheme}).Elem("img").ElemAttr("img", "src")
+	c.MissingAlt = AltFromTitle
+	output := Clean(c, `<img src="/a.png" title="A">`)
+	if expected := `<img src="/a.png" alt=""/>`; output != expected {
+		t.Errorf("expected %q, actual %q", expected, output)
+	}
+	if err := c.CheckOutput(output); err != nil {
+		t.Error(err)
+	}
+}
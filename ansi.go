@@ -0,0 +1,135 @@
+package htmlcleaner
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ANSI Select Graphic Rendition parameters.
+const (
+	ansiBold      = "1"
+	ansiDim       = "2"
+	ansiItalic    = "3"
+	ansiUnderline = "4"
+	ansiStrike    = "9"
+)
+
+// RenderANSI cleans a fragment of HTML using the specified Config, or the
+// FallbackConfig if it is nil, and converts it to text styled with ANSI escape
+// sequences for display in a terminal. Emphasis is rendered as bold, italic,
+// underlined, or struck through text, lists are bulleted or numbered, and
+// quotations are indented with a vertical bar.
+func RenderANSI(c *Config, fragment string) string {
+	nodes := cleanFragment(c, fragment)
+	for _, n := range nodes {
+		Walk(n, func(n *html.Node) WalkAction {
+			if n.Type == html.TextNode {
+				n.Data = stripTerminalControls(n.Data)
+			}
+			return WalkContinue
+		})
+	}
+
+	r := &ansiRenderer{}
+	w := newTextWriter(r.element)
+	w.nodes(nodes)
+
+	return w.String()
+}
+
+// stripTerminalControls removes C0 and C1 control characters other than
+// newlines and tabs from s, so that text cannot start escape sequences of
+// its own, such as one that changes the terminal's title.
+func stripTerminalControls(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+type ansiRenderer struct {
+	styles []string
+}
+
+// style renders the contents of n with an additional SGR parameter.
+func (r *ansiRenderer) style(w *textWriter, sgr string, f func()) {
+	r.styles = append(r.styles, sgr)
+	w.write("\x1b[" + sgr + "m")
+
+	f()
+
+	r.styles = r.styles[:len(r.styles)-1]
+	w.write("\x1b[0m")
+	if len(r.styles) != 0 {
+		w.write("\x1b[" + strings.Join(r.styles, ";") + "m")
+	}
+}
+
+func (r *ansiRenderer) element(w *textWriter, n *html.Node) {
+	children := func() { w.children(n) }
+
+	switch n.DataAtom {
+	case atom.B, atom.Strong:
+		r.style(w, ansiBold, children)
+	case atom.I, atom.Em, atom.Cite, atom.Q:
+		r.style(w, ansiItalic, children)
+	case atom.U, atom.Ins:
+		r.style(w, ansiUnderline, children)
+	case atom.S, atom.Strike, atom.Del:
+		r.style(w, ansiStrike, children)
+	case atom.Code, atom.Kbd, atom.Tt:
+		r.style(w, ansiDim, children)
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		w.block(2)
+		r.style(w, ansiBold, children)
+		w.block(2)
+	case atom.A:
+		r.style(w, ansiUnderline, children)
+		if href, _ := getAttr(n, "href"); href != "" && href != textContent(n) {
+			w.text(" (" + stripTerminalControls(href) + ")")
+		}
+	case atom.Img:
+		if alt, _ := getAttr(n, "alt"); alt != "" {
+			w.text("[image: " + stripTerminalControls(alt) + "]")
+		} else {
+			w.text("[image]")
+		}
+	case atom.P:
+		w.block(2)
+		w.children(n)
+		w.block(2)
+	case atom.Blockquote:
+		w.block(2)
+		w.indent("│ ", children)
+		w.block(2)
+	case atom.Ul, atom.Ol:
+		w.block(1)
+		i := 0
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+				w.node(c)
+				continue
+			}
+			i++
+			marker := "• "
+			if n.DataAtom == atom.Ol {
+				marker = strconv.Itoa(i) + ". "
+			}
+			w.block(1)
+			w.write(marker)
+			w.indent(strings.Repeat(" ", len([]rune(marker))), func() { w.children(c) })
+			w.block(1)
+		}
+		w.block(1)
+	default:
+		w.defaultElement(n)
+	}
+}
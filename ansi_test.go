@@ -0,0 +1,26 @@
+package htmlcleaner
+
+import (
+	"testing"
+
+	"golang.org/x/net/html/atom"
+)
+
+var ansiConfig = DefaultConfig.Clone().ElemAtom(atom.Ul, atom.Ol, atom.Li, atom.H1)
+
+var testTableRenderANSI = []testTable{
+	{"Empty", ``, ``, ansiConfig},
+	{"Bold", `a <b>b</b> c`, "a \x1b[1mb\x1b[0m c", ansiConfig},
+	{"Nested", `<b>a <i>b</i> c</b>`, "\x1b[1ma \x1b[3mb\x1b[0m\x1b[1m c\x1b[0m", ansiConfig},
+	{"Link", `<a href="https://example.com/">x</a>`, "\x1b[4mx\x1b[0m (https://example.com/)", ansiConfig},
+	{"Heading", `<h1>Title</h1><p>a</p><p>b</p>`, "\x1b[1mTitle\x1b[0m\n\na\n\nb", ansiConfig},
+	{"List", `<ul><li>a</li><li>b<ol><li>c</li><li>d</li></ol></li></ul>`, "• a\n• b\n  1. c\n  2. d", ansiConfig},
+	{"Quote", `<blockquote><p>a</p><p>b</p></blockquote>`, "│ a\n│\n│ b", ansiConfig},
+	{"Pre", "<pre>a\n  b</pre>", "a\n  b", ansiConfig},
+	{"Controls", "a\x1b]0;pwned\a\x1b[2J\u009b2Jb", "a]0;pwned[2J2Jb", ansiConfig},
+	{"ControlsInAttributes", "<img src=\"/a.png\" alt=\"\x1b[2Jx\">", "[image: [2Jx]", ansiConfig},
+}
+
+func TestRenderANSI(t *testing.T) {
+	doTableTest(RenderANSI, t, testTableRenderANSI)
+}
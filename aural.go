@@ -0,0 +1,93 @@
+package htmlcleaner
+
+import (
+	"strconv"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// RenderAural cleans a fragment of HTML using the specified Config, or the
+// FallbackConfig if it is nil, and converts it to a linear text description
+// similar to what a screen reader would announce. It is meant for previewing
+// the accessibility of content: links, images, headings, lists, and
+// quotations are announced as such.
+func RenderAural(c *Config, fragment string) string {
+	w := newTextWriter(auralElement)
+	w.nodes(cleanFragment(c, fragment))
+
+	return w.String()
+}
+
+func auralElement(w *textWriter, n *html.Node) {
+	switch n.DataAtom {
+	case atom.A:
+		if _, ok := getAttr(n, "href"); ok {
+			w.text("link:")
+			w.space = true
+		}
+		w.children(n)
+	case atom.Img:
+		if alt, _ := getAttr(n, "alt"); alt != "" {
+			w.text(" image with description: " + alt + ". ")
+		} else {
+			w.text(" image without description. ")
+		}
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		w.block(1)
+		w.text("heading level " + n.Data[1:] + ":")
+		w.space = true
+		w.children(n)
+		w.block(1)
+	case atom.Ul, atom.Ol:
+		count := 0
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.DataAtom == atom.Li {
+				count++
+			}
+		}
+		total := strconv.Itoa(count)
+
+		w.block(1)
+		if count == 1 {
+			w.text("list of 1 item.")
+		} else {
+			w.text("list of " + total + " items.")
+		}
+
+		i := 0
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+				w.node(c)
+				continue
+			}
+			i++
+			w.block(1)
+			w.text("item " + strconv.Itoa(i) + " of " + total + ":")
+			w.space = true
+			w.children(c)
+		}
+
+		w.block(1)
+		w.text("end of list.")
+		w.block(1)
+	case atom.Blockquote:
+		w.block(1)
+		w.text("quotation:")
+		w.block(1)
+		w.children(n)
+		w.block(1)
+		w.text("end of quotation.")
+		w.block(1)
+	case atom.Pre:
+		w.block(1)
+		w.text("code block:")
+		w.block(1)
+		w.preformatted(func() { w.children(n) })
+		w.block(1)
+		w.text("end of code block.")
+		w.block(1)
+	default:
+		w.defaultElement(n)
+	}
+}
@@ -0,0 +1,23 @@
+package htmlcleaner
+
+import (
+	"testing"
+
+	"golang.org/x/net/html/atom"
+)
+
+var auralConfig = DefaultConfig.Clone().ElemAtom(atom.Ul, atom.Ol, atom.Li, atom.H2)
+
+var testTableRenderAural = []testTable{
+	{"Empty", ``, ``, auralConfig},
+	{"Link", `see <a href="/x">this page</a> or <a>that</a>`, `see link: this page or that`, auralConfig},
+	{"Images", `<img src="a.png" alt="a cat"><img src="b.png">`, `image with description: a cat. image without description.`, auralConfig},
+	{"Heading", `<h2>Title</h2><p>text</p>`, "heading level 2: Title\ntext", auralConfig},
+	{"List", `<ol><li>a</li><li>b</li></ol><ul><li>c</li></ul>`, "list of 2 items.\nitem 1 of 2: a\nitem 2 of 2: b\nend of list.\nlist of 1 item.\nitem 1 of 1: c\nend of list.", auralConfig},
+	{"Quote", `<blockquote>a</blockquote>`, "quotation:\na\nend of quotation.", auralConfig},
+	{"Pre", "<pre>a\nb</pre>", "code block:\na\nb\nend of code block.", auralConfig},
+}
+
+func TestRenderAural(t *testing.T) {
+	doTableTest(RenderAural, t, testTableRenderAural)
+}
@@ -0,0 +1,87 @@
+package htmlcleaner
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	autoLinkURL = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"]+|` +
+		`\b[a-z0-9](?:[a-z0-9-]*[a-z0-9])?(?:\.[a-z0-9](?:[a-z0-9-]*[a-z0-9])?)*` +
+		`\.(?:com|net|org|edu|gov|mil|int|info|biz|io|dev|app|co|me|tv|us|uk|ca|au|de|fr|nl|jp)\b(?:/[^\s<>"]*)?`)
+	autoLinkEmail = regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9](?:[a-z0-9-]*[a-z0-9])?(?:\.[a-z0-9](?:[a-z0-9-]*[a-z0-9])?)*\.[a-z]{2,}\b`)
+)
+
+// autoLinkRules returns the text rules used for AutoLink. Text that would
+// become a link with a disallowed URL is left alone.
+func autoLinkRules(c *Config) []textRule {
+	link := func(href, s string) *html.Node {
+		attr := html.Attribute{Key: "href", Val: href}
+		if !cleanURL(c, "a", atom.Href, &attr) {
+			return text(s)
+		}
+		return Link(attr.Val, s)
+	}
+
+	return []textRule{
+		{re: autoLinkEmail, build: func(m []string) ([]*html.Node, bool) {
+			return []*html.Node{link("mailto:"+m[0], m[0])}, true
+		}},
+		{re: autoLinkURL, build: func(m []string) ([]*html.Node, bool) {
+			s, rest := trimURLPunctuation(m[0])
+
+			href := s
+			if !strings.Contains(strings.ToLower(href), "://") {
+				href = "http://" + href
+			}
+
+			nodes := []*html.Node{link(href, s)}
+			if rest != "" {
+				nodes = append(nodes, text(rest))
+			}
+			return nodes, true
+		}},
+	}
+}
+
+// trimURLPunctuation splits trailing punctuation that is more likely to be
+// part of the surrounding sentence than of the URL from the end of s.
+func trimURLPunctuation(s string) (string, string) {
+	end := len(s)
+	for end > 0 {
+		switch s[end-1] {
+		case '.', ',', ':', ';', '!', '?', '\'', '*', '_':
+			end--
+			continue
+		case ')':
+			if strings.Count(s[:end], "(") < strings.Count(s[:end], ")") {
+				end--
+				continue
+			}
+		}
+		break
+	}
+	return s[:end], s[end:]
+}
+
+// allowsLinks reports whether the Config allows <a href>.
+func (c *Config) allowsLinks() bool {
+	if attrs, ok := c.elem[atom.A]; ok {
+		if _, ok = attrs[atom.Href]; ok {
+			return true
+		}
+		_, ok = c.attr[atom.Href]
+		return ok
+	}
+	if attrs, ok := c.elemCustom["a"]; ok {
+		if _, ok = attrs["href"]; ok {
+			return true
+		}
+		_, ok = c.attr[atom.Href]
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package htmlcleaner
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/html/atom"
+)
+
+var autoLinkConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.AutoLink = true
+
+	return c
+}()
+
+var autoLinkHTTPSConfig = func() *Config {
+	c := autoLinkConfig.Clone()
+
+	c.ValidateURL = func(u *url.URL) bool {
+		return u.Scheme == "https"
+	}
+
+	return c
+}()
+
+var autoLinkNoLinksConfig = func() *Config {
+	c := &Config{AutoLink: true}
+
+	c.ElemAtom(atom.P)
+
+	return c
+}()
+
+var testTableAutoLink = []testTable{
+	{"Plain", `hello world`, `hello world`, autoLinkConfig},
+	{"URL", `see https://example.com/a?b=c.`, `see <a href="https://example.com/a?b=c">https://example.com/a?b=c</a>.`, autoLinkConfig},
+	{"WWW", `(www.example.com)`, `(<a href="http://www.example.com">www.example.com</a>)`, autoLinkConfig},
+	{"Parens", `https://en.wikipedia.org/wiki/Go_(game), ok`, `<a href="https://en.wikipedia.org/wiki/Go_(game)">https://en.wikipedia.org/wiki/Go_(game)</a>, ok`, autoLinkConfig},
+	{"TLD", `example.io and main.go`, `<a href="http://example.io">example.io</a> and main.go`, autoLinkConfig},
+	{"Email", `mail bob@example.com!`, `mail <a href="mailto:bob@example.com">bob@example.com</a>!`, autoLinkConfig},
+	{"ExistingLink", `<a href="https://example.com/">https://example.com/</a>`, `<a href="https://example.com/">https://example.com/</a>`, autoLinkConfig},
+	{"Code", `<code>https://example.com/</code>`, `<code>https://example.com/</code>`, autoLinkConfig},
+	{"ValidateURL", `http://example.com/ https://example.com/`, `http://example.com/ <a href="https://example.com/">https://example.com/</a>`, autoLinkHTTPSConfig},
+	{"NotAllowed", `https://example.com/`, `https://example.com/`, autoLinkNoLinksConfig},
+}
+
+func TestAutoLink(t *testing.T) {
+	doTableTest(Clean, t, testTableAutoLink)
+}
+
+func TestAutoLinkLarge(t *testing.T) {
+	input := strings.Repeat("x@y.zz ", 20000)
+
+	start := time.Now()
+	output := Clean(autoLinkConfig, input)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("took %v", elapsed)
+	}
+	if n := strings.Count(output, `<a href="mailto:x@y.zz">`); n != 20000 {
+		t.Errorf("expected 20000 links, got %d", n)
+	}
+}
@@ -0,0 +1,62 @@
+package htmlcleaner
+
+import (
+	"bytes"
+)
+
+// maxRetainedBuffer is the largest render buffer a Cleaner keeps between
+// calls, so that one huge fragment does not pin its memory forever.
+const maxRetainedBuffer = 1 << 20
+
+// Cleaner cleans many fragments with the same Config, reusing the memory
+// used for rendering and for the state of each cleaning operation instead
+// of allocating it again for every fragment. This reduces garbage
+// collection in batch jobs.
+//
+// A Cleaner is not safe for concurrent use; use one per goroutine. The
+// strings returned by Clean do not refer to the Cleaner's memory, so they
+// remain valid after later calls. The Config must not be modified while
+// the Cleaner is in use.
+type Cleaner struct {
+	state *cleaner
+	buf   bytes.Buffer
+}
+
+// NewCleaner returns a Cleaner that uses the specified Config, or the
+// FallbackConfig if it is nil.
+func NewCleaner(c *Config) *Cleaner {
+	return &Cleaner{state: newCleaner(c)}
+}
+
+// Clean is equivalent to calling the Clean function with the Cleaner's
+// Config.
+func (cl *Cleaner) Clean(fragment string) string {
+	cl.state.reset()
+
+	output := cl.state.clean(fragment, &cl.buf)
+
+	if cl.buf.Cap() > maxRetainedBuffer {
+		cl.buf = bytes.Buffer{}
+	}
+
+	return output
+}
+
+// reset prepares a cleaner for another fragment, keeping its allocations.
+func (c *cleaner) reset() {
+	elemCount := c.elemCount
+	for k := range elemCount {
+		delete(elemCount, k)
+	}
+
+	*c = cleaner{
+		Config:    c.Config,
+		rules:     c.rules,
+		stack:     c.stack[:0],
+		elemCount: elemCount,
+	}
+
+	if c.RuleStats != nil {
+		c.counts = &RuleCounts{}
+	}
+}
@@ -0,0 +1,40 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html/atom"
+)
+
+func TestCleaner(t *testing.T) {
+	c := DefaultConfig.Clone()
+	c.MaxElemAtom(atom.B, 1)
+	c.RuleStats = &RuleStats{}
+
+	cl := NewCleaner(c)
+	inputs := []string{
+		`<b>a</b><b>b</b><script>c</script>`,
+		`<b>d</b><a href="javascript:e()">f</a>`,
+		strings.Repeat("<i>g</i>", maxRetainedBuffer/8+1),
+		`<b>h</b>`,
+	}
+
+	var outputs []string
+	for _, input := range inputs {
+		outputs = append(outputs, cl.Clean(input))
+	}
+
+	for i, input := range inputs {
+		if expected := Clean(c, input); outputs[i] != expected {
+			t.Errorf("input %d: expected %q, actual %q", i, expected, outputs[i])
+		}
+	}
+
+	if counts := c.RuleStats.Counts(); counts.Elems["b"] != 6 || counts.EscapedElems["script"] != 2 {
+		t.Errorf("unexpected counts %+v", counts)
+	}
+	if cl.buf.Cap() > maxRetainedBuffer {
+		t.Errorf("kept a buffer of %d bytes", cl.buf.Cap())
+	}
+}
@@ -0,0 +1,70 @@
+package htmlcleaner
+
+import (
+	"regexp"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	dirValue = regexp.MustCompile(`\A(?i:ltr|rtl|auto)\z`)
+
+	// langTag checks the syntax of BCP 47 language tags, such as "en",
+	// "zh-Hant-TW", or "x-klingon", without checking the registry.
+	langTag = regexp.MustCompile(`\A(?:[a-zA-Z]{2,8}|[xXiI])(?:-[a-zA-Z0-9]{1,8})*\z`)
+)
+
+// validGlobalValue checks the values of attributes that have the same
+// meaning on every element, such as dir and lang, wherever they are
+// allowed.
+func validGlobalValue(a atom.Atom, val string) bool {
+	switch a {
+	case atom.Dir:
+		return dirValue.MatchString(val)
+	case atom.Lang:
+		// lang="" means the language is unknown.
+		return val == "" || langTag.MatchString(val)
+	}
+	return true
+}
+
+// autoDirElems is the set of elements that AutoDir applies to: the block
+// elements that usually hold text.
+var autoDirElems = map[atom.Atom]bool{
+	atom.Address:    true,
+	atom.Article:    true,
+	atom.Aside:      true,
+	atom.Blockquote: true,
+	atom.Dd:         true,
+	atom.Details:    true,
+	atom.Div:        true,
+	atom.Dt:         true,
+	atom.Figcaption: true,
+	atom.H1:         true,
+	atom.H2:         true,
+	atom.H3:         true,
+	atom.H4:         true,
+	atom.H5:         true,
+	atom.H6:         true,
+	atom.Li:         true,
+	atom.P:          true,
+	atom.Section:    true,
+	atom.Summary:    true,
+}
+
+// addAutoDir adds dir="auto" to the elements in autoDirElems that have no
+// dir attribute.
+func addAutoDir(nodes []*html.Node) {
+	for _, n := range nodes {
+		Walk(n, func(n *html.Node) WalkAction {
+			if n.Type != html.ElementNode || !autoDirElems[n.DataAtom] {
+				return WalkContinue
+			}
+			if _, ok := getAttr(n, "dir"); !ok {
+				n.Attr = append(n.Attr, html.Attribute{Key: "dir", Val: "auto"})
+			}
+			return WalkContinue
+		})
+	}
+}
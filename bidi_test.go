@@ -0,0 +1,41 @@
+package htmlcleaner
+
+import (
+	"testing"
+
+	"golang.org/x/net/html/atom"
+)
+
+var bidiConfig = DefaultConfig.Clone().GlobalAttrAtom(atom.Dir).GlobalAttrAtom(atom.Lang)
+
+var autoDirConfig = func() *Config {
+	c := DefaultConfig.Clone().SetWrapText(true).ElemAtom(atom.Ul, atom.Li)
+
+	c.AutoDir = true
+
+	return c
+}()
+
+var testTableBidi = []testTable{
+	{"Dir", `<p dir="rtl">a</p><p dir="LTR">b</p><b dir="auto">c</b>`, `<p dir="rtl">a</p><p dir="LTR">b</p><b dir="auto">c</b>`, bidiConfig},
+	{"BadDir", `<p dir="up">a</p><p dir="">b</p>`, `<p>a</p><p>b</p>`, bidiConfig},
+	{"Lang", `<p lang="en">a</p><q lang="zh-Hant-TW">b</q><i lang="x-klingon">c</i><i lang="">d</i>`, `<p lang="en">a</p><q lang="zh-Hant-TW">b</q><i lang="x-klingon">c</i><i lang="">d</i>`, bidiConfig},
+	{"BadLang", `<p lang="e">a</p><p lang="en_US">b</p><p lang="en-">c</p><p lang="abcdefghi">d</p>`, `<p>a</p><p>b</p><p>c</p><p>d</p>`, bidiConfig},
+	{"NotAllowed", `<p dir="rtl" lang="ar">a</p>`, `<p>a</p>`, nil},
+	{"AutoDir", `a<blockquote>b</blockquote><ul><li>c</li></ul>`, `<p dir="auto">a</p><blockquote dir="auto">b</blockquote><ul><li dir="auto">c</li></ul>`, autoDirConfig},
+	{"AutoDirKept", `<p dir="rtl">a</p>`, `<p dir="rtl">a</p>`, func() *Config {
+		c := bidiConfig.Clone()
+		c.AutoDir = true
+		return c
+	}()},
+}
+
+func TestBidi(t *testing.T) {
+	doTableTest(Clean, t, testTableBidi)
+
+	for _, tt := range testTableBidi {
+		if err := tt.Config.CheckOutput(tt.Output); err != nil {
+			t.Errorf("%s: %v", tt.Name, err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package htmlcleaner
+
+import "golang.org/x/net/html"
+
+// omittedMarker replaces the nodes removed by MaxChildren and MaxNodes, like
+// the nodes below the maximum depth in ParseDepth.
+const omittedMarker = "[omitted]"
+
+// limitBreadth applies MaxChildren and MaxNodes to a list of sibling nodes
+// before they are cleaned, so that very wide trees do not take time in
+// proportion to their size.
+func (c *cleaner) limitBreadth(nodes []*html.Node) []*html.Node {
+	if c.MaxChildren <= 0 && c.MaxNodes <= 0 {
+		return nodes
+	}
+
+	for i, n := range nodes {
+		if c.tooBroad(i) {
+			return append(nodes[:i:i], text(omittedMarker))
+		}
+		c.nodeCount++
+		c.limitChildren(n)
+	}
+	return nodes
+}
+
+func (c *cleaner) limitChildren(n *html.Node) {
+	i := 0
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if c.tooBroad(i) {
+			for child.NextSibling != nil {
+				n.RemoveChild(child.NextSibling)
+			}
+			n.InsertBefore(text(omittedMarker), child)
+			n.RemoveChild(child)
+			return
+		}
+		c.nodeCount++
+		c.limitChildren(child)
+		i++
+	}
+}
+
+// tooBroad reports whether the node at index i of its parent is over
+// MaxChildren or MaxNodes.
+func (c *cleaner) tooBroad(i int) bool {
+	return (c.MaxChildren > 0 && i >= c.MaxChildren) || (c.MaxNodes > 0 && c.nodeCount >= c.MaxNodes)
+}
@@ -0,0 +1,41 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+)
+
+var maxChildrenConfig = func() *Config {
+	c := DefaultConfig.Clone().Elem("p", "b")
+
+	c.MaxChildren = 2
+
+	return c
+}()
+
+var maxNodesConfig = func() *Config {
+	c := DefaultConfig.Clone().Elem("p", "b")
+
+	c.MaxNodes = 3
+
+	return c
+}()
+
+var testTableBreadth = []testTable{
+	{"MaxChildren", `<p><b>1</b><b>2</b><b>3</b></p>`, `<p><b>1</b><b>2</b>[omitted]</p>`, maxChildrenConfig},
+	{"MaxChildrenTopLevel", `<p>1</p><p>2</p><p>3</p>`, `<p>1</p><p>2</p>[omitted]`, maxChildrenConfig},
+	{"MaxChildrenExact", `<p><b>1</b><b>2</b></p>`, `<p><b>1</b><b>2</b></p>`, maxChildrenConfig},
+	{"MaxNodes", `<p><b>1</b><b>2</b></p><p>3</p>`, `<p><b>1</b>[omitted]</p>[omitted]`, maxNodesConfig},
+	{"Unlimited", `<p><b>1</b><b>2</b><b>3</b></p>`, `<p><b>1</b><b>2</b><b>3</b></p>`, nil},
+}
+
+func TestBreadth(t *testing.T) {
+	doTableTest(Clean, t, testTableBreadth)
+
+	c := maxNodesConfig.Clone()
+	c.MaxNodes = 1000
+	output := Clean(c, strings.Repeat("<b>x</b>", 100000))
+	if n := strings.Count(output, "<b>"); n != 500 {
+		t.Errorf("expected 500 elements, got %d", n)
+	}
+}
@@ -0,0 +1,85 @@
+package htmlcleaner
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// A Budget limits how often optional, expensive checks such as
+// Config.CheckURL can run, so that cleaning degrades gracefully under load
+// instead of blocking. A Budget is usually shared between many calls, so it
+// must be safe for concurrent use.
+type Budget interface {
+	// Take reports whether one more check may run now.
+	Take() bool
+}
+
+// RateBudget returns a Budget that allows n checks per interval, refilled
+// continuously, with bursts of up to n checks.
+func RateBudget(n int, interval time.Duration) Budget {
+	return &rateBudget{
+		max:    float64(n),
+		tokens: float64(n),
+		rate:   float64(n) / float64(interval),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+type rateBudget struct {
+	mu     sync.Mutex
+	max    float64
+	tokens float64
+	rate   float64 // tokens per nanosecond
+	last   time.Time
+	now    func() time.Time
+}
+
+func (b *rateBudget) Take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens += float64(now.Sub(b.last)) * b.rate
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// checkURL runs CheckURL on the value of an allowed URL attribute, or on
+// the URLs in an allowed style or srcset attribute, if the Budget allows it, and
+// reports whether the attribute may be kept.
+func (c *cleaner) checkURL(attr *html.Attribute) bool {
+	a := atom.Lookup([]byte(attr.Key))
+	if c.CheckURL == nil || (!isURLAttr(a) && a != atom.Style && a != atom.Srcset) {
+		return true
+	}
+
+	if c.Budget != nil && !c.Budget.Take() {
+		c.skippedChecks++
+		return true
+	}
+
+	if a == atom.Style {
+		attr.Val = cleanStyleURLs(attr.Val, c.CheckURL)
+		return attr.Val != ""
+	}
+	if a == atom.Srcset {
+		attr.Val = cleanSrcset(attr.Val, c.CheckURL)
+		return attr.Val != ""
+	}
+
+	u, err := url.Parse(attr.Val)
+	return err == nil && c.CheckURL(u)
+}
@@ -0,0 +1,73 @@
+package htmlcleaner
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+type countBudget int
+
+func (b *countBudget) Take() bool {
+	if *b == 0 {
+		return false
+	}
+	*b--
+	return true
+}
+
+type attrTracer map[string]int
+
+func (t attrTracer) StartSpan(name string) Span         { return t }
+func (t attrTracer) SetAttribute(key string, value int) { t[key] = value }
+func (t attrTracer) End()                               {}
+
+func TestCheckURL(t *testing.T) {
+	budget := countBudget(2)
+	c := DefaultConfig.Clone()
+	c.CheckURL = func(u *url.URL) bool { return u.Host != "evil.example" }
+	c.Budget = &budget
+
+	attrs := make(attrTracer)
+	c.Tracer = attrs
+
+	const input = `<a href="https://evil.example/1">a</a><a href="https://ok.example/">b</a><a href="https://evil.example/2">c</a>`
+	const expected = `<a>a</a><a href="https://ok.example/">b</a><a href="https://evil.example/2">c</a>`
+	if out := Clean(c, input); out != expected {
+		t.Errorf("expected %q, got %q", expected, out)
+	}
+
+	if attrs["skipped_checks"] != 1 || attrs["partially_validated"] != 1 || attrs["rejected_urls"] != 1 {
+		t.Errorf("unexpected span attributes %v", attrs)
+	}
+
+	c.Budget = nil
+	if out := Clean(c, input); out != `<a>a</a><a href="https://ok.example/">b</a><a>c</a>` {
+		t.Errorf("unexpected output without budget %q", out)
+	}
+}
+
+func TestRateBudget(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := RateBudget(2, time.Second).(*rateBudget)
+	b.last = now
+	b.now = func() time.Time { return now }
+
+	for i, expected := range []bool{true, true, false} {
+		if b.Take() != expected {
+			t.Errorf("take %d: expected %v", i, expected)
+		}
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if !b.Take() || b.Take() {
+		t.Error("expected one check after half an interval")
+	}
+
+	now = now.Add(time.Hour)
+	for i, expected := range []bool{true, true, false} {
+		if b.Take() != expected {
+			t.Errorf("take %d after refill: expected %v", i, expected)
+		}
+	}
+}
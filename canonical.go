@@ -0,0 +1,117 @@
+package htmlcleaner
+
+import (
+	"crypto/sha256"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// RenderCanonical is like Render, but produces the same output for
+// equivalent trees: attributes are sorted, element and attribute names are
+// lowercased, and duplicate attributes are removed. Quoting and character
+// references are already normalized by Render. The nodes are not modified.
+func RenderCanonical(nodes ...*html.Node) string {
+	nodes = deepCopyAll(nodes)
+	for _, n := range nodes {
+		canonicalize(n)
+	}
+	return Render(nodes...)
+}
+
+// Canonical cleans a fragment of HTML using the specified Config, or the
+// FallbackConfig if it is nil, and returns it in a canonical form: rendered by
+// RenderCanonical, with adjacent text merged and whitespace collapsed as if
+// CollapseWhitespace were set. Fragments with the same canonical form look
+// the same, so it is suitable for hashing, comparing, caching, and diffing
+// cleaned content. Attributes added by NodeIDPrefix are not included. The
+// canonical form may change between versions of this package.
+func Canonical(c *Config, fragment string) string {
+	if c == nil {
+		c = FallbackConfig()
+	}
+
+	nodes := cleanFragment(c, fragment)
+	if c.NodeIDPrefix != "" {
+		removeNodeIDs(nodes)
+	}
+
+	parent := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range nodes {
+		parent.AppendChild(n)
+	}
+	mergeText(parent)
+	nodes = nodes[:0]
+	for parent.FirstChild != nil {
+		n := parent.FirstChild
+		parent.RemoveChild(n)
+		nodes = append(nodes, n)
+	}
+
+	return RenderCanonical(collapseWhitespace(c, nodes)...)
+}
+
+// Fingerprint returns the SHA-256 hash of the canonical form of a fragment
+// of HTML, as returned by Canonical, so fragments that look the same have
+// the same fingerprint. It is suitable for detecting duplicate posts and as
+// a cache key, but like the canonical form, it may change between versions
+// of this package.
+func Fingerprint(c *Config, fragment string) [32]byte {
+	return sha256.Sum256([]byte(Canonical(c, fragment)))
+}
+
+// mergeText joins adjacent text nodes inside n, so that whitespace at their
+// boundary is collapsed.
+func mergeText(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		for c.Type == html.TextNode && c.NextSibling != nil && c.NextSibling.Type == html.TextNode {
+			c.Data += c.NextSibling.Data
+			n.RemoveChild(c.NextSibling)
+		}
+		mergeText(c)
+	}
+}
+
+func canonicalize(n *html.Node) {
+	Walk(n, func(n *html.Node) WalkAction {
+		if n.Type != html.ElementNode {
+			return WalkContinue
+		}
+
+		if n.Namespace == "" {
+			n.Data = strings.ToLower(n.Data)
+			n.DataAtom = atom.Lookup([]byte(n.Data))
+		}
+
+		for i := range n.Attr {
+			n.Attr[i].Namespace = strings.ToLower(n.Attr[i].Namespace)
+			n.Attr[i].Key = strings.ToLower(n.Attr[i].Key)
+		}
+
+		sort.Stable(attrsByName(n.Attr))
+
+		attrs := n.Attr[:0]
+		for i, a := range n.Attr {
+			if i != 0 && a.Namespace == n.Attr[i-1].Namespace && a.Key == n.Attr[i-1].Key {
+				continue
+			}
+			attrs = append(attrs, a)
+		}
+		n.Attr = attrs
+
+		return WalkContinue
+	})
+}
+
+type attrsByName []html.Attribute
+
+func (a attrsByName) Len() int      { return len(a) }
+func (a attrsByName) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a attrsByName) Less(i, j int) bool {
+	if a[i].Namespace != a[j].Namespace {
+		return a[i].Namespace < a[j].Namespace
+	}
+	return a[i].Key < a[j].Key
+}
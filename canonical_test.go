@@ -0,0 +1,67 @@
+package htmlcleaner
+
+import (
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+var testTableRenderCanonical = []testTable{
+	{"Empty", ``, ``, nil},
+	{"SortAttributes", `<a title='x' href=y>z</a>`, `<a href="y" title="x">z</a>`, nil},
+	{"SameOrder", `<a href="y" title="x">z</a>`, `<a href="y" title="x">z</a>`, nil},
+	{"Entities", `&lt;&#60;&#x3c;&quot;&nbsp;`, "&lt;&lt;&lt;&#34;\u00a0", nil},
+}
+
+func TestRenderCanonical(t *testing.T) {
+	doTableTest(func(c *Config, s string) string {
+		return RenderCanonical(Parse(s)...)
+	}, t, testTableRenderCanonical)
+}
+
+func TestRenderCanonicalConstructed(t *testing.T) {
+	n := &html.Node{
+		Type: html.ElementNode,
+		Data: "A",
+		Attr: []html.Attribute{
+			{Key: "TITLE", Val: "x"},
+			{Key: "href", Val: "y"},
+			{Key: "title", Val: "z"},
+		},
+	}
+
+	if expected, actual := `<a href="y" title="x"></a>`, RenderCanonical(n); expected != actual {
+		t.Errorf("expected %q, actual %q", expected, actual)
+	}
+	if n.Data != "A" || n.DataAtom != 0 || n.Attr[0].Key != "TITLE" {
+		t.Errorf("RenderCanonical modified its input")
+	}
+}
+
+var testTableCanonical = []testTable{
+	{"Cleaned", `<p title='x'>a<script>b</script></p>`, `<p title="x">a&lt;script&gt;b&lt;/script&gt;</p>`, nil},
+	{"Whitespace", "<p>\n  a \t b\n</p>", `<p>a b</p>`, nil},
+	{"MergedText", `<p>a <span> b</span></p>`, `<p>a b</p>`, (&Config{}).Elem("p").Unwrap("span")},
+	{"Pre", "<pre>  a\n  b</pre>", "<pre>  a\n  b</pre>", nil},
+	{"Entities", `<b title="&#x22;">&#60;</b>`, `<b title="&#34;">&lt;</b>`, nil},
+}
+
+func TestCanonical(t *testing.T) {
+	doTableTest(Canonical, t, testTableCanonical)
+
+	if Canonical(nil, `<b title="a" title="b">x  y</b>`) != Canonical(nil, "<B TITLE=a>x\ny</B>") {
+		t.Error("expected the same canonical form")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	if Fingerprint(nil, `<b title="a" lang="en">x  y</b>`) != Fingerprint(nil, "<B LANG=en TITLE=a>x\ny</B>") {
+		t.Error("expected the same fingerprint")
+	}
+	if Fingerprint(nil, `<b>x y</b>`) == Fingerprint(nil, `<i>x y</i>`) {
+		t.Error("expected different fingerprints")
+	}
+	if Fingerprint(nil, `x<script>y</script>`) != Fingerprint(nil, `x&lt;script&gt;y&lt;/script&gt;`) {
+		t.Error("expected the fingerprint of the cleaned HTML")
+	}
+}
@@ -0,0 +1,69 @@
+package htmlcleaner
+
+import (
+	"bytes"
+	"regexp"
+
+	"golang.org/x/net/html"
+)
+
+// CharRefAction is what happens to character references beyond
+// MaxCharRefs. See Config.CharRefAction.
+type CharRefAction int
+
+const (
+	// CharRefLiteral keeps the extra references as literal text, so
+	// "&amp;" is shown as "&amp;" instead of "&".
+	CharRefLiteral CharRefAction = iota
+
+	// CharRefDrop removes the extra references.
+	CharRefDrop
+
+	// CharRefReject makes Clean return the whole fragment as escaped
+	// text.
+	CharRefReject
+)
+
+var charRef = regexp.MustCompile(`&(?:#[0-9]+;?|#[xX][0-9a-fA-F]+;?|[A-Za-z][A-Za-z0-9]*;?)`)
+
+// limitCharRefs applies MaxCharRefs and MaxCharRefLength to a fragment
+// before it is parsed. It reports false if the fragment is rejected.
+func (c *cleaner) limitCharRefs(fragment string) (string, bool) {
+	if c.MaxCharRefs <= 0 && c.MaxCharRefLength <= 0 {
+		return fragment, true
+	}
+
+	var buf bytes.Buffer
+	count, last := 0, 0
+	for _, loc := range charRef.FindAllStringIndex(fragment, -1) {
+		ref := fragment[loc[0]:loc[1]]
+		if html.UnescapeString(ref) == ref {
+			// not a reference the parser would expand
+			continue
+		}
+
+		count++
+		if (c.MaxCharRefs <= 0 || count <= c.MaxCharRefs) && (c.MaxCharRefLength <= 0 || len(ref) <= c.MaxCharRefLength) {
+			continue
+		}
+
+		c.limitedCharRefs++
+		switch c.CharRefAction {
+		case CharRefReject:
+			return "", false
+		case CharRefDrop:
+			buf.WriteString(fragment[last:loc[0]])
+		default:
+			buf.WriteString(fragment[last:loc[0]])
+			buf.WriteString("&amp;")
+			buf.WriteString(ref[1:])
+		}
+		last = loc[1]
+	}
+
+	if last == 0 {
+		return fragment, true
+	}
+	buf.WriteString(fragment[last:])
+	return buf.String(), true
+}
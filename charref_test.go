@@ -0,0 +1,71 @@
+package htmlcleaner
+
+import "testing"
+
+var charRefLiteralConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.MaxCharRefs = 2
+	c.CharRefAction = CharRefLiteral
+
+	return c
+}()
+
+var charRefDropConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.MaxCharRefs = 2
+	c.CharRefAction = CharRefDrop
+
+	return c
+}()
+
+var charRefRejectConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.MaxCharRefs = 2
+	c.CharRefAction = CharRefReject
+
+	return c
+}()
+
+var charRefLengthConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.MaxCharRefLength = 8
+	c.CharRefAction = CharRefLiteral
+
+	return c
+}()
+
+var charRefDropOneConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.MaxCharRefs = 1
+	c.CharRefAction = CharRefDrop
+
+	return c
+}()
+
+var charRefLiteralOneConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.MaxCharRefs = 1
+	c.CharRefAction = CharRefLiteral
+
+	return c
+}()
+
+var testTableCharRefs = []testTable{
+	{"UnderLimit", `&lt;&#65;`, `&lt;A`, charRefLiteralConfig},
+	{"Literal", `&lt;&#65;&#x42;`, `&lt;A&amp;#x42;`, charRefLiteralConfig},
+	{"Drop", `a&lt;b&#65;c&#x42;d`, `a&lt;bAcd`, charRefDropConfig},
+	{"Reject", `<b>&lt;&#65;&#x42;</b>`, `&lt;b&gt;&amp;lt;&amp;#65;&amp;#x42;&lt;/b&gt;`, charRefRejectConfig},
+	{"Length", `&#65;&#0000000065;`, `A&amp;#0000000065;`, charRefLengthConfig},
+	{"NotReferences", `&foo; & &#;`, `&amp;foo; &amp; &amp;#;`, charRefDropOneConfig},
+	{"Attribute", `<a href="?a=1&amp;b=2&amp;c=3">x</a>`, `<a href="?a=1&amp;b=2&amp;amp;c=3">x</a>`, charRefLiteralOneConfig},
+}
+
+func TestCharRefLimits(t *testing.T) {
+	doTableTest(Clean, t, testTableCharRefs)
+}
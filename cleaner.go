@@ -14,13 +14,32 @@ import (
 // Parse.
 const DefaultMaxDepth = 100
 
+// DefaultMaxURLLength and DefaultMaxAttrLength are the limits on attribute
+// values used by DefaultConfig.
+const (
+	DefaultMaxURLLength  = 2048
+	DefaultMaxAttrLength = 8192
+)
+
 // Preprocess escapes disallowed tags in a cleaner way, but does not fix
 // nesting problems. Use with Clean.
-func Preprocess(config *Config, fragment string) string {
+func Preprocess(config *Config, fragment string) (output string) {
 	if config == nil {
-		config = DefaultConfig
+		config = FallbackConfig()
+	}
+
+	fragment, _ = config.limitInput(fragment)
+
+	if s, ok := config.sniff(fragment); ok {
+		return s
 	}
 
+	span := config.startSpan("htmlcleaner.Preprocess")
+	defer span.End()
+	defer config.recoverString(&output, fragment, span)
+
+	escaped := 0
+
 	var buf bytes.Buffer
 	write := func(raw string) {
 		_, err := buf.WriteString(raw)
@@ -29,9 +48,27 @@ func Preprocess(config *Config, fragment string) string {
 		expectError(err, nil)
 	}
 
-	t := html.NewTokenizer(strings.NewReader(fragment))
+	t := html.NewTokenizer(strings.NewReader(config.normalizeNewlines(fragment)))
+	rawText := false
 	for {
-		switch tok := t.Next(); tok {
+		tok := t.Next()
+
+		// whether this token is the contents of a disallowed raw text
+		// element
+		inRawText := rawText
+		rawText = false
+
+		var token html.Token
+		haveToken := tok != html.ErrorToken && (len(config.tokenFilters) != 0 || config.PreprocessAttrs != PreprocessAttrKeep)
+		if haveToken {
+			token = t.Token()
+			if replacement, ok := config.filterToken(token); ok {
+				write(replacement)
+				continue
+			}
+		}
+
+		switch tok {
 		case html.ErrorToken:
 			err := t.Err()
 
@@ -43,13 +80,28 @@ func Preprocess(config *Config, fragment string) string {
 
 			if err == io.EOF {
 				write(html.EscapeString(string(t.Raw())))
+
+				span.SetAttribute("input_bytes", len(fragment))
+				span.SetAttribute("output_bytes", buf.Len())
+				span.SetAttribute("escaped_tags", escaped)
+
 				return buf.String()
 			}
 		case html.TextToken:
-			write(string(t.Raw()))
+			raw := string(t.Raw())
+			if inRawText {
+				raw = config.rawTextContent(raw)
+			}
+			write(raw)
 		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
 			raw := string(t.Raw())
-			tagName, _ := t.TagName()
+			var tagName []byte
+			if haveToken {
+				// t.Token has already read the tag name
+				tagName = []byte(token.Data)
+			} else {
+				tagName, _ = t.TagName()
+			}
 			allowed := false
 			if tag := atom.Lookup(tagName); tag != 0 {
 				if _, ok := config.elem[tag]; ok {
@@ -61,13 +113,30 @@ func Preprocess(config *Config, fragment string) string {
 					allowed = true
 				}
 			}
-			if !allowed {
+			if !allowed && config.mayAllowSelector(string(tagName)) {
+				allowed = true
+			}
+			if !allowed && tok == html.StartTagToken && tokenizerRawText(string(tagName)) {
+				rawText = true
+			}
+			if !allowed && config.unwrapped(atom.Lookup(tagName), string(tagName)) {
+				raw = ""
+			} else if !allowed {
 				raw = html.EscapeString(raw)
+				escaped++
+			} else if tok != html.EndTagToken && config.PreprocessAttrs != PreprocessAttrKeep {
+				var escapedTag bool
+				raw, escapedTag = config.preprocessAttrs(token, raw)
+				if escapedTag {
+					escaped++
+				}
 			}
 			write(raw)
 		case html.CommentToken:
 			raw := string(t.Raw())
-			if config.EscapeComments || !strings.HasPrefix(raw, "<!--") || !strings.HasSuffix(raw, "-->") {
+			if config.RemoveComments {
+				raw = ""
+			} else if config.EscapeComments || !strings.HasPrefix(raw, "<!--") || !strings.HasSuffix(raw, "-->") {
 				raw = html.EscapeString(raw)
 			}
 			write(raw)
@@ -85,6 +154,13 @@ func Parse(fragment string) []*html.Node {
 // ParseDepth is a convenience function that wraps html.ParseFragment but takes
 // a string instead of an io.Reader and omits deep trees.
 func ParseDepth(fragment string, maxDepth int) []*html.Node {
+	nodes, _ := parseDepth(fragment, maxDepth)
+	return nodes
+}
+
+// parseDepth is ParseDepth, and also reports whether any nodes were
+// omitted.
+func parseDepth(fragment string, maxDepth int) (nodes []*html.Node, omitted bool) {
 	nodes, err := html.ParseFragment(strings.NewReader(fragment), &html.Node{
 		Type:     html.ElementNode,
 		Data:     "div",
@@ -94,11 +170,13 @@ func ParseDepth(fragment string, maxDepth int) []*html.Node {
 
 	if maxDepth > 0 {
 		for _, n := range nodes {
-			forceMaxDepth(n, maxDepth)
+			if forceMaxDepth(n, maxDepth) {
+				omitted = true
+			}
 		}
 	}
 
-	return nodes
+	return nodes, omitted
 }
 
 // Render is a convenience function that wraps html.Render and renders to a
@@ -114,10 +192,85 @@ func Render(nodes ...*html.Node) string {
 	return string(buf.Bytes())
 }
 
-// Clean a fragment of HTML using the specified Config, or the DefaultConfig
+// renderBuffer is Render, using buf if it is not nil.
+func renderBuffer(buf *bytes.Buffer, nodes ...*html.Node) string {
+	if buf == nil {
+		return Render(nodes...)
+	}
+
+	buf.Reset()
+	for _, n := range nodes {
+		err := html.Render(buf, n)
+		expectError(err, nil)
+	}
+
+	return buf.String()
+}
+
+// Clean a fragment of HTML using the specified Config, or the FallbackConfig
 // if it is nil.
 func Clean(c *Config, fragment string) string {
-	return Render(CleanNodes(c, Parse(fragment))...)
+	return newCleaner(c).clean(fragment, nil)
+}
+
+// cleanFragment is Clean, returning the parsed output, for the functions
+// that convert cleaned content to another form. Like Clean, it applies the
+// input limits, NormalizeNewlines, SniffHandler, and Verify.
+func cleanFragment(c *Config, fragment string) []*html.Node {
+	return Parse(Clean(c, fragment))
+}
+
+// clean is Clean, rendering into buf if it is not nil.
+func (cl *cleaner) clean(fragment string, buf *bytes.Buffer) (output string) {
+	fragment, cl.inputExceeded = cl.limitInput(fragment)
+	if cl.inputExceeded {
+		cl.explainNote("input is longer than MaxInputBytes, so it was %s", limitVerb(cl.InputAction == InputAbort))
+	}
+
+	if s, ok := cl.sniff(fragment); ok {
+		cl.explainNote("input looks like %s, so it was passed to SniffHandler", SniffContent(fragment))
+		return s
+	}
+
+	span := cl.startSpan("htmlcleaner.Clean")
+	defer span.End()
+	defer cl.recoverString(&output, fragment, span)
+
+	if limited, ok := cl.limitCharRefs(cl.normalizeNewlines(fragment)); ok {
+		if cl.limitedCharRefs != 0 {
+			cl.explainNote("character references over MaxCharRefs or MaxCharRefLength: %d", cl.limitedCharRefs)
+		}
+		output = renderBuffer(buf, cl.limitExpansion(cleanNodes(cl, cl.parse(limited)), len(fragment))...)
+		if cl.expansionExceeded != 0 {
+			cl.explainNote("output is longer than MaxExpansion allows, so it was %s", limitVerb(cl.ExpansionAction == ExpansionAbort))
+		}
+	} else {
+		cl.explainNote("input has character references over MaxCharRefs or MaxCharRefLength, so it was escaped")
+		output = html.EscapeString(fragment)
+	}
+	if !cl.verify(output) {
+		cl.explainNote("output failed Verify, so it was escaped")
+		output = html.EscapeString(output)
+	}
+	output = cl.scrubString(output)
+	output = cl.encodeEntities(output)
+
+	span.SetAttribute("input_bytes", len(fragment))
+	span.SetAttribute("output_bytes", len(output))
+	cl.setRuleAttributes(span)
+	cl.RuleStats.add(cl.counts)
+
+	cl.shadow(fragment, output)
+
+	return output
+}
+
+// needsSrc is the set of elements that are removed if they have no src or
+// srcset attribute.
+var needsSrc = map[atom.Atom]bool{
+	atom.Img:    true,
+	atom.Source: true,
+	atom.Track:  true,
 }
 
 var isBlockElement = map[atom.Atom]bool{
@@ -162,9 +315,91 @@ var isBlockElement = map[atom.Atom]bool{
 }
 
 // CleanNodes calls CleanNode on each node, and additionally wraps inline
-// elements in <p> tags and wraps dangling <li> tags in <ul> tags.
-func CleanNodes(c *Config, nodes []*html.Node) []*html.Node {
-	return cleanNodes(c, deepCopyAll(nodes))
+// elements in <p> tags, wraps dangling <li> tags in <ul> tags, and applies
+// the options that affect whole fragments, such as CollapseWhitespace.
+func CleanNodes(c *Config, nodes []*html.Node) (cleaned []*html.Node) {
+	cl := newCleaner(c)
+
+	span := cl.startSpan("htmlcleaner.CleanNodes")
+	defer span.End()
+	defer cl.recoverNodes(&cleaned, nodes, span)
+
+	cleaned = cleanNodes(cl, deepCopyAll(nodes))
+
+	if cl.Verify != nil {
+		if output := Render(cleaned...); !cl.verify(output) {
+			cleaned = []*html.Node{text(output)}
+		}
+	}
+
+	if cl.ControlChars != ControlKeep {
+		cl.scrubNodes(cleaned)
+	}
+
+	cl.setRuleAttributes(span)
+	cl.RuleStats.add(cl.counts)
+
+	return cleaned
+}
+
+// cleaner holds the state of a single cleaning operation.
+type cleaner struct {
+	*Config
+
+	// text replacement rules, including those enabled by AutoLink
+	rules []textRule
+
+	// the allowed elements enclosing the node being cleaned
+	stack []*html.Node
+
+	// the number of input elements enclosing the node being cleaned
+	depth int
+
+	// the number of each element seen so far, for MaxElem
+	elemCount map[string]int
+
+	// the number of nodes seen so far, for MaxNodes
+	nodeCount int
+
+	// rule hit counts, reported to the Tracer
+	escapedElems      int
+	removedAttrs      int
+	rejectedURLs      int
+	skippedChecks     int
+	lowContrast       int
+	limitedCharRefs   int
+	expansionExceeded int
+	regexpEvals       int
+
+	// whether Parse omitted deeply nested nodes, and whether the input
+	// was longer than MaxInputBytes, for CleanE
+	tooDeep       bool
+	inputExceeded bool
+
+	// detailed rule hit counts, if RuleStats is set
+	counts *RuleCounts
+
+	// a description of each decision, for Explain
+	explain *bytes.Buffer
+
+	// the Configs whose Shadow is being cleaned with this one
+	shadowed map[*Config]bool
+}
+
+func newCleaner(c *Config) *cleaner {
+	if c == nil {
+		c = FallbackConfig()
+	}
+
+	cl := &cleaner{Config: c, rules: c.textRules}
+	if c.AutoLink && c.allowsLinks() {
+		cl.rules = append(cl.rules[:len(cl.rules):len(cl.rules)], autoLinkRules(c)...)
+	}
+	if c.RuleStats != nil {
+		cl.counts = &RuleCounts{}
+	}
+
+	return cl
 }
 
 func deepCopyAll(nodes []*html.Node) []*html.Node {
@@ -190,28 +425,80 @@ func deepCopy(n *html.Node) *html.Node {
 	return clone
 }
 
-func cleanNodes(c *Config, nodes []*html.Node) []*html.Node {
-	if c == nil {
-		c = DefaultConfig
+func cleanNodes(c *cleaner, nodes []*html.Node) []*html.Node {
+	nodes = c.limitBreadth(nodes)
+
+	filtered := make([]*html.Node, 0, len(nodes))
+	for _, n := range nodes {
+		filtered = appendFiltered(filtered, filterNode(c, n))
 	}
+	nodes = filtered
 
 	for i, n := range nodes {
-		nodes[i] = filterNode(c, n)
-		if nodes[i].DataAtom == atom.Li {
+		if n.DataAtom == atom.Li {
 			wrapper := &html.Node{
 				Type:     html.ElementNode,
 				Data:     "ul",
 				DataAtom: atom.Ul,
 			}
-			wrapper.AppendChild(nodes[i])
+			wrapper.AppendChild(n)
 			nodes[i] = wrapper
 		}
 	}
 
+	if c.needsTextNormalization() {
+		normalizeText(c.Config, nodes)
+	}
+
+	if len(c.rules) != 0 {
+		nodes = applyTextRules(c, nodes)
+	}
+
+	if c.SmartPunctuation {
+		smartPunctuation(nodes)
+	}
+
+	if c.TextFilter != nil {
+		filterText(c.Config, nodes)
+	}
+
+	if c.BreakLongWords > 0 || c.MaxWordLength > 0 {
+		nodes = breakLongWords(c.Config, nodes)
+	}
+
+	if c.Gallery != nil {
+		nodes = groupImages(c.Config, nodes)
+	}
+
 	if c.WrapText {
 		nodes = wrapText(nodes)
 	}
 
+	if c.AutoDir {
+		addAutoDir(nodes)
+	}
+
+	if c.NBSP == NBSPSpace {
+		nodes = normalizeNBSP(c.Config, nodes)
+	}
+
+	if c.CollapseWhitespace {
+		nodes = collapseWhitespace(c.Config, nodes)
+	}
+
+	if c.RemoveEmpty {
+		nodes = removeEmpty(c.Config, nodes)
+	}
+
+	// after CollapseWhitespace and RemoveEmpty so the spaces are kept
+	if c.NBSP == NBSPSpan {
+		nodes = normalizeNBSP(c.Config, nodes)
+	}
+
+	if c.NodeIDPrefix != "" {
+		addNodeIDs(c.NodeIDPrefix, nodes)
+	}
+
 	return nodes
 }
 
@@ -258,75 +545,225 @@ func text(s string) *html.Node {
 // their attributes checked for legality as well. Elements with illegal
 // attributes are copied and the problematic attributes are removed. Elements
 // that are not in the set of legal elements are replaced with a textual
-// version of their source code.
+// version of their source code, or, if they were passed to Unwrap, with a
+// document node holding their cleaned children.
 func CleanNode(c *Config, n *html.Node) *html.Node {
-	if c == nil {
-		c = DefaultConfig
-	}
-	return filterNode(c, deepCopy(n))
+	cl := newCleaner(c)
+	n = filterNode(cl, cl.limitBreadth([]*html.Node{deepCopy(n)})[0])
+	cl.RuleStats.add(cl.counts)
+	return n
 }
 
-func filterNode(c *Config, n *html.Node) *html.Node {
+func filterNode(c *cleaner, n *html.Node) *html.Node {
 	if n.Type == html.TextNode {
 		return n
 	}
+	if n.Type == html.CommentNode && c.RemoveComments {
+		c.explainf(n, "removed: RemoveComments")
+		return &html.Node{Type: html.DocumentNode}
+	}
 	if n.Type == html.CommentNode && !c.EscapeComments {
 		return n
 	}
 	if n.Type != html.ElementNode {
+		c.explainf(n, "escaped")
 		return text(Render(n))
 	}
 	return cleanNode(c, n)
 }
 
-func cleanNode(c *Config, n *html.Node) *html.Node {
-	allowedAttr, ok1 := c.elem[n.DataAtom]
-	customAttr, ok2 := c.elemCustom[n.Data]
-	if ok1 || ok2 {
+func cleanNode(c *cleaner, n *html.Node) *html.Node {
+	if name := n.Data; c.offsetHeading(n) {
+		c.explainf(n, "renamed from <%s> by HeadingOffset", name)
+	}
+
+	_, ok1 := c.elem[n.DataAtom]
+	_, ok2 := c.elemCustom[n.Data]
+	allowed, selectorAttrs := c.selectorAllows(n, ok1 || ok2)
+	if allowed {
+		if reason := c.nestingViolation(n); reason != "" {
+			if c.NestingAction == NestingUnwrap {
+				c.explainf(n, "unwrapped: %s", reason)
+				return unwrapNode(c, n)
+			}
+			c.explainf(n, "escaped: %s", reason)
+			return escapeNode(c, n)
+		}
+
+		summarize := false
+		if c.quoteTooDeep(n) {
+			if c.QuoteSummary == "" {
+				c.explainf(n, "collapsed: nested more deeply than MaxQuoteDepth")
+				return collapseQuote(c, n)
+			}
+			summarize = true
+		}
+
+		if c.overLimit(n) {
+			c.explainf(n, "replaced with MaxElemPlaceholder: more than MaxElem")
+			return text(c.MaxElemPlaceholder)
+		}
+
+		code, highlighted := c.highlight(n)
+
+		c.stack = append(c.stack, n)
 		cleanChildren(c, n)
+		c.stack = c.stack[:len(c.stack)-1]
+
+		if c.truncateText(n) {
+			c.explainf(n, "truncated text: MaxTextLength")
+		}
 
 		haveSrc := false
 
 		attrs := n.Attr
 		n.Attr = make([]html.Attribute, 0, len(attrs))
 		for _, attr := range attrs {
-			a := atom.Lookup([]byte(attr.Key))
-
-			re1, ok1 := allowedAttr[a]
-			re2, ok2 := customAttr[attr.Key]
-			_, ok3 := c.attr[a]
-			_, ok4 := c.attrCustom[attr.Key]
+			c.codeLanguageClass(n, &attr)
 
-			if attr.Namespace != "" || (!ok1 && !ok2 && !ok3 && !ok4) {
-				continue
+			verdict := c.checkAttr(n.DataAtom, n.Data, &attr)
+			if verdict == attrNotAllowed && attr.Namespace == "" && containsString(selectorAttrs, attr.Key) {
+				verdict = c.checkAttrValue(n.Data, &attr)
 			}
-
-			if !cleanURL(c, a, &attr) {
-				continue
+			if verdict == attrOK && !c.checkURL(&attr) {
+				verdict = attrBadURL
+			}
+			if verdict == attrOK && !c.truncateAttrText(n.Data, &attr) {
+				verdict = attrTooLong
+			}
+			c.recordAttr(n, &attr, verdict)
+			if verdict != attrOK {
+				c.explainf(n, "removed attribute %s=%q: %s", attr.Key, attr.Val, attrReasons[verdict])
 			}
 
-			if re1 != nil && !re1.MatchString(attr.Val) {
+			switch verdict {
+			case attrNotAllowed, attrNoMatch, attrTooLong, attrUnsafe:
+				c.removedAttrs++
 				continue
-			}
-			if re2 != nil && !re2.MatchString(attr.Val) {
+			case attrBadURL:
+				c.removedAttrs++
+				c.rejectedURLs++
 				continue
 			}
 
-			haveSrc = haveSrc || a == atom.Src
+			haveSrc = haveSrc || attr.Key == "src" || attr.Key == "srcset"
 
 			n.Attr = append(n.Attr, attr)
 		}
 
-		if n.DataAtom == atom.Img && !haveSrc {
+		if c.forceAttrs(n) {
+			c.explainf(n, "changed attributes: SandboxMedia, ImageLoading, ImageDecoding, or ReferrerPolicy")
+		}
+
+		if c.addDimensions(n) {
+			c.explainf(n, "added width and height: ImageDimensions")
+		}
+
+		if c.checkIDN(n) {
+			c.explainf(n, "removed attribute href: internationalized domain name")
+		}
+
+		if c.removeUnknownColors(n) {
+			c.explainf(n, "removed colors: MinContrast cannot check colors it does not recognize")
+		}
+
+		if ratio, removed := c.checkContrast(n); removed {
+			c.explainf(n, "removed colors: contrast ratio %.2f is below MinContrast", ratio)
+		}
+
+		if needsSrc[n.DataAtom] && !haveSrc {
+			c.explainf(n, "removed: no src")
 			// replace it with an empty text node
 			return &html.Node{Type: html.TextNode}
 		}
 
+		if !c.checkAlt(n) {
+			return &html.Node{Type: html.TextNode}
+		}
+
+		if highlighted != nil {
+			c.explainf(n, "inserted output of HighlightCode")
+			insertHighlighted(n, code, highlighted)
+		}
+
+		c.recordElem(n)
+
+		if summarize {
+			c.explainf(n, "wrapped in <details>: nested more deeply than MaxQuoteDepth")
+			return summarizeQuote(c, n)
+		}
+
+		c.explainf(n, "kept")
+
 		return n
 	}
+	if legacyRawText[n.DataAtom] {
+		unwrap := c.unwrapped(n.DataAtom, n.Data)
+		if unwrap {
+			c.explainf(n, "unwrapped: not allowed; contents parsed as HTML")
+		} else {
+			c.explainf(n, "escaped tags: not allowed; contents parsed as HTML")
+		}
+		return reparseLegacy(c, n, unwrap)
+	}
+	if c.unwrapped(n.DataAtom, n.Data) {
+		c.explainf(n, "unwrapped: not allowed")
+		return unwrapNode(c, n)
+	}
+	c.explainf(n, "escaped: not allowed")
+	return escapeNode(c, n)
+}
+
+// escapeNode replaces n with a textual version of its source code.
+func escapeNode(c *cleaner, n *html.Node) *html.Node {
+	c.escapedElems++
+	if c.counts != nil {
+		count(&c.counts.EscapedElems, n.Data, 1)
+	}
 	return text(html.UnescapeString(Render(n)))
 }
 
+// unwrapNode replaces n with a document node holding its cleaned children.
+func unwrapNode(c *cleaner, n *html.Node) *html.Node {
+	cleanChildren(c, n)
+	n.Type = html.DocumentNode
+	n.Data, n.DataAtom, n.Namespace, n.Attr = "", 0, "", nil
+	return n
+}
+
+// overLimit counts n and reports whether there are more elements like it
+// than MaxElem allows.
+func (c *cleaner) overLimit(n *html.Node) bool {
+	max, ok := c.maxElem[n.DataAtom]
+	if !ok || n.DataAtom == 0 {
+		max, ok = c.maxElemCustom[n.Data]
+	}
+	if !ok {
+		return false
+	}
+
+	if c.elemCount == nil {
+		c.elemCount = make(map[string]int)
+	}
+	c.elemCount[n.Data]++
+
+	return c.elemCount[n.Data] > max
+}
+
+// appendFiltered appends a node returned by filterNode to nodes, replacing
+// the document nodes of unwrapped elements with their children.
+func appendFiltered(nodes []*html.Node, n *html.Node) []*html.Node {
+	if n.Type != html.DocumentNode {
+		return append(nodes, n)
+	}
+	for n.FirstChild != nil {
+		child := n.FirstChild
+		n.RemoveChild(child)
+		nodes = append(nodes, child)
+	}
+	return nodes
+}
+
 var allowedURLSchemes = map[string]bool{
 	"http":   true,
 	"https":  true,
@@ -341,29 +778,57 @@ func SafeURLScheme(u *url.URL) bool {
 	return allowedURLSchemes[u.Scheme]
 }
 
-func cleanURL(c *Config, a atom.Atom, attr *html.Attribute) bool {
-	if a != atom.Href && a != atom.Src && a != atom.Poster {
+// isURLAttr reports whether attributes named a are URLs.
+func isURLAttr(a atom.Atom) bool {
+	return a == atom.Href || a == atom.Src || a == atom.Poster
+}
+
+func cleanURL(c *Config, elem string, a atom.Atom, attr *html.Attribute) bool {
+	check := func(u *url.URL) bool {
+		return c.checkParsedURL(elem, u)
+	}
+	if a == atom.Style {
+		attr.Val = cleanStyleURLs(attr.Val, check)
+		return attr.Val != ""
+	}
+	if a == atom.Srcset {
+		attr.Val = cleanSrcset(attr.Val, check)
+		return attr.Val != ""
+	}
+	if !isURLAttr(a) {
 		return true
 	}
 
 	u, err := url.Parse(attr.Val)
-	if err != nil {
+	if err != nil || !c.checkParsedURL(elem, u) {
 		return false
 	}
+	attr.Val = u.String()
+	return true
+}
+
+// checkParsedURL reports whether u is allowed in an attribute of elem, and
+// removes any query parameters that should be stripped from it.
+func (c *Config) checkParsedURL(elem string, u *url.URL) bool {
 	if c.ValidateURL != nil && !c.ValidateURL(u) {
 		return false
 	}
-	attr.Val = u.String()
+	if !c.allowedHost(elem, u) {
+		return false
+	}
+	c.stripQueryParams(u)
 	return true
 }
 
-func cleanChildren(c *Config, parent *html.Node) {
+func cleanChildren(c *cleaner, parent *html.Node) {
 	var children []*html.Node
+	c.depth++
 	for parent.FirstChild != nil {
 		child := parent.FirstChild
 		parent.RemoveChild(child)
-		children = append(children, filterNode(c, child))
+		children = appendFiltered(children, filterNode(c, child))
 	}
+	c.depth--
 
 	if c.WrapText {
 		_, ok := c.wrap[parent.DataAtom]
@@ -380,29 +845,34 @@ func cleanChildren(c *Config, parent *html.Node) {
 	}
 }
 
-func forceMaxDepth(n *html.Node, depth int) {
+// forceMaxDepth replaces the nodes below depth with omittedMarker and
+// reports whether there were any.
+func forceMaxDepth(n *html.Node, depth int) (omitted bool) {
 	if depth == 0 {
 		n.Type = html.TextNode
 		n.FirstChild, n.LastChild = nil, nil
 		n.Attr = nil
-		n.Data = "[omitted]"
+		n.Data = omittedMarker
 		for n.NextSibling != nil {
 			n.Parent.RemoveChild(n.NextSibling)
 		}
-		return
+		return true
 	}
 
 	if n.Type != html.ElementNode {
-		return
+		return false
 	}
 
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		forceMaxDepth(c, depth-1)
+		if forceMaxDepth(c, depth-1) {
+			omitted = true
+		}
 	}
+	return omitted
 }
 
 func expectError(err, expected error) {
 	if err != expected {
-		panic("htmlcleaner: unexpected error: " + err.Error())
+		panic(unexpectedError{err})
 	}
 }
@@ -2,6 +2,7 @@ package htmlcleaner
 
 import (
 	"io"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -37,6 +38,64 @@ var wrapConfig = func() *Config {
 	return &c
 }()
 
+var collapseConfig = func() *Config {
+	c := DefaultConfig.Clone().SetWrapText(true).ElemAtom(atom.Ul, atom.Li)
+
+	c.CollapseWhitespace = true
+
+	return c
+}()
+
+var minifyConfig = func() *Config {
+	c := collapseConfig.Clone()
+
+	c.RemoveInterTagWhitespace = true
+
+	return c
+}()
+
+var removeEmptyConfig = func() *Config {
+	c := wrapConfig.Clone().ElemAtom(atom.Table, atom.Tbody, atom.Tr, atom.Td)
+
+	c.RemoveEmpty = true
+
+	return c
+}()
+
+var maxElemConfig = func() *Config {
+	c := DefaultConfig.Clone().MaxElem("img", 2).MaxElem("x-y", 1).Elem("x-y")
+
+	c.MaxElemPlaceholder = "[image removed]"
+
+	return c
+}()
+
+var headingConfig = func() *Config {
+	c := DefaultConfig.Clone().ElemAtom(atom.H3, atom.H4, atom.H5, atom.H6)
+
+	c.HeadingOffset = 2
+
+	return c
+}()
+
+var quoteConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.MaxQuoteDepth = 2
+	c.QuotePlaceholder = "[quote omitted]"
+
+	return c
+}()
+
+var quoteSummaryConfig = func() *Config {
+	c := (&Config{}).ElemAtom(atom.Blockquote, atom.B)
+
+	c.MaxQuoteDepth = 1
+	c.QuoteSummary = "Show quoted text"
+
+	return c
+}()
+
 var testTableClean = []testTable{
 	{"Empty", ``, ``, nil},
 	{"PlainText", `a`, `a`, nil},
@@ -62,6 +121,7 @@ var testTableClean = []testTable{
 	{"GreaterThanSuffix", `foo>`, `foo&gt;`, nil},
 	{"Comment", `<!--comment-->`, `<!--comment-->`, nil},
 	{"CommentEscaped", `<!--comment-->`, `&lt;!--comment--&gt;`, &Config{EscapeComments: true}},
+	{"CommentRemoved", `a<!--comment-->b`, `ab`, &Config{RemoveComments: true, EscapeComments: true}},
 	{"CDATA", `<![CDATA[ foo ]]>`, `<!--[CDATA[ foo ]]-->`, nil},
 	{"CDATAEscaped", `<![CDATA[ foo ]]>`, `&lt;!--[CDATA[ foo ]]--&gt;`, &Config{EscapeComments: true}},
 	{"XML", `<?xml version="1.0"?>`, `<!--?xml version="1.0"?-->`, nil},
@@ -74,12 +134,81 @@ var testTableClean = []testTable{
 	{"WrapUnclosed", `hello <em>world`, `<p>hello <em>world</em></p>`, wrapConfig},
 	{"WrapStraySpace", `<p>hello</p> <p>world</p>`, `<p>hello</p> <p>world</p>`, wrapConfig},
 	{"WrapInvalidNesting", `<em>hello <p>world</p>`, `<p><em>hello </em></p><p><em>world</em></p><p></p>`, wrapConfig},
+	{"CollapseWhitespace", "  hello \n\t <em>big   </em>  world  ", `<p>hello <em>big </em> world</p>`, collapseConfig},
+	{"CollapseWhitespacePre", "<p> a  b </p>\n<pre> a  b </pre>", `<p>a b</p> <pre> a  b </pre>`, collapseConfig},
+	{"CollapseWhitespaceInterTag", "<ul>\n  <li> a </li>\n  <li>b</li>\n</ul>", `<ul><li>a</li> <li>b</li></ul>`, collapseConfig},
+	{"RemoveEmpty", `<a href="javascript:x()"></a><p> </p><em><b></b></em>text`, `<p>text</p>`, removeEmptyConfig},
+	{"RemoveEmptyKeep", `<p><img src="a.png"></p><table><tr><td></td></tr></table>`, `<p><img src="a.png"/></p><table><tbody><tr><td></td></tr></tbody></table>`, removeEmptyConfig},
+	{"RemoveEmptyWrap", `<em>hello <p>world</p>`, `<p><em>hello </em></p><p><em>world</em></p>`, removeEmptyConfig},
+	{"RemoveEmptyCustomKeep", `<p></p><blockquote></blockquote>`, `<p></p>`, removeEmptyConfig.Clone().KeepEmpty("p")},
+	{"RemoveEmptyCustomKeepDefaults", `<p><br/></p><p></p><blockquote></blockquote>`, `<p><br/></p><blockquote></blockquote>`, removeEmptyConfig.Clone().ElemAtom(atom.Br).KeepEmpty("blockquote")},
+	{"Unwrap", `<font color="red">hello <b>world</b> <script>x</script></font>`, `hello <b>world</b> &lt;script&gt;x&lt;/script&gt;`, DefaultConfig.Clone().Unwrap("font")},
+	{"UnwrapCustom", `<x-y><x-z>a</x-z></x-y>`, `<x-z>a</x-z>`, (&Config{}).Elem("x-z").Unwrap("x-y")},
+	{"UnwrapWrap", `<font>a<p>b</p></font>`, `<p>a</p><p>b</p>`, wrapConfig.Clone().Unwrap("font")},
+	{"MaxElem", `<img src="1"><p><img src="2"><img src="3"></p><img src="4">`, `<img src="1"/><p><img src="2"/>[image removed]</p>[image removed]`, maxElemConfig},
+	{"MaxElemCustom", `<x-y>a<x-y>b</x-y></x-y><x-y>c</x-y>`, `<x-y>a[image removed]</x-y>[image removed]`, maxElemConfig},
+	{"MaxElemRemove", `<b>1</b><b>2</b>`, `<b>1</b>`, DefaultConfig.Clone().MaxElem("b", 1)},
+	{"HeadingOffset", `<h1>a</h1><h2>b</h2><h4>c</h4><h5>d</h5><h6>e</h6>`, `<h3>a</h3><h4>b</h4><h6>c</h6><h6>d</h6><h6>e</h6>`, headingConfig},
+	{"HeadingOffsetDisallowed", `<h1>a</h1><h2>b</h2>`, `&lt;h3&gt;a&lt;/h3&gt;<h4>b</h4>`, (&Config{HeadingOffset: 2}).ElemAtom(atom.H1, atom.H4)},
+	{"MaxQuoteDepth", `<blockquote>a<blockquote>b<blockquote>c<blockquote>d</blockquote></blockquote></blockquote></blockquote>`, `<blockquote>a<blockquote>b[quote omitted]</blockquote></blockquote>`, quoteConfig},
+	{"QuoteSummary", `<blockquote>a<blockquote>b<blockquote><b>c</b><blockquote>d</blockquote></blockquote></blockquote></blockquote>e`, `<blockquote>a<details><summary>Show quoted text</summary><blockquote>b<blockquote><b>c</b><blockquote>d</blockquote></blockquote></blockquote></details></blockquote>e`, quoteSummaryConfig},
+	{"QuoteSummarySiblings", `<blockquote><blockquote>a</blockquote><blockquote>b</blockquote></blockquote>`, `<blockquote><details><summary>Show quoted text</summary><blockquote>a</blockquote></details><details><summary>Show quoted text</summary><blockquote>b</blockquote></details></blockquote>`, quoteSummaryConfig},
+	{"MaxQuoteDepthFlatten", `<blockquote>a<blockquote>b<p>c</p><blockquote>d</blockquote></blockquote></blockquote>`, `<blockquote>ab<p>c</p>d</blockquote>`, (&Config{MaxQuoteDepth: 1}).ElemAtom(atom.Blockquote, atom.P)},
+	{"RemoveInterTagWhitespace", "<ul>\n  <li> a </li>\n  <li>b</li>\n</ul>", `<ul><li>a</li><li>b</li></ul>`, minifyConfig},
 }
 
 func TestClean(t *testing.T) {
 	doTableTest(Clean, t, testTableClean)
 }
 
+var webComponentConfig = (&Config{WrapText: true, RemoveEmpty: true}).
+	Elem("p", "x-card", "x-icon").
+	ElemAttr("x-card", "title", "data-id").
+	ElemAttrMatch("x-icon", "name", regexp.MustCompile(`\A[a-z-]+\z`)).
+	ElemAttr("p", "data-id").
+	GlobalAttr("slot").
+	WrapTextInside("x-card").
+	KeepEmpty("x-icon").
+	MaxElem("x-icon", 2).
+	Unwrap("x-wrapper")
+
+var testTableWebComponents = []testTable{
+	{"Allowed", `<x-card title="a">b</x-card>`, `<x-card title="a"><p>b</p></x-card>`, webComponentConfig},
+	{"UpperCase", `<X-Card TITLE="a">b</X-Card>`, `<x-card title="a"><p>b</p></x-card>`, webComponentConfig},
+	{"CustomAttr", `<x-card data-id="1" data-other="2" slot="s">b</x-card>`, `<x-card data-id="1" slot="s"><p>b</p></x-card>`, webComponentConfig},
+	{"CustomAttrOnKnownElement", `<p data-id="1" data-other="2">a</p>`, `<p data-id="1">a</p>`, webComponentConfig},
+	{"Match", `<x-icon name="star"></x-icon><x-icon name="Star!"></x-icon>`, `<x-icon name="star"></x-icon><x-icon></x-icon>`, webComponentConfig},
+	{"MaxElem", `<x-icon></x-icon><x-icon></x-icon><x-icon></x-icon>`, `<x-icon></x-icon><x-icon></x-icon>`, webComponentConfig},
+	{"Unwrap", `<x-wrapper><x-card>a</x-card></x-wrapper>`, `<x-card><p>a</p></x-card>`, webComponentConfig},
+	{"NotAllowed", `<x-other name="a">b</x-other>`, `<p>&lt;x-other name=&#34;a&#34;&gt;b&lt;/x-other&gt;</p>`, webComponentConfig},
+	{"Empty", `<x-card title="a"></x-card>`, ``, webComponentConfig},
+}
+
+func TestWebComponents(t *testing.T) {
+	doTableTest(Clean, t, testTableWebComponents)
+	doTableTest(func(c *Config, s string) string {
+		return Render(CleanNodes(c, Parse(s))...)
+	}, t, testTableWebComponents)
+}
+
+var wrapInsideConfig = (&Config{WrapText: true}).
+	Elem("p", "b", "blockquote", "x-box").
+	WrapTextInside("blockquote", "x-box")
+
+var testTableWrapTextInside = []testTable{
+	{"Blockquote", `a<blockquote>b<b>c</b></blockquote>`, `<p>a</p><blockquote><p>b<b>c</b></p></blockquote>`, wrapInsideConfig},
+	{"Nested", `<blockquote>a<blockquote>b<x-box>c<p>d</p>e</x-box></blockquote></blockquote>`, `<blockquote><p>a</p><blockquote><p>b</p><x-box><p>c</p><p>d</p><p>e</p></x-box></blockquote></blockquote>`, wrapInsideConfig},
+	{"NotInside", `<p>a<b>b</b></p>`, `<p>a<b>b</b></p>`, wrapInsideConfig},
+	{"WrapTextOff", `<blockquote>a</blockquote>`, `<blockquote>a</blockquote>`, (&Config{}).Elem("blockquote").WrapTextInside("blockquote")},
+}
+
+func TestWrapTextInside(t *testing.T) {
+	doTableTest(Clean, t, testTableWrapTextInside)
+	doTableTest(func(c *Config, s string) string {
+		return Render(CleanNodes(c, Parse(s))...)
+	}, t, testTableWrapTextInside)
+}
+
 var testTablePreprocess = []testTable{
 	{"Empty", ``, ``, nil},
 	{"NoMarkup", `a`, `a`, nil},
@@ -92,6 +221,7 @@ var testTablePreprocess = []testTable{
 	{"GreaterThanSuffix", `foo>`, `foo>`, nil},
 	{"Comment", `<!--comment-->`, `<!--comment-->`, nil},
 	{"CommentEscape", `<!--comment-->`, `&lt;!--comment--&gt;`, &Config{EscapeComments: true}},
+	{"CommentRemove", `a<!--comment-->b`, `ab`, &Config{RemoveComments: true}},
 	{"CDATA", `<![CDATA[ foo ]]>`, `&lt;![CDATA[ foo ]]&gt;`, nil},
 	{"CDATAEscape", `<![CDATA[ foo ]]>`, `&lt;![CDATA[ foo ]]&gt;`, &Config{EscapeComments: true}},
 	{"XML", `<?xml version="1.0"?>`, `&lt;?xml version=&#34;1.0&#34;?&gt;`, nil},
@@ -99,6 +229,7 @@ var testTablePreprocess = []testTable{
 	{"Doctype", `<!DOCTYPE html>`, `&lt;!DOCTYPE html&gt;`, nil},
 	{"DoctypeEscape", `<!DOCTYPE html>`, `&lt;!DOCTYPE html&gt;`, &Config{EscapeComments: true}},
 	{"PHP", `<?php echo mysql_real_escape_string('foo'); ?>`, `&lt;?php echo mysql_real_escape_string(&#39;foo&#39;); ?&gt;`, nil},
+	{"Unwrap", `<font color="red">a</font><b>b</b>`, `a&lt;b&gt;b&lt;/b&gt;`, (&Config{}).Unwrap("font")},
 	{"PHPEscape", `<?php echo mysql_real_escape_string('foo'); ?>`, `&lt;?php echo mysql_real_escape_string(&#39;foo&#39;); ?&gt;`, &Config{EscapeComments: true}},
 }
 
@@ -108,8 +239,8 @@ func TestPreprocess(t *testing.T) {
 
 func TestExpectError(t *testing.T) {
 	defer func() {
-		if r := recover(); r != "htmlcleaner: unexpected error: EOF" {
-			t.Errorf("expectError paniced with %v", r)
+		if err, ok := recover().(error); !ok || err.Error() != "htmlcleaner: unexpected error: EOF" {
+			t.Errorf("expectError paniced with %v", err)
 		}
 	}()
 
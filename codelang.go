@@ -0,0 +1,99 @@
+package htmlcleaner
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// codeClassRule is the class rule of a <code> or <pre> element that
+// CodeLanguages replaced.
+type codeClassRule struct {
+	// the rule before CodeLanguages, or nil if class was not allowed
+	prev *regexp.Regexp
+
+	// the rule set by CodeLanguages
+	re *regexp.Regexp
+}
+
+// CodeLanguages allows a class of language-xyz, as used by syntax
+// highlighters, on <code> and <pre> elements for each language xyz in langs.
+// It does not allow the elements themselves, so it only affects those that
+// are already allowed. Classes allowed by an earlier rule for the class
+// attribute on these elements are kept; otherwise, only the first allowed
+// language class is kept. The receiver is returned to allow call chaining.
+func (c *Config) CodeLanguages(langs ...string) *Config {
+	if c.codeLangs == nil {
+		c.codeLangs = make(map[string]struct{})
+	}
+	for _, lang := range langs {
+		c.codeLangs[lang] = struct{}{}
+	}
+
+	quoted := make([]string, 0, len(c.codeLangs))
+	for lang := range c.codeLangs {
+		quoted = append(quoted, regexp.QuoteMeta(lang))
+	}
+	sort.Strings(quoted)
+	re := regexp.MustCompile(`\Alanguage-(?:` + strings.Join(quoted, "|") + `)\z`)
+
+	for _, e := range []atom.Atom{atom.Code, atom.Pre} {
+		attrs, ok := c.elem[e]
+		if !ok {
+			continue
+		}
+
+		prev, ok := attrs[atom.Class]
+		if _, global := c.attr[atom.Class]; global && !ok {
+			prev, ok = nil, true
+		}
+		if ok && prev == nil {
+			// any class is already allowed
+			continue
+		}
+		if rule, ok := c.codeClass[e]; ok && rule.re == prev {
+			prev = rule.prev
+		}
+
+		combined := re
+		if prev != nil {
+			combined = eitherMatch(prev, re)
+		}
+
+		if c.codeClass == nil {
+			c.codeClass = make(map[atom.Atom]codeClassRule)
+		}
+		c.codeClass[e] = codeClassRule{prev: prev, re: combined}
+		c.ElemAttrAtomMatch(e, atom.Class, combined)
+	}
+
+	return c
+}
+
+// codeLanguageClass removes the classes other than the first allowed
+// language class from a class attribute on <code> or <pre>, unless the
+// attribute is allowed by the rule that CodeLanguages replaced.
+func (c *Config) codeLanguageClass(n *html.Node, attr *html.Attribute) {
+	rule, ok := c.codeClass[n.DataAtom]
+	if !ok || attr.Key != "class" || n.DataAtom == 0 || c.elem[n.DataAtom][atom.Class] != rule.re {
+		return
+	}
+	if rule.prev != nil && rule.prev.MatchString(attr.Val) {
+		return
+	}
+
+	for _, class := range strings.Fields(attr.Val) {
+		if !strings.HasPrefix(class, "language-") {
+			continue
+		}
+		if _, ok := c.codeLangs[class[len("language-"):]]; ok {
+			attr.Val = class
+			return
+		}
+	}
+
+	attr.Val = ""
+}
@@ -0,0 +1,35 @@
+package htmlcleaner
+
+import (
+	"regexp"
+	"testing"
+)
+
+var codeLangConfig = DefaultConfig.Clone().CodeLanguages("go", "c++")
+
+var codeLangClassConfig = DefaultConfig.Clone().
+	ElemAttrMatch("code", "class", regexp.MustCompile(`\Ahl-[a-z]+\z`)).
+	CodeLanguages("go")
+
+var testTableCodeLanguages = []testTable{
+	{"Allowed", `<pre><code class="language-go">x</code></pre>`, `<pre><code class="language-go">x</code></pre>`, codeLangConfig},
+	{"Pre", `<pre class="language-c++">x</pre>`, `<pre class="language-c++">x</pre>`, codeLangConfig},
+	{"OtherClasses", `<code class="hljs language-go highlighted">x</code>`, `<code class="language-go">x</code>`, codeLangConfig},
+	{"FirstLanguage", `<code class="language-rust language-c++ language-go">x</code>`, `<code class="language-c++">x</code>`, codeLangConfig},
+	{"NotAllowed", `<code class="language-rust">x</code>`, `<code>x</code>`, codeLangConfig},
+	{"NoPrefix", `<code class="go">x</code>`, `<code>x</code>`, codeLangConfig},
+	{"OtherElement", `<span class="language-go">x</span>`, `&lt;span class=&#34;language-go&#34;&gt;x&lt;/span&gt;`, codeLangConfig},
+	{"Unconfigured", `<code class="language-go">x</code>`, `<code>x</code>`, nil},
+	{"ElemNotAllowed", `<pre class="language-go"><code class="language-go">x</code></pre>`, `<pre class="language-go">&lt;code class=&#34;language-go&#34;&gt;x&lt;/code&gt;</pre>`, (&Config{}).Elem("pre").CodeLanguages("go")},
+	{"ExistingClass", `<code class="hl-keyword">x</code><code class="language-go">y</code>`, `<code class="hl-keyword">x</code><code class="language-go">y</code>`, codeLangClassConfig},
+	{"ExistingClassOther", `<code class="hl-keyword language-go">x</code>`, `<code class="language-go">x</code>`, codeLangClassConfig},
+	{"AnyClass", `<code class="hljs language-go">x</code>`, `<code class="hljs language-go">x</code>`, DefaultConfig.Clone().ElemAttr("code", "class").CodeLanguages("go")},
+}
+
+func TestCodeLanguages(t *testing.T) {
+	doTableTest(Clean, t, testTableCodeLanguages)
+
+	if !codeLangConfig.AllowsAttr("code", "class", "language-go") || codeLangConfig.AllowsAttr("code", "class", "language-go x") {
+		t.Error("unexpected result from AllowsAttr")
+	}
+}
@@ -0,0 +1,241 @@
+package htmlcleaner
+
+import (
+	"strconv"
+
+	"golang.org/x/net/html"
+)
+
+// Comparison is the result of CompareClean.
+type Comparison struct {
+	// The output of each Config.
+	Old, New string
+
+	// The places where the outputs differ, in document order. It is empty
+	// if the outputs are the same.
+	Diff []Difference
+}
+
+// Difference is a node that differs between the outputs compared by
+// CompareClean.
+type Difference struct {
+	// The location of the node, such as "p[0]/b[1]" for the second <b>
+	// element inside the first <p> element. Text nodes are named #text.
+	Path string
+
+	// The HTML of the node in each output, or an empty string if the
+	// node is only in the other output.
+	Old, New string
+}
+
+// CompareClean cleans a fragment of HTML with two Configs and returns both
+// outputs and the differences between them. It is meant for measuring the
+// effect of a change to a Config on a sample of content. The outputs are the
+// same as those of Clean.
+func CompareClean(cOld, cNew *Config, fragment string) *Comparison {
+	cmp := &Comparison{
+		Old: Clean(cOld, fragment),
+		New: Clean(cNew, fragment),
+	}
+	if cmp.Old != cmp.New {
+		cmp.Diff = diffNodes("", compareNodes(cOld, fragment, cmp.Old), compareNodes(cNew, fragment, cmp.New), nil)
+	}
+	return cmp
+}
+
+// compareNodes returns the nodes of the output of Clean. The nodes returned
+// by CleanNodes keep escaped elements apart from the text around them, so
+// they are used if they match the output.
+func compareNodes(c *Config, fragment, output string) []*html.Node {
+	if nodes := CleanNodes(c, Parse(fragment)); Render(nodes...) == output {
+		return nodes
+	}
+	return Parse(output)
+}
+
+func childNodes(n *html.Node) []*html.Node {
+	var children []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		children = append(children, c)
+	}
+	return children
+}
+
+// nodePath returns the path of nodes[i] below prefix.
+func nodePath(prefix string, nodes []*html.Node, i int) string {
+	name := "#text"
+	switch nodes[i].Type {
+	case html.ElementNode:
+		name = nodes[i].Data
+	case html.CommentNode:
+		name = "#comment"
+	}
+
+	index := 0
+	for _, n := range nodes[:i] {
+		if n.Type == nodes[i].Type && n.Data == nodes[i].Data {
+			index++
+		}
+	}
+
+	if prefix != "" {
+		prefix += "/"
+	}
+	return prefix + name + "[" + strconv.Itoa(index) + "]"
+}
+
+// maxCommonSubsequence is the largest number of pairs of nodes that
+// commonSubsequence compares. Past this, the nodes between the common prefix
+// and suffix are treated as entirely changed.
+const maxCommonSubsequence = 1 << 24
+
+// commonSubsequence returns the indices of the pairs of equal strings in the
+// longest common subsequence of olds and news, in order.
+func commonSubsequence(olds, news []string) [][2]int {
+	var pairs [][2]int
+
+	prefix := 0
+	for prefix < len(olds) && prefix < len(news) && olds[prefix] == news[prefix] {
+		pairs = append(pairs, [2]int{prefix, prefix})
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(olds)-prefix && suffix < len(news)-prefix && olds[len(olds)-1-suffix] == news[len(news)-1-suffix] {
+		suffix++
+	}
+
+	a, b := olds[prefix:len(olds)-suffix], news[prefix:len(news)-suffix]
+	if len(a) != 0 && len(b) != 0 && len(a) <= maxCommonSubsequence/len(b) {
+		pairs = hirschberg(a, b, prefix, prefix, pairs)
+	}
+
+	for k := suffix; k > 0; k-- {
+		pairs = append(pairs, [2]int{len(olds) - k, len(news) - k})
+	}
+	return pairs
+}
+
+// hirschberg appends the pairs in the longest common subsequence of a and b
+// to pairs, adding ai and bi to the indices. It uses space linear in the
+// length of b.
+func hirschberg(a, b []string, ai, bi int, pairs [][2]int) [][2]int {
+	if len(a) == 0 || len(b) == 0 {
+		return pairs
+	}
+	if len(a) == 1 {
+		for j := range b {
+			if a[0] == b[j] {
+				return append(pairs, [2]int{ai, bi + j})
+			}
+		}
+		return pairs
+	}
+
+	mid := len(a) / 2
+	front := lcsLengths(a[:mid], b, false)
+	back := lcsLengths(a[mid:], b, true)
+
+	split, best := 0, -1
+	for k := 0; k <= len(b); k++ {
+		if l := front[k] + back[len(b)-k]; l > best {
+			split, best = k, l
+		}
+	}
+
+	pairs = hirschberg(a[:mid], b[:split], ai, bi, pairs)
+	return hirschberg(a[mid:], b[split:], ai+mid, bi+split, pairs)
+}
+
+// lcsLengths returns a row where row[k] is the length of the longest common
+// subsequence of a and the first k strings of b, or the last k strings of a
+// and b if reverse is true.
+func lcsLengths(a, b []string, reverse bool) []int {
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for i := range a {
+		x := a[i]
+		if reverse {
+			x = a[len(a)-1-i]
+		}
+		for k := 1; k <= len(b); k++ {
+			y := b[k-1]
+			if reverse {
+				y = b[len(b)-k]
+			}
+			switch {
+			case x == y:
+				cur[k] = prev[k-1] + 1
+			case prev[k] >= cur[k-1]:
+				cur[k] = prev[k]
+			default:
+				cur[k] = cur[k-1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// sameTag reports whether two nodes are the same apart from their children.
+func sameTag(a, b *html.Node) bool {
+	if a.Type != b.Type || a.Data != b.Data || a.Namespace != b.Namespace || len(a.Attr) != len(b.Attr) {
+		return false
+	}
+	for i := range a.Attr {
+		if a.Attr[i] != b.Attr[i] {
+			return false
+		}
+	}
+	return a.Type == html.ElementNode
+}
+
+// diffNodes appends the differences between two lists of sibling nodes to
+// diff. Siblings are matched using their longest common subsequence, and
+// unmatched siblings with the same tag are compared recursively.
+func diffNodes(prefix string, olds, news []*html.Node, diff []Difference) []Difference {
+	oldHTML := make([]string, len(olds))
+	for i, n := range olds {
+		oldHTML[i] = Render(n)
+	}
+	newHTML := make([]string, len(news))
+	for i, n := range news {
+		newHTML[i] = Render(n)
+	}
+
+	var gapOld, gapNew []int
+	flush := func() {
+		for k := 0; k < len(gapOld) || k < len(gapNew); k++ {
+			switch {
+			case k >= len(gapNew):
+				i := gapOld[k]
+				diff = append(diff, Difference{Path: nodePath(prefix, olds, i), Old: oldHTML[i]})
+			case k >= len(gapOld):
+				j := gapNew[k]
+				diff = append(diff, Difference{Path: nodePath(prefix, news, j), New: newHTML[j]})
+			default:
+				i, j := gapOld[k], gapNew[k]
+				path := nodePath(prefix, olds, i)
+				if sameTag(olds[i], news[j]) {
+					diff = diffNodes(path, childNodes(olds[i]), childNodes(news[j]), diff)
+				} else {
+					diff = append(diff, Difference{Path: path, Old: oldHTML[i], New: newHTML[j]})
+				}
+			}
+		}
+		gapOld, gapNew = gapOld[:0], gapNew[:0]
+	}
+
+	i, j := 0, 0
+	for _, m := range append(commonSubsequence(oldHTML, newHTML), [2]int{len(olds), len(news)}) {
+		for ; i < m[0]; i++ {
+			gapOld = append(gapOld, i)
+		}
+		for ; j < m[1]; j++ {
+			gapNew = append(gapNew, j)
+		}
+		flush()
+		i++
+		j++
+	}
+
+	return diff
+}
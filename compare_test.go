@@ -0,0 +1,78 @@
+package htmlcleaner
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestCompareClean(t *testing.T) {
+	strict := (&Config{}).Elem("p", "b")
+
+	for _, tt := range []struct {
+		name  string
+		input string
+		diff  []Difference
+	}{
+		{"Same", `<p><b>a</b></p>`, nil},
+		{"Escaped", `<p>a<i>b</i>c</p>`, []Difference{
+			{Path: "p[0]/i[0]", Old: `<i>b</i>`, New: `&lt;i&gt;b&lt;/i&gt;`},
+		}},
+		{"Attribute", `<p><b title="x">a</b><b>b</b></p>`, []Difference{
+			{Path: "p[0]/b[0]", Old: `<b title="x">a</b>`, New: `<b>a</b>`},
+		}},
+		{"Nested", `<p>x</p><p><b><a href="/">a</a></b></p>`, []Difference{
+			{Path: "p[1]/b[0]/a[0]", Old: `<a href="/">a</a>`, New: `&lt;a href=&#34;/&#34;&gt;a&lt;/a&gt;`},
+		}},
+	} {
+		cmp := CompareClean(nil, strict, tt.input)
+		if cmp.Old != Render(CleanNodes(nil, Parse(tt.input))...) || cmp.New != Render(CleanNodes(strict, Parse(tt.input))...) {
+			t.Errorf("%s: unexpected outputs %q and %q", tt.name, cmp.Old, cmp.New)
+		}
+		if !reflect.DeepEqual(cmp.Diff, tt.diff) {
+			t.Errorf("%s: expected %q\ngot %q", tt.name, tt.diff, cmp.Diff)
+		}
+	}
+}
+
+func TestCommonSubsequence(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 200; n++ {
+		olds := make([]string, r.Intn(12))
+		for i := range olds {
+			olds[i] = string('a' + rune(r.Intn(3)))
+		}
+		news := make([]string, r.Intn(12))
+		for j := range news {
+			news[j] = string('a' + rune(r.Intn(3)))
+		}
+
+		// the length of the longest common subsequence, the slow way
+		lcs := make([][]int, len(olds)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(news)+1)
+		}
+		for i := len(olds) - 1; i >= 0; i-- {
+			for j := len(news) - 1; j >= 0; j-- {
+				if olds[i] == news[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] > lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+
+		pairs := commonSubsequence(olds, news)
+		if len(pairs) != lcs[0][0] {
+			t.Errorf("%q %q: expected %d pairs, got %v", olds, news, lcs[0][0], pairs)
+		}
+		for k, p := range pairs {
+			if olds[p[0]] != news[p[1]] || (k > 0 && (p[0] <= pairs[k-1][0] || p[1] <= pairs[k-1][1])) {
+				t.Errorf("%q %q: bad pairs %v", olds, news, pairs)
+				break
+			}
+		}
+	}
+}
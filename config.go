@@ -4,6 +4,7 @@ import (
 	"net/url"
 	"regexp"
 
+	"golang.org/x/net/html"
 	"golang.org/x/net/html/atom"
 )
 
@@ -16,16 +17,326 @@ type Config struct {
 	wrap       map[atom.Atom]struct{}
 	wrapCustom map[string]struct{}
 
+	keepEmpty       map[atom.Atom]struct{}
+	keepEmptyCustom map[string]struct{}
+	unwrap          map[atom.Atom]struct{}
+	unwrapCustom    map[string]struct{}
+	textRules       []textRule
+	tokenFilters    []TokenFilter
+	maxElem         map[atom.Atom]int
+	maxElemCustom   map[string]int
+	forbid          map[string]map[string]struct{}
+	requireInside   map[string]map[string]struct{}
+	codeLangs       map[string]struct{}
+	codeClass       map[atom.Atom]codeClassRule
+	allowHosts      map[string]map[string]struct{}
+	denyHosts       map[string]map[string]struct{}
+	stripQuery      map[string]struct{}
+	stripQueryHosts map[string]map[string]struct{}
+	allowSelectors  []selectorRule
+	denySelectors   []selector
+	namedEntities   map[rune]string
+	escapeChars     map[rune]struct{}
+	maxText         map[string]int
+	maxAttrText     map[ElemAttr]int
+
 	// A custom URL validation function. If it is set and returns false,
 	// the attribute will be removed. Called for attributes such as src
-	// and href.
+	// and href, and for each url() in an allowed style attribute, such as
+	// a background-image, in which case only that declaration is removed.
 	ValidateURL func(*url.URL) bool
 
 	// If true, HTML comments are turned into text.
 	EscapeComments bool
 
+	// If true, HTML comments are removed. This takes precedence over
+	// EscapeComments.
+	RemoveComments bool
+
+	// What Preprocess does with allowed start tags that have attributes
+	// the Config does not allow, such as onclick. By default, only the
+	// tag name is checked, and the attributes are left for Clean.
+	PreprocessAttrs PreprocessAttrAction
+
+	// What Preprocess does with the contents of disallowed elements whose
+	// contents are not parsed as HTML, such as <script>, <style>, and
+	// <textarea>. By default, the contents are kept as they are.
+	RawTextContent RawTextAction
+
 	// Wrap text nodes in at least one tag.
 	WrapText bool
+
+	// Collapse runs of whitespace in text nodes to a single space, except
+	// inside elements such as <pre> and <code>, and trim whitespace at the
+	// start and end of block elements.
+	CollapseWhitespace bool
+
+	// If true along with CollapseWhitespace, text nodes that consist only
+	// of whitespace are removed.
+	RemoveInterTagWhitespace bool
+
+	// If set, Preprocess and Clean check their input with SniffContent.
+	// If it does not look like HTML, the input is passed to SniffHandler
+	// along with the detected media type, and its return value is used
+	// as-is instead of cleaning the input.
+	SniffHandler func(contentType, fragment string) string
+
+	// Convert URLs and email addresses in text to links. URLs are
+	// recognized by their scheme, a www. prefix, or a common top-level
+	// domain. The links are subject to ValidateURL and the rest of the
+	// Config, and are only created if <a href> is allowed.
+	AutoLink bool
+
+	// Remove elements that have no content after cleaning, other than
+	// whitespace. See KeepEmpty.
+	RemoveEmpty bool
+
+	// The text that replaces elements beyond the limit set by MaxElem. If
+	// it is empty, the elements are removed.
+	MaxElemPlaceholder string
+
+	// What happens to elements that are forbidden inside one of their
+	// ancestors, or outside the ancestors they require. See ForbidInside
+	// and RequireInside.
+	NestingAction NestingAction
+
+	// If set, spans are started for each call to Clean, CleanNodes, and
+	// Preprocess.
+	Tracer Tracer
+
+	// If non-nil, the output of Clean and CleanNodes is parsed again and
+	// checked with CheckOutput. Violations are passed to Verify, and the
+	// output is replaced with its escaped source. This is a safety net
+	// for custom text rules and other transformations, and costs one
+	// extra parse per call.
+	Verify func(err error)
+
+	// The number of levels to add to each heading before cleaning, so
+	// that an offset of 2 turns <h1> into <h3>. Levels are clamped
+	// between <h1> and <h6>. The changed element must be allowed.
+	HeadingOffset int
+
+	// The maximum number of nested <blockquote> elements, or 0 for no
+	// limit other than the depth limit of Parse. Quotes nested more
+	// deeply are replaced with QuotePlaceholder if it is set, and
+	// otherwise with their contents. If QuoteSummary is set instead, the
+	// first quote that is nested too deeply is kept, with everything
+	// inside it, in a collapsed <details> element with QuoteSummary as
+	// its <summary>, such as "Show quoted text".
+	MaxQuoteDepth    int
+	QuotePlaceholder string
+	QuoteSummary     string
+
+	// An optional, expensive check for URLs that are allowed by
+	// ValidateURL, such as a lookup in a remote blocklist. If Budget is
+	// set, CheckURL only runs while the Budget allows it. Skipped checks
+	// keep the URL and are reported to the Tracer, with the output marked
+	// as partially validated.
+	CheckURL func(*url.URL) bool
+	Budget   Budget
+
+	// If non-nil, counts how often each rule is applied.
+	RuleStats *RuleStats
+
+	// If non-nil, called with the language and text of each
+	// <pre><code> block, where the language is taken from a class such
+	// as language-go on either element. The returned nodes, such as
+	// spans from a syntax highlighter, replace the contents of the
+	// block without being cleaned. If it returns nil, the block is
+	// cleaned normally.
+	HighlightCode func(lang, code string) []*html.Node
+
+	// Make Clean write non-ASCII characters as numeric character
+	// references, such as &#160;, unless they are listed in
+	// NamedEntities. This is useful for consumers that do not handle
+	// UTF-8 or that only understand the named references defined by XML.
+	NumericEntities bool
+
+	// What to do with runs of non-breaking spaces, which are often used
+	// for indentation in pasted content. A single non-breaking space is
+	// always kept. Text inside elements such as <pre> is not changed.
+	NBSP      NBSPAction
+	NBSPClass string
+
+	// If non-zero, words longer than this many characters get a break
+	// opportunity after every BreakLongWords characters, so they cannot
+	// widen the page. The break is a soft hyphen, or a <wbr> element if
+	// BreakWithWBR is set, which is added even if the Config does not
+	// otherwise allow it. Words that look like URLs and text inside
+	// links, code, and preformatted elements are not changed.
+	BreakLongWords int
+	BreakWithWBR   bool
+
+	// If non-zero, words longer than this many characters, including
+	// URLs, are split with spaces or truncated, depending on
+	// MaxWordAction. Truncated words are wrapped in a <span> with the
+	// whole word as its title, even if the Config does not otherwise
+	// allow it. Text inside links, code, and preformatted elements is not
+	// changed.
+	MaxWordLength int
+	MaxWordAction MaxWordAction
+
+	// If non-zero, colors set by allowed attributes such as color,
+	// bgcolor, and style are removed from elements whose text would have
+	// a WCAG contrast ratio below this, such as 4.5, to stop hidden or
+	// unreadable text. Colors are inherited from enclosing elements, and
+	// the page is assumed to have black text on a white background.
+	// Colors that cannot be parsed are removed.
+	MinContrast float64
+
+	// A candidate Config to try out in report-only mode. If Shadow and
+	// ShadowReport are set, Clean also cleans each fragment using Shadow,
+	// and if the outputs differ, calls ShadowReport with the input, the
+	// output that Clean returns, and the output of Shadow. This allows a
+	// stricter policy to be tested on real content before it is used.
+	// Shadow does not affect the output of Clean, even if it panics.
+	Shadow       *Config
+	ShadowReport func(fragment, output, candidate string)
+
+	// What happens to links to internationalized domain names, which can
+	// be used to imitate a well-known domain. The default is IDNAllow.
+	IDNLinks IDNAction
+
+	// If non-zero, elements keep only their first MaxChildren children,
+	// and only the first MaxNodes nodes of each fragment are kept, in
+	// document order. The rest are replaced by the text "[omitted]",
+	// like the nodes below the maximum depth in ParseDepth.
+	MaxChildren int
+	MaxNodes    int
+
+	// If non-zero, Clean expands at most MaxCharRefs character references
+	// per fragment, and none longer than MaxCharRefLength bytes, such as
+	// numeric references padded with zeros. CharRefAction decides what
+	// happens to the rest.
+	MaxCharRefs      int
+	MaxCharRefLength int
+	CharRefAction    CharRefAction
+
+	// If non-zero, attributes with values longer than MaxAttrLength bytes,
+	// and URL attributes such as href and src longer than MaxURLLength
+	// bytes, such as large data: URLs, are removed.
+	MaxURLLength  int
+	MaxAttrLength int
+
+	// If non-zero, Clean limits its output to MaxExpansion times the size
+	// of its input, or 1 KiB if that is larger, since escaping disallowed
+	// elements can make the output much larger than the input.
+	// ExpansionAction decides what happens to larger outputs.
+	MaxExpansion    float64
+	ExpansionAction ExpansionAction
+
+	// If non-zero, Preprocess and Clean only process the first
+	// MaxInputBytes bytes of their input, cut at a token boundary, so
+	// that large payloads are not processed to completion. InputAction
+	// decides what happens to larger inputs.
+	MaxInputBytes int
+	InputAction   InputAction
+
+	// Event handler attributes such as onclick, and attributes holding
+	// javascript: or vbscript: URLs, are removed even if the Config
+	// allows them, unless AllowUnsafe is set. This protects against
+	// mistakes in a Config; do not set it for untrusted content.
+	AllowUnsafe bool
+
+	// If set, <video> and <audio> elements lose their autoplay and loop
+	// attributes and get controls and preload="none", even if the Config
+	// does not allow those attributes, so that media only plays when the
+	// reader asks for it. If MediaCrossOrigin is also set, such as to
+	// "anonymous", it becomes their crossorigin attribute.
+	SandboxMedia     bool
+	MediaCrossOrigin string
+
+	// If not empty, these become the loading, decoding, and
+	// referrerpolicy attributes of every <img> element, and the loading
+	// and referrerpolicy attributes of every <iframe> element, replacing
+	// any the author wrote, even if the Config does not allow those
+	// attributes. For example, "lazy", "async", and "no-referrer".
+	ImageLoading   string
+	ImageDecoding  string
+	ReferrerPolicy string
+
+	// If non-nil, called with the src of each <img> element that has
+	// neither a width nor a height attribute after cleaning. If it
+	// returns ok and positive dimensions, such as from the application's
+	// image metadata, they are added as width and height attributes, even
+	// if the Config does not allow those attributes, to avoid layout
+	// shifts while the image loads.
+	ImageDimensions func(src string) (w, h int, ok bool)
+
+	// What happens to <img> elements with no alt attribute after
+	// cleaning. If AltText is set, it is called with the src of each such
+	// image first, and if it returns ok, the result becomes the alt
+	// attribute instead. Alt attributes are added even if the Config does
+	// not otherwise allow them.
+	MissingAlt AltAction
+	AltText    func(src string) (alt string, ok bool)
+
+	// If set, CleanNodes and Clean add dir="auto" to block elements such
+	// as <p> and <blockquote> that have no dir attribute, even if the
+	// Config does not allow it, so that each paragraph of multilingual
+	// content is shown in the direction of its own text.
+	AutoDir bool
+
+	// If non-nil, CleanNodes and Clean wrap each run of consecutive
+	// images in a gallery element, with each image in a <figure>
+	// captioned by its title or alt text. The added elements are allowed
+	// even if the Config does not otherwise allow them.
+	Gallery *Gallery
+
+	// If set, CleanNodes and Clean add a data-node-id attribute to each
+	// block element, even if the Config does not allow it. The id is
+	// NodeIDPrefix followed by a hash of the cleaned element, so it stays
+	// the same across re-renders as long as the element does, and
+	// client-side annotations such as inline comments can be anchored to
+	// it. Identical elements are numbered in document order. Existing
+	// data-node-id attributes are removed. Node ids are not part of the
+	// output of Canonical.
+	NodeIDPrefix string
+
+	// If set, CleanNodes and Clean replace straight quotes in text with
+	// curly quotes, "--" with an em dash, and "..." with an ellipsis. Text
+	// inside elements such as <code>, <pre>, and <kbd> is left alone.
+	SmartPunctuation bool
+
+	// If non-nil, called with each text node that is kept by CleanNodes
+	// and Clean, and the element it is in, or nil at the top level, and
+	// returns the replacement text, such as with filtered words masked.
+	// Text inside <code> or <pre> can be left alone by checking the
+	// parent and its ancestors. TextFilter runs after text rules and
+	// SmartPunctuation, and must not modify the tree.
+	TextFilter func(parent *html.Node, text string) string
+
+	// If set, Preprocess and Clean convert CRLF and CR line endings in
+	// their input to LF and remove form feeds and vertical tabs before
+	// parsing, so that content pasted from other systems gives the same
+	// text nodes, and the same diffs, as content typed directly.
+	NormalizeNewlines bool
+
+	// Added to text and attribute values that are cut short by
+	// MaxTextLength and MaxAttrTextLength, such as "…". It does not count
+	// toward the limits.
+	TruncationMarker string
+
+	// Options for the characters in text, which apply before text rules
+	// such as AutoLink. NormalizeUnicode converts text to Unicode
+	// normalization form C, so that the same text is always encoded the
+	// same way. If OnConfusable is set, it is called with each word that
+	// mixes Latin, Greek, and Cyrillic letters, which are often used to
+	// imitate other words.
+	NormalizeUnicode bool
+	BidiControls     BidiAction
+	ZeroWidth        ZeroWidthAction
+	OnConfusable     func(word string)
+
+	// What happens to control characters other than tab, line feed, and
+	// carriage return, and to invalid UTF-8, in the text, comments, and
+	// attribute values of the output of CleanNodes and Clean. With
+	// ControlStrip or ControlReplace, the output is always valid UTF-8.
+	ControlChars ControlCharAction
+
+	// FailureAction decides what Preprocess, Clean, and CleanNodes do if
+	// they panic. By default, the panic is not recovered.
+	FailureAction FailureAction
 }
 
 // Elem ensures an element name is allowed. The receiver is returned to
@@ -190,9 +501,218 @@ func (c *Config) WrapTextInsideAtom(elem ...atom.Atom) *Config {
 	return c
 }
 
+// Unwrap makes disallowed elements with the specified names be replaced by
+// their cleaned contents, rather than by a textual version of their source
+// code. The receiver is returned to allow call chaining.
+func (c *Config) Unwrap(names ...string) *Config {
+	if c.unwrapCustom == nil {
+		c.unwrapCustom = make(map[string]struct{})
+	}
+
+	for _, name := range names {
+		if a := atom.Lookup([]byte(name)); a != 0 {
+			c.UnwrapAtom(a)
+			continue
+		}
+
+		c.unwrapCustom[name] = struct{}{}
+	}
+
+	return c
+}
+
+// UnwrapAtom makes disallowed elements with the specified names be replaced
+// by their cleaned contents, rather than by a textual version of their source
+// code. The receiver is returned to allow call chaining.
+func (c *Config) UnwrapAtom(elem ...atom.Atom) *Config {
+	if c.unwrap == nil {
+		c.unwrap = make(map[atom.Atom]struct{})
+	}
+
+	for _, a := range elem {
+		c.unwrap[a] = struct{}{}
+	}
+
+	return c
+}
+
+func (c *Config) unwrapped(a atom.Atom, name string) bool {
+	if _, ok := c.unwrap[a]; ok && a != 0 {
+		return true
+	}
+	_, ok := c.unwrapCustom[name]
+	return ok
+}
+
+// MaxElem limits the number of elements with the specified name in each
+// cleaned fragment. Elements beyond the limit are replaced by
+// MaxElemPlaceholder. The receiver is returned to allow call chaining.
+func (c *Config) MaxElem(elem string, n int) *Config {
+	if a := atom.Lookup([]byte(elem)); a != 0 {
+		return c.MaxElemAtom(a, n)
+	}
+
+	if c.maxElemCustom == nil {
+		c.maxElemCustom = make(map[string]int)
+	}
+
+	c.maxElemCustom[elem] = n
+
+	return c
+}
+
+// MaxElemAtom limits the number of elements with the specified name in each
+// cleaned fragment. Elements beyond the limit are replaced by
+// MaxElemPlaceholder. The receiver is returned to allow call chaining.
+func (c *Config) MaxElemAtom(elem atom.Atom, n int) *Config {
+	if c.maxElem == nil {
+		c.maxElem = make(map[atom.Atom]int)
+	}
+
+	c.maxElem[elem] = n
+
+	return c
+}
+
+// AllowsElem reports whether an element with the specified name is allowed.
+func (c *Config) AllowsElem(name string) bool {
+	_, ok1 := c.elem[atom.Lookup([]byte(name))]
+	_, ok2 := c.elemCustom[name]
+	return ok1 || ok2
+}
+
+// AllowsAttr reports whether an attribute with the specified name and value
+// is allowed on an element with the specified name. It does not check whether
+// the element itself is allowed. URLs are checked with ValidateURL.
+func (c *Config) AllowsAttr(elem, attr, val string) bool {
+	a := html.Attribute{Key: attr, Val: val}
+	return c.checkAttr(atom.Lookup([]byte(elem)), elem, &a) == attrOK
+}
+
+// AllowedElems returns the names of the allowed elements, in no particular
+// order.
+func (c *Config) AllowedElems() []string {
+	var names []string
+	for e := range c.elem {
+		names = append(names, e.String())
+	}
+	for e := range c.elemCustom {
+		if _, ok := c.elem[atom.Lookup([]byte(e))]; !ok {
+			names = append(names, e)
+		}
+	}
+	return names
+}
+
+// AllowedAttrs returns the names of the attributes allowed on the specified
+// element, including global attributes, in no particular order.
+func (c *Config) AllowedAttrs(elem string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for a := range c.elem[atom.Lookup([]byte(elem))] {
+		add(a.String())
+	}
+	for a := range c.elemCustom[elem] {
+		add(a)
+	}
+	for a := range c.attr {
+		add(a.String())
+	}
+	for a := range c.attrCustom {
+		add(a)
+	}
+
+	return names
+}
+
+type attrVerdict int
+
+const (
+	attrOK         attrVerdict = iota
+	attrNotAllowed             // the attribute is not allowed on the element
+	attrBadURL                 // the URL could not be parsed or failed ValidateURL
+	attrNoMatch                // the value does not match the regular expression
+	attrTooLong                // the value is longer than MaxAttrLength or MaxURLLength
+	attrUnsafe                 // the attribute is an event handler or holds a script URL
+)
+
+// checkAttr decides whether an attribute is allowed on an element. URL
+// attributes are normalized in place.
+func (c *Config) checkAttr(e atom.Atom, elem string, attr *html.Attribute) attrVerdict {
+	a := atom.Lookup([]byte(attr.Key))
+
+	re1, ok1 := c.elem[e][a]
+	re2, ok2 := c.elemCustom[elem][attr.Key]
+	_, ok3 := c.attr[a]
+	_, ok4 := c.attrCustom[attr.Key]
+
+	if attr.Namespace != "" || (!ok1 && !ok2 && !ok3 && !ok4) {
+		return attrNotAllowed
+	}
+
+	if v := c.checkAttrValue(elem, attr); v != attrOK {
+		return v
+	}
+
+	if re1 != nil && !re1.MatchString(attr.Val) {
+		return attrNoMatch
+	}
+	if re2 != nil && !re2.MatchString(attr.Val) {
+		return attrNoMatch
+	}
+
+	return attrOK
+}
+
+// attrMatch returns the regular expression that an attribute's value must
+// match, or nil if there is none.
+func (c *Config) attrMatch(e atom.Atom, elem, key string) *regexp.Regexp {
+	if re := c.elem[e][atom.Lookup([]byte(key))]; re != nil {
+		return re
+	}
+	return c.elemCustom[elem][key]
+}
+
+// checkAttrValue applies the checks for an allowed attribute that do not
+// depend on the element and attribute rules.
+func (c *Config) checkAttrValue(elem string, attr *html.Attribute) attrVerdict {
+	a := atom.Lookup([]byte(attr.Key))
+
+	if c.MaxAttrLength > 0 && len(attr.Val) > c.MaxAttrLength {
+		return attrTooLong
+	}
+
+	if !cleanURL(c, elem, a, attr) {
+		return attrBadURL
+	}
+
+	if c.MaxURLLength > 0 && isURLAttr(a) && len(attr.Val) > c.MaxURLLength {
+		return attrTooLong
+	}
+
+	if !c.AllowUnsafe && unsafeAttr(attr) {
+		return attrUnsafe
+	}
+
+	if !validGlobalValue(a, attr.Val) {
+		return attrNoMatch
+	}
+
+	return attrOK
+}
+
 // DefaultConfig is the default settings for htmlcleaner.
 var DefaultConfig = (&Config{
-	ValidateURL: SafeURLScheme,
+	ValidateURL:   SafeURLScheme,
+	MaxURLLength:  DefaultMaxURLLength,
+	MaxAttrLength: DefaultMaxAttrLength,
 }).GlobalAttrAtom(atom.Title).
 	ElemAttrAtom(atom.A, atom.Href).
 	ElemAttrAtom(atom.Img, atom.Src, atom.Alt).
@@ -207,3 +727,165 @@ var DefaultConfig = (&Config{
 	ElemAtom(atom.Code, atom.Kbd, atom.Tt).
 	ElemAttrAtom(atom.Details, atom.Open).
 	ElemAtom(atom.Summary)
+
+// Clone returns a deep copy of the Config. Changes made to the copy, including
+// through chainable methods such as Elem, do not affect the original. This is
+// the preferred way to derive a Config from DefaultConfig.
+func (c *Config) Clone() *Config {
+	clone := *c
+
+	if c.elem != nil {
+		clone.elem = make(map[atom.Atom]map[atom.Atom]*regexp.Regexp, len(c.elem))
+		for e, attrs := range c.elem {
+			var copied map[atom.Atom]*regexp.Regexp
+			if attrs != nil {
+				copied = make(map[atom.Atom]*regexp.Regexp, len(attrs))
+				for a, re := range attrs {
+					copied[a] = re
+				}
+			}
+			clone.elem[e] = copied
+		}
+	}
+	if c.elemCustom != nil {
+		clone.elemCustom = make(map[string]map[string]*regexp.Regexp, len(c.elemCustom))
+		for e, attrs := range c.elemCustom {
+			var copied map[string]*regexp.Regexp
+			if attrs != nil {
+				copied = make(map[string]*regexp.Regexp, len(attrs))
+				for a, re := range attrs {
+					copied[a] = re
+				}
+			}
+			clone.elemCustom[e] = copied
+		}
+	}
+	clone.attr = copyAtomSet(c.attr)
+	clone.attrCustom = copyStringSet(c.attrCustom)
+	clone.wrap = copyAtomSet(c.wrap)
+	clone.wrapCustom = copyStringSet(c.wrapCustom)
+	clone.keepEmpty = copyAtomSet(c.keepEmpty)
+	clone.keepEmptyCustom = copyStringSet(c.keepEmptyCustom)
+	clone.unwrap = copyAtomSet(c.unwrap)
+	clone.unwrapCustom = copyStringSet(c.unwrapCustom)
+	clone.textRules = append([]textRule(nil), c.textRules...)
+	clone.tokenFilters = append([]TokenFilter(nil), c.tokenFilters...)
+	if c.maxElem != nil {
+		clone.maxElem = make(map[atom.Atom]int, len(c.maxElem))
+		for e, n := range c.maxElem {
+			clone.maxElem[e] = n
+		}
+	}
+	clone.forbid = copyStringSets(c.forbid)
+	clone.requireInside = copyStringSets(c.requireInside)
+	if c.maxElemCustom != nil {
+		clone.maxElemCustom = make(map[string]int, len(c.maxElemCustom))
+		for e, n := range c.maxElemCustom {
+			clone.maxElemCustom[e] = n
+		}
+	}
+	clone.codeLangs = copyStringSet(c.codeLangs)
+	if c.codeClass != nil {
+		clone.codeClass = make(map[atom.Atom]codeClassRule, len(c.codeClass))
+		for e, rule := range c.codeClass {
+			clone.codeClass[e] = rule
+		}
+	}
+	clone.allowHosts = copyStringSets(c.allowHosts)
+	clone.denyHosts = copyStringSets(c.denyHosts)
+	clone.stripQuery = copyStringSet(c.stripQuery)
+	clone.stripQueryHosts = copyStringSets(c.stripQueryHosts)
+	clone.allowSelectors = append([]selectorRule(nil), c.allowSelectors...)
+	clone.denySelectors = append([]selector(nil), c.denySelectors...)
+	if c.namedEntities != nil {
+		clone.namedEntities = make(map[rune]string, len(c.namedEntities))
+		for r, ref := range c.namedEntities {
+			clone.namedEntities[r] = ref
+		}
+	}
+	if c.escapeChars != nil {
+		clone.escapeChars = make(map[rune]struct{}, len(c.escapeChars))
+		for r := range c.escapeChars {
+			clone.escapeChars[r] = struct{}{}
+		}
+	}
+	if c.maxText != nil {
+		clone.maxText = make(map[string]int, len(c.maxText))
+		for e, n := range c.maxText {
+			clone.maxText[e] = n
+		}
+	}
+	if c.maxAttrText != nil {
+		clone.maxAttrText = make(map[ElemAttr]int, len(c.maxAttrText))
+		for k, n := range c.maxAttrText {
+			clone.maxAttrText[k] = n
+		}
+	}
+
+	return &clone
+}
+
+func copyAtomSet(s map[atom.Atom]struct{}) map[atom.Atom]struct{} {
+	if s == nil {
+		return nil
+	}
+	clone := make(map[atom.Atom]struct{}, len(s))
+	for k := range s {
+		clone[k] = struct{}{}
+	}
+	return clone
+}
+
+func copyStringSets(m map[string]map[string]struct{}) map[string]map[string]struct{} {
+	if m == nil {
+		return nil
+	}
+	clone := make(map[string]map[string]struct{}, len(m))
+	for e, hosts := range m {
+		clone[e] = copyStringSet(hosts)
+	}
+	return clone
+}
+
+func copyStringSet(s map[string]struct{}) map[string]struct{} {
+	if s == nil {
+		return nil
+	}
+	clone := make(map[string]struct{}, len(s))
+	for k := range s {
+		clone[k] = struct{}{}
+	}
+	return clone
+}
+
+// The setters below are equivalent to assigning the exported fields of the
+// same name directly, and exist so that a Config can be built in a single
+// chained expression. Only these fields have setters; the others are
+// assigned directly. There is no separate options type.
+
+// SetValidateURL sets ValidateURL. The receiver is returned to allow call
+// chaining.
+func (c *Config) SetValidateURL(f func(*url.URL) bool) *Config {
+	c.ValidateURL = f
+	return c
+}
+
+// SetEscapeComments sets EscapeComments. The receiver is returned to allow
+// call chaining.
+func (c *Config) SetEscapeComments(escape bool) *Config {
+	c.EscapeComments = escape
+	return c
+}
+
+// SetRemoveComments sets RemoveComments. The receiver is returned to allow
+// call chaining.
+func (c *Config) SetRemoveComments(remove bool) *Config {
+	c.RemoveComments = remove
+	return c
+}
+
+// SetWrapText sets WrapText. The receiver is returned to allow call chaining.
+func (c *Config) SetWrapText(wrap bool) *Config {
+	c.WrapText = wrap
+	return c
+}
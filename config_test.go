@@ -44,3 +44,19 @@ func TestConfig(t *testing.T) {
 	run("GlobalCustomAttr", `<p data-original-title="World">Hello</p><custom-element data-original-title="Hello">World</custom-element>`, `<p>Hello</p><custom-element>World</custom-element>`, `<p data-original-title="World">Hello</p><custom-element data-original-title="Hello">World</custom-element>`, (&htmlcleaner.Config{}).Elem("p", "custom-element"), func(c *htmlcleaner.Config) { c.GlobalAttr("data-original-title") })
 	run("WrapText", `a<blockquote>b</blockquote>c<custom-element>d</custom-element>e`, `<p>a</p><blockquote>b</blockquote><p>c</p><custom-element>d</custom-element><p>e</p>`, `<p>a</p><blockquote><p>b</p></blockquote><p>c</p><custom-element><p>d</p></custom-element><p>e</p>`, (&htmlcleaner.Config{WrapText: true}).Elem("p", "blockquote", "custom-element"), func(c *htmlcleaner.Config) { c.WrapTextInside("blockquote", "custom-element") })
 }
+
+func TestConfigClone(t *testing.T) {
+	original := (&htmlcleaner.Config{}).Elem("p", "custom-element").ElemAttr("p", "title")
+	clone := original.Clone().ElemAttr("p", "lang").Elem("b").SetWrapText(true)
+
+	input := `<p title="a" lang="en"><b>c</b></p>`
+	if expected, actual := `<p title="a">&lt;b&gt;c&lt;/b&gt;</p>`, htmlcleaner.Clean(original, input); expected != actual {
+		t.Errorf("original: expected %q, actual %q", expected, actual)
+	}
+	if expected, actual := `<p title="a" lang="en"><b>c</b></p>`, htmlcleaner.Clean(clone, input); expected != actual {
+		t.Errorf("clone: expected %q, actual %q", expected, actual)
+	}
+	if original.WrapText {
+		t.Errorf("SetWrapText on clone modified original")
+	}
+}
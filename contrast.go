@@ -0,0 +1,631 @@
+package htmlcleaner
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// rgb is a color with components from 0 to 255.
+type rgb [3]int
+
+// paint is a color with an opacity from 0 to 1.
+type paint struct {
+	c     rgb
+	alpha float64
+}
+
+// over returns the color seen when p is drawn over bg.
+func (p paint) over(bg rgb) rgb {
+	var c rgb
+	for i := range c {
+		c[i] = int(math.Floor(p.alpha*float64(p.c[i]) + (1-p.alpha)*float64(bg[i]) + 0.5))
+	}
+	return c
+}
+
+var (
+	defaultForeground = rgb{0, 0, 0}
+	defaultBackground = rgb{255, 255, 255}
+)
+
+// namedColors holds the CSS named colors.
+var namedColors = map[string]rgb{
+	"aliceblue":            {240, 248, 255},
+	"antiquewhite":         {250, 235, 215},
+	"aqua":                 {0, 255, 255},
+	"aquamarine":           {127, 255, 212},
+	"azure":                {240, 255, 255},
+	"beige":                {245, 245, 220},
+	"bisque":               {255, 228, 196},
+	"black":                {0, 0, 0},
+	"blanchedalmond":       {255, 235, 205},
+	"blue":                 {0, 0, 255},
+	"blueviolet":           {138, 43, 226},
+	"brown":                {165, 42, 42},
+	"burlywood":            {222, 184, 135},
+	"cadetblue":            {95, 158, 160},
+	"chartreuse":           {127, 255, 0},
+	"chocolate":            {210, 105, 30},
+	"coral":                {255, 127, 80},
+	"cornflowerblue":       {100, 149, 237},
+	"cornsilk":             {255, 248, 220},
+	"crimson":              {220, 20, 60},
+	"cyan":                 {0, 255, 255},
+	"darkblue":             {0, 0, 139},
+	"darkcyan":             {0, 139, 139},
+	"darkgoldenrod":        {184, 134, 11},
+	"darkgray":             {169, 169, 169},
+	"darkgreen":            {0, 100, 0},
+	"darkgrey":             {169, 169, 169},
+	"darkkhaki":            {189, 183, 107},
+	"darkmagenta":          {139, 0, 139},
+	"darkolivegreen":       {85, 107, 47},
+	"darkorange":           {255, 140, 0},
+	"darkorchid":           {153, 50, 204},
+	"darkred":              {139, 0, 0},
+	"darksalmon":           {233, 150, 122},
+	"darkseagreen":         {143, 188, 143},
+	"darkslateblue":        {72, 61, 139},
+	"darkslategray":        {47, 79, 79},
+	"darkslategrey":        {47, 79, 79},
+	"darkturquoise":        {0, 206, 209},
+	"darkviolet":           {148, 0, 211},
+	"deeppink":             {255, 20, 147},
+	"deepskyblue":          {0, 191, 255},
+	"dimgray":              {105, 105, 105},
+	"dimgrey":              {105, 105, 105},
+	"dodgerblue":           {30, 144, 255},
+	"firebrick":            {178, 34, 34},
+	"floralwhite":          {255, 250, 240},
+	"forestgreen":          {34, 139, 34},
+	"fuchsia":              {255, 0, 255},
+	"gainsboro":            {220, 220, 220},
+	"ghostwhite":           {248, 248, 255},
+	"gold":                 {255, 215, 0},
+	"goldenrod":            {218, 165, 32},
+	"gray":                 {128, 128, 128},
+	"green":                {0, 128, 0},
+	"greenyellow":          {173, 255, 47},
+	"grey":                 {128, 128, 128},
+	"honeydew":             {240, 255, 240},
+	"hotpink":              {255, 105, 180},
+	"indianred":            {205, 92, 92},
+	"indigo":               {75, 0, 130},
+	"ivory":                {255, 255, 240},
+	"khaki":                {240, 230, 140},
+	"lavender":             {230, 230, 250},
+	"lavenderblush":        {255, 240, 245},
+	"lawngreen":            {124, 252, 0},
+	"lemonchiffon":         {255, 250, 205},
+	"lightblue":            {173, 216, 230},
+	"lightcoral":           {240, 128, 128},
+	"lightcyan":            {224, 255, 255},
+	"lightgoldenrodyellow": {250, 250, 210},
+	"lightgray":            {211, 211, 211},
+	"lightgreen":           {144, 238, 144},
+	"lightgrey":            {211, 211, 211},
+	"lightpink":            {255, 182, 193},
+	"lightsalmon":          {255, 160, 122},
+	"lightseagreen":        {32, 178, 170},
+	"lightskyblue":         {135, 206, 250},
+	"lightslategray":       {119, 136, 153},
+	"lightslategrey":       {119, 136, 153},
+	"lightsteelblue":       {176, 196, 222},
+	"lightyellow":          {255, 255, 224},
+	"lime":                 {0, 255, 0},
+	"limegreen":            {50, 205, 50},
+	"linen":                {250, 240, 230},
+	"magenta":              {255, 0, 255},
+	"maroon":               {128, 0, 0},
+	"mediumaquamarine":     {102, 205, 170},
+	"mediumblue":           {0, 0, 205},
+	"mediumorchid":         {186, 85, 211},
+	"mediumpurple":         {147, 112, 219},
+	"mediumseagreen":       {60, 179, 113},
+	"mediumslateblue":      {123, 104, 238},
+	"mediumspringgreen":    {0, 250, 154},
+	"mediumturquoise":      {72, 209, 204},
+	"mediumvioletred":      {199, 21, 133},
+	"midnightblue":         {25, 25, 112},
+	"mintcream":            {245, 255, 250},
+	"mistyrose":            {255, 228, 225},
+	"moccasin":             {255, 228, 181},
+	"navajowhite":          {255, 222, 173},
+	"navy":                 {0, 0, 128},
+	"oldlace":              {253, 245, 230},
+	"olive":                {128, 128, 0},
+	"olivedrab":            {107, 142, 35},
+	"orange":               {255, 165, 0},
+	"orangered":            {255, 69, 0},
+	"orchid":               {218, 112, 214},
+	"palegoldenrod":        {238, 232, 170},
+	"palegreen":            {152, 251, 152},
+	"paleturquoise":        {175, 238, 238},
+	"palevioletred":        {219, 112, 147},
+	"papayawhip":           {255, 239, 213},
+	"peachpuff":            {255, 218, 185},
+	"peru":                 {205, 133, 63},
+	"pink":                 {255, 192, 203},
+	"plum":                 {221, 160, 221},
+	"powderblue":           {176, 224, 230},
+	"purple":               {128, 0, 128},
+	"rebeccapurple":        {102, 51, 153},
+	"red":                  {255, 0, 0},
+	"rosybrown":            {188, 143, 143},
+	"royalblue":            {65, 105, 225},
+	"saddlebrown":          {139, 69, 19},
+	"salmon":               {250, 128, 114},
+	"sandybrown":           {244, 164, 96},
+	"seagreen":             {46, 139, 87},
+	"seashell":             {255, 245, 238},
+	"sienna":               {160, 82, 45},
+	"silver":               {192, 192, 192},
+	"skyblue":              {135, 206, 235},
+	"slateblue":            {106, 90, 205},
+	"slategray":            {112, 128, 144},
+	"slategrey":            {112, 128, 144},
+	"snow":                 {255, 250, 250},
+	"springgreen":          {0, 255, 127},
+	"steelblue":            {70, 130, 180},
+	"tan":                  {210, 180, 140},
+	"teal":                 {0, 128, 128},
+	"thistle":              {216, 191, 216},
+	"tomato":               {255, 99, 71},
+	"turquoise":            {64, 224, 208},
+	"violet":               {238, 130, 238},
+	"wheat":                {245, 222, 179},
+	"white":                {255, 255, 255},
+	"whitesmoke":           {245, 245, 245},
+	"yellow":               {255, 255, 0},
+	"yellowgreen":          {154, 205, 50},
+}
+
+// parseColor parses a named, hexadecimal, rgb(), or hsl() color, including
+// an alpha component.
+func parseColor(s string) (rgb, float64, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if c, ok := namedColors[s]; ok {
+		return c, 1, true
+	}
+	if s == "transparent" {
+		return rgb{}, 0, true
+	}
+
+	if strings.HasPrefix(s, "#") {
+		return parseHexColor(s[1:])
+	}
+
+	open := strings.IndexByte(s, '(')
+	if open == -1 || !strings.HasSuffix(s, ")") {
+		return rgb{}, 0, false
+	}
+	args := strings.Fields(strings.NewReplacer(",", " ", "/", " ").Replace(s[open+1 : len(s)-1]))
+	if len(args) != 3 && len(args) != 4 {
+		return rgb{}, 0, false
+	}
+
+	alpha := 1.0
+	if len(args) == 4 {
+		a, ok := parseColorNumber(args[3], 1)
+		if !ok {
+			return rgb{}, 0, false
+		}
+		alpha = clamp(a, 0, 1)
+	}
+
+	switch s[:open] {
+	case "rgb", "rgba":
+		var c rgb
+		for i := range c {
+			v, ok := parseColorNumber(args[i], 255)
+			if !ok {
+				return rgb{}, 0, false
+			}
+			c[i] = int(math.Floor(clamp(v, 0, 255) + 0.5))
+		}
+		return c, alpha, true
+	case "hsl", "hsla":
+		h, ok := parseColorNumber(strings.TrimSuffix(args[0], "deg"), 0)
+		if !ok {
+			return rgb{}, 0, false
+		}
+		sat, ok1 := parseColorNumber(args[1], 100)
+		light, ok2 := parseColorNumber(args[2], 100)
+		if !ok1 || !ok2 {
+			return rgb{}, 0, false
+		}
+		return hslToRGB(h, clamp(sat, 0, 100)/100, clamp(light, 0, 100)/100), alpha, true
+	}
+
+	return rgb{}, 0, false
+}
+
+// parseHexColor parses a color with 3, 4, 6, or 8 hexadecimal digits.
+func parseHexColor(hex string) (rgb, float64, bool) {
+	if len(hex) == 3 || len(hex) == 4 {
+		long := make([]byte, 0, 2*len(hex))
+		for i := 0; i < len(hex); i++ {
+			long = append(long, hex[i], hex[i])
+		}
+		hex = string(long)
+	}
+	if len(hex) != 6 && len(hex) != 8 {
+		return rgb{}, 0, false
+	}
+
+	var v [4]int
+	v[3] = 255
+	for i := 0; 2*i < len(hex); i++ {
+		d, err := strconv.ParseUint(hex[2*i:2*i+2], 16, 8)
+		if err != nil {
+			return rgb{}, 0, false
+		}
+		v[i] = int(d)
+	}
+	return rgb{v[0], v[1], v[2]}, float64(v[3]) / 255, true
+}
+
+// parseColorNumber parses a number or a percentage of max. If max is zero,
+// percentages are not allowed.
+func parseColorNumber(s string, max float64) (float64, bool) {
+	scale := 1.0
+	if strings.HasSuffix(s, "%") {
+		if max == 0 {
+			return 0, false
+		}
+		s = s[:len(s)-1]
+		scale = max / 100
+	}
+	for i := 0; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && s[i] != '.' && s[i] != '-' && s[i] != '+' {
+			return 0, false
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f * scale, true
+}
+
+func clamp(f, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, f))
+}
+
+// hslToRGB converts a hue in degrees and a saturation and lightness from 0
+// to 1 to a color.
+func hslToRGB(h, s, l float64) rgb {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	f := func(n float64) int {
+		k := math.Mod(n+h/30, 12)
+		a := s * math.Min(l, 1-l)
+		v := l - a*math.Max(-1, math.Min(k-3, math.Min(9-k, 1)))
+		return int(math.Floor(v*255 + 0.5))
+	}
+	return rgb{f(0), f(8), f(4)}
+}
+
+// luminance returns the WCAG relative luminance of c.
+func (c rgb) luminance() float64 {
+	var l [3]float64
+	for i, v := range c {
+		f := float64(v) / 255
+		if f <= 0.03928 {
+			l[i] = f / 12.92
+		} else {
+			l[i] = math.Pow((f+0.055)/1.055, 2.4)
+		}
+	}
+	return 0.2126*l[0] + 0.7152*l[1] + 0.0722*l[2]
+}
+
+// contrastRatio returns the WCAG contrast ratio of two colors, from 1 to 21.
+func contrastRatio(a, b rgb) float64 {
+	la, lb := a.luminance(), b.luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// styleDeclarations splits an inline style into property and value pairs.
+func styleDeclarations(style string) [][2]string {
+	var decls [][2]string
+	for _, decl := range splitStyle(style) {
+		i := strings.IndexByte(decl, ':')
+		if i == -1 {
+			continue
+		}
+		decls = append(decls, [2]string{
+			strings.ToLower(strings.TrimSpace(decl[:i])),
+			strings.TrimSpace(decl[i+1:]),
+		})
+	}
+	return decls
+}
+
+// isColorProperty reports whether an attribute or CSS property sets the
+// foreground or background color.
+func isColorProperty(name string) (fg, bg bool) {
+	switch name {
+	case "color":
+		return true, false
+	case "bgcolor", "background-color", "background":
+		return false, true
+	}
+	return false, false
+}
+
+// colorKeywords are values of color properties that do not set a color.
+var colorKeywords = map[string]bool{
+	"inherit":      true,
+	"initial":      true,
+	"unset":        true,
+	"revert":       true,
+	"revert-layer": true,
+	"currentcolor": true,
+	"none":         true,
+}
+
+// propertyColor returns the color set by the value of an attribute or CSS
+// property that sets a color, or nil if the value does not set one. It
+// returns false if the value sets a color that cannot be parsed.
+func propertyColor(name, val string) (*paint, bool) {
+	val = strings.TrimSpace(val)
+	if i := strings.LastIndexByte(val, '!'); i != -1 && strings.EqualFold(strings.TrimSpace(val[i+1:]), "important") {
+		val = strings.TrimSpace(val[:i])
+	}
+
+	tokens := []string{val}
+	if name == "background" {
+		tokens = splitBackground(val)
+	}
+
+	for _, tok := range tokens {
+		lower := strings.ToLower(tok)
+		if colorKeywords[lower] {
+			continue
+		}
+		if c, alpha, ok := parseColor(lower); ok {
+			return &paint{c, alpha}, true
+		}
+		if name != "background" || strings.HasPrefix(lower, "#") || (strings.HasSuffix(lower, ")") && !strings.HasPrefix(lower, "url(")) {
+			// the shorthand also sets images, positions, and so
+			// on, but gradients and colors that are not understood
+			// could hide text
+			return nil, false
+		}
+	}
+
+	return nil, true
+}
+
+// splitBackground splits the value of the CSS background property at spaces
+// and slashes outside of parentheses.
+func splitBackground(val string) []string {
+	var tokens []string
+	depth, start := 0, 0
+	for i := 0; i <= len(val); i++ {
+		if i < len(val) {
+			switch val[i] {
+			case '(':
+				depth++
+				continue
+			case ')':
+				if depth > 0 {
+					depth--
+				}
+				continue
+			case ' ', '\t', '\n', '\f', '\r', '/', ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		if tok := strings.TrimSpace(val[start:i]); tok != "" {
+			tokens = append(tokens, tok)
+		}
+		start = i + 1
+	}
+	return tokens
+}
+
+// colors returns the foreground and background colors set by attrs, or nil
+// for colors they do not set. Colors that cannot be parsed are ignored.
+func colors(attrs []html.Attribute) (fg, bg *paint) {
+	set := func(name, val string) {
+		isFg, isBg := isColorProperty(name)
+		if !isFg && !isBg {
+			return
+		}
+		c, _ := propertyColor(name, val)
+		if c == nil {
+			return
+		}
+		if isFg {
+			fg = c
+		} else {
+			bg = c
+		}
+	}
+
+	for _, a := range attrs {
+		if a.Namespace != "" {
+			continue
+		}
+		if a.Key == "style" {
+			for _, decl := range styleDeclarations(a.Val) {
+				set(decl[0], decl[1])
+			}
+			continue
+		}
+		set(a.Key, a.Val)
+	}
+
+	return
+}
+
+// hasUncoloredText reports whether n contains text that is not inside a
+// descendant with its own foreground color. The children of n have already
+// been cleaned.
+func hasUncoloredText(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.TextNode:
+			if strings.TrimFunc(c.Data, isHTMLSpace) != "" {
+				return true
+			}
+		case html.ElementNode:
+			if fg, _ := colors(c.Attr); fg == nil && hasUncoloredText(c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkContrast removes the colors set by the cleaned attributes of n if the
+// text would have less contrast than MinContrast, taking the colors of the
+// enclosing elements into account. It returns the contrast ratio and whether
+// the colors were removed.
+func (c *cleaner) checkContrast(n *html.Node) (float64, bool) {
+	if c.MinContrast <= 0 {
+		return 0, false
+	}
+
+	ownFg, ownBg := colors(n.Attr)
+	if ownFg == nil && (ownBg == nil || !hasUncoloredText(n)) {
+		return 0, false
+	}
+
+	// the enclosing elements have not been cleaned yet
+	fg, bg := defaultForeground, defaultBackground
+	for _, a := range c.stack {
+		var attrs []html.Attribute
+		for _, attr := range a.Attr {
+			if c.checkAttr(a.DataAtom, a.Data, &attr) == attrOK {
+				attrs = append(attrs, attr)
+			}
+		}
+		fg, bg = inheritColors(attrs, fg, bg)
+	}
+
+	ratio := contrastRatio(inheritColors(n.Attr, fg, bg))
+	if ratio >= c.MinContrast {
+		return ratio, false
+	}
+
+	c.removeColors(n)
+
+	// descendants with their own colors were checked against the
+	// colors that were just removed
+	c.recheckContrast(n, fg, bg)
+
+	return ratio, true
+}
+
+// inheritColors returns the colors of an element with attrs inside an
+// element with the colors fg and bg.
+func inheritColors(attrs []html.Attribute, fg, bg rgb) (rgb, rgb) {
+	f, b := colors(attrs)
+	if b != nil {
+		bg = b.over(bg)
+	}
+	if f != nil {
+		fg = f.over(bg)
+	}
+	return fg, bg
+}
+
+// recheckContrast removes colors from the descendants of n whose text has
+// too little contrast with the colors fg and bg inherited from n.
+func (c *cleaner) recheckContrast(n *html.Node, fg, bg rgb) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+
+		f, b := inheritColors(child.Attr, fg, bg)
+		if (f != fg || b != bg) && contrastRatio(f, b) < c.MinContrast {
+			c.removeColors(child)
+			f, b = fg, bg
+		}
+		c.recheckContrast(child, f, b)
+	}
+}
+
+// isUnknownColor reports whether an attribute or CSS property sets a color
+// that cannot be parsed.
+func isUnknownColor(name, val string) bool {
+	if isFg, isBg := isColorProperty(name); !isFg && !isBg {
+		return false
+	}
+	_, ok := propertyColor(name, val)
+	return !ok
+}
+
+// removeUnknownColors removes the cleaned attributes and style declarations
+// of n that set colors whose contrast cannot be checked. It reports whether
+// any were removed.
+func (c *cleaner) removeUnknownColors(n *html.Node) bool {
+	if c.MinContrast <= 0 {
+		return false
+	}
+
+	return c.removeColorsIf(n, isUnknownColor)
+}
+
+// removeColors removes the attributes and style declarations of n that set
+// colors.
+func (c *cleaner) removeColors(n *html.Node) {
+	c.removeColorsIf(n, func(name, val string) bool {
+		isFg, isBg := isColorProperty(name)
+		return isFg || isBg
+	})
+}
+
+// removeColorsIf removes the attributes and style declarations of n for
+// which remove returns true, and reports whether any were removed.
+func (c *cleaner) removeColorsIf(n *html.Node, remove func(name, val string) bool) bool {
+	removed := false
+	attrs := n.Attr[:0]
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == "style" {
+			var kept []string
+			changed := false
+			for _, decl := range styleDeclarations(a.Val) {
+				if remove(decl[0], decl[1]) {
+					changed = true
+				} else {
+					kept = append(kept, decl[0]+": "+decl[1])
+				}
+			}
+			if changed {
+				removed = true
+				if len(kept) == 0 {
+					continue
+				}
+				a.Val = strings.Join(kept, "; ")
+			}
+		} else if a.Namespace == "" && remove(a.Key, a.Val) {
+			removed = true
+			continue
+		}
+		attrs = append(attrs, a)
+	}
+	n.Attr = attrs
+
+	if removed {
+		c.lowContrast++
+	}
+	return removed
+}
@@ -0,0 +1,63 @@
+package htmlcleaner
+
+import (
+	"math"
+	"testing"
+)
+
+var contrastConfig = func() *Config {
+	c := (&Config{}).Elem("p", "b").
+		ElemAttr("font", "color").
+		ElemAttr("td", "bgcolor").
+		ElemAttr("span", "style").
+		Elem("table", "tbody", "tr")
+
+	c.MinContrast = 4.5
+
+	return c
+}()
+
+var testTableContrast = []testTable{
+	{"Readable", `<font color="#333">a</font>`, `<font color="#333">a</font>`, contrastConfig},
+	{"White", `<font color="white">a</font>`, `<font>a</font>`, contrastConfig},
+	{"Style", `<span style="color: #fefefe; font-weight: bold">a</span>`, `<span style="font-weight: bold">a</span>`, contrastConfig},
+	{"StyleOnlyColor", `<span style="color:rgb(250, 250, 250)">a</span>`, `<span>a</span>`, contrastConfig},
+	{"Inherited", `<table><tr><td bgcolor="black"><font color="#111">a</font><font color="yellow">b</font></td></tr></table>`, `<table><tbody><tr><td><font>a</font><font>b</font></td></tr></tbody></table>`, contrastConfig},
+	{"InheritedOK", `<table><tr><td bgcolor="black"><font color="yellow">a</font></td></tr></table>`, `<table><tbody><tr><td bgcolor="black"><font color="yellow">a</font></td></tr></tbody></table>`, contrastConfig},
+	{"InheritedBackground", `<table><tr><td bgcolor="black"><b>a</b></td></tr></table>`, `<table><tbody><tr><td><b>a</b></td></tr></tbody></table>`, contrastConfig},
+	{"BackgroundOnly", `<span style="background-color: #000">a</span>`, `<span>a</span>`, contrastConfig},
+	{"Named", `<font color="darkslategray">a</font>`, `<font color="darkslategray">a</font>`, contrastConfig},
+	{"NamedLight", `<font color="lightgoldenrodyellow">a</font>`, `<font>a</font>`, contrastConfig},
+	{"Important", `<span style="color: white !important">a</span>`, `<span>a</span>`, contrastConfig},
+	{"RGBA", `<span style="color: rgba(0, 0, 0, 0.05)">a</span>`, `<span>a</span>`, contrastConfig},
+	{"RGBAOpaque", `<span style="color: rgba(0, 0, 0, 1)">a</span>`, `<span style="color: rgba(0, 0, 0, 1)">a</span>`, contrastConfig},
+	{"RGBPercent", `<span style="color: rgb(99%, 99%, 99%)">a</span>`, `<span>a</span>`, contrastConfig},
+	{"HSL", `<span style="color: hsl(0, 0%, 98%)">a</span>`, `<span>a</span>`, contrastConfig},
+	{"HSLDark", `<span style="color: hsl(240deg 100% 25%)">a</span>`, `<span style="color: hsl(240deg 100% 25%)">a</span>`, contrastConfig},
+	{"HSLA", `<span style="color: hsla(0, 0%, 0%, 0.1)">a</span>`, `<span>a</span>`, contrastConfig},
+	{"Hex8", `<span style="color: #00000010">a</span>`, `<span>a</span>`, contrastConfig},
+	{"Hex4", `<span style="color: #000f">a</span>`, `<span style="color: #000f">a</span>`, contrastConfig},
+	{"Transparent", `<span style="color: transparent">a</span>`, `<span>a</span>`, contrastConfig},
+	{"TransparentBackground", `<table><tr><td bgcolor="black"><span style="background-color: transparent; color: white">a</span></td></tr></table>`, `<table><tbody><tr><td bgcolor="black"><span style="background-color: transparent; color: white">a</span></td></tr></tbody></table>`, contrastConfig},
+	{"Shorthand", `<span style="background: url(x.png) #fff no-repeat; color: #fefefe">a</span>`, `<span>a</span>`, contrastConfig},
+	{"ShorthandNoColor", `<span style="background: no-repeat; color: #333">a</span>`, `<span style="background: no-repeat; color: #333">a</span>`, contrastConfig},
+	{"Gradient", `<span style="background: linear-gradient(#000, #000); font-weight: bold">a</span>`, `<span style="font-weight: bold">a</span>`, contrastConfig},
+	{"Unknown", `<font color="foo(1)">a</font>`, `<font>a</font>`, contrastConfig},
+	{"UnknownStyle", `<span style="color: var(--hidden); font-weight: bold">a</span>`, `<span style="font-weight: bold">a</span>`, contrastConfig},
+	{"Inherit", `<span style="color: inherit">a</span>`, `<span style="color: inherit">a</span>`, contrastConfig},
+}
+
+func TestMinContrast(t *testing.T) {
+	doTableTest(Clean, t, testTableContrast)
+}
+
+func TestContrastRatio(t *testing.T) {
+	black, _, _ := parseColor("#000")
+	white, _, _ := parseColor("rgb(255,255,255)")
+	if r := contrastRatio(black, white); math.Abs(r-21) > 0.01 {
+		t.Errorf("expected 21, got %v", r)
+	}
+	if r := contrastRatio(white, white); r != 1 {
+		t.Errorf("expected 1, got %v", r)
+	}
+}
@@ -0,0 +1,84 @@
+package htmlcleaner
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// ControlCharAction is what happens to control characters and invalid UTF-8
+// in the output. See Config.ControlChars.
+type ControlCharAction int
+
+const (
+	// ControlKeep leaves control characters and invalid UTF-8 alone.
+	ControlKeep ControlCharAction = iota
+
+	// ControlStrip removes control characters and invalid UTF-8.
+	ControlStrip
+
+	// ControlReplace replaces each control character and each invalid
+	// UTF-8 sequence with U+FFFD REPLACEMENT CHARACTER.
+	ControlReplace
+)
+
+// isControlChar reports whether r is a C0 or C1 control character other
+// than tab, line feed, or carriage return, or DEL.
+func isControlChar(r rune) bool {
+	if r == '\t' || r == '\n' || r == '\r' {
+		return false
+	}
+	return r < 0x20 || (r >= 0x7f && r <= 0x9f)
+}
+
+// scrubString applies ControlChars to s.
+func (c *Config) scrubString(s string) string {
+	if c.ControlChars == ControlKeep {
+		return s
+	}
+
+	clean := true
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || isControlChar(r) {
+			clean = false
+			break
+		}
+		i += size
+	}
+	if clean {
+		return s
+	}
+
+	buf := make([]byte, 0, len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || isControlChar(r) {
+			if c.ControlChars == ControlReplace {
+				buf = append(buf, string(utf8.RuneError)...)
+			}
+		} else {
+			buf = append(buf, s[i:i+size]...)
+		}
+		i += size
+	}
+	return string(buf)
+}
+
+// scrubNodes applies ControlChars to the text, comments, and attribute
+// values in nodes.
+func (c *Config) scrubNodes(nodes []*html.Node) {
+	for _, n := range nodes {
+		Walk(n, func(n *html.Node) WalkAction {
+			switch n.Type {
+			case html.TextNode, html.CommentNode:
+				n.Data = c.scrubString(n.Data)
+			case html.ElementNode:
+				for i := range n.Attr {
+					n.Attr[i].Val = c.scrubString(n.Attr[i].Val)
+				}
+			}
+			return WalkContinue
+		})
+	}
+}
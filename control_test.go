@@ -0,0 +1,47 @@
+package htmlcleaner
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+var controlStripConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.ControlChars = ControlStrip
+
+	return c
+}()
+
+var controlReplaceConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.ControlChars = ControlReplace
+
+	return c
+}()
+
+var testTableControlChars = []testTable{
+	{"Keep", "a\x01b\x85c", "a\x01b\x85c", nil},
+	{"Strip", "a\x01b\u0085c\x7fd\te\nf", "abcd\te\nf", controlStripConfig},
+	{"Replace", "a\x01b\u0085c", "a\ufffdb\ufffdc", controlReplaceConfig},
+	{"InvalidUTF8", "a\xffb\xe2\x82c", "abc", controlStripConfig},
+	{"InvalidUTF8Replace", "a\xffb", "a\ufffdb", controlReplaceConfig},
+	{"Attribute", "<b title=\"a\x02b\xff\">c</b>", "<b title=\"ab\">c</b>", controlStripConfig},
+	{"Comment", "<!--a\x02b-->", "<!--ab-->", controlStripConfig},
+	{"Escaped", "<script>\x02</script>", "&lt;script&gt;&lt;/script&gt;", controlStripConfig},
+}
+
+func TestControlChars(t *testing.T) {
+	doTableTest(Clean, t, testTableControlChars)
+	doTableTest(func(c *Config, fragment string) string {
+		return Render(CleanNodes(c, Parse(fragment))...)
+	}, t, testTableControlChars[1:])
+
+	c := controlStripConfig.Clone()
+	c.MaxCharRefs = 1
+	c.CharRefAction = CharRefReject
+	if output := Clean(c, "&amp;&amp;\xff"); !utf8.ValidString(output) {
+		t.Errorf("invalid UTF-8 in %q", output)
+	}
+}
@@ -0,0 +1,171 @@
+package htmlcleaner
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// EditOp is the kind of an Edit.
+type EditOp int
+
+const (
+	// EditEqual is content in both versions.
+	EditEqual EditOp = iota
+
+	// EditInsert is content only in the new version.
+	EditInsert
+
+	// EditDelete is content only in the old version.
+	EditDelete
+)
+
+// Edit is a piece of content compared by Diff.
+type Edit struct {
+	Op EditOp
+
+	// The location of the element containing the content, in the same
+	// form as Difference.Path, or an empty string at the top level.
+	Path string
+
+	// The HTML of the content: a word, a run of whitespace, or an element.
+	HTML string
+}
+
+// Diff compares two fragments of cleaned HTML, such as two versions of a
+// post, and returns their differences in document order. Text is compared
+// word by word. Elements that differ only in their contents, such as a
+// paragraph with one word changed, are compared recursively and are not
+// part of the result themselves, so each Edit in a changed element has the
+// path of that element.
+//
+// The fragments must already be the output of Clean. They are parsed without
+// a depth limit and are not cleaned again, so untrusted HTML passed to Diff
+// appears in the result as it is.
+func Diff(a, b string) []Edit {
+	var edits []Edit
+	diffWords("", ParseDepth(a, 0), ParseDepth(b, 0), nil, &edits)
+	return edits
+}
+
+// DiffHTML is like Diff, but returns the new version of the HTML with the
+// content only in the old version inside <del> elements and the content
+// only in the new version inside <ins> elements. Like Diff, it does not clean
+// its inputs, so the result is only as safe as the two fragments.
+func DiffHTML(a, b string) string {
+	parent := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	diffWords("", ParseDepth(a, 0), ParseDepth(b, 0), parent, nil)
+	return Render(childNodes(parent)...)
+}
+
+// splitWords returns nodes with each text node split into words and runs of
+// whitespace.
+func splitWords(nodes []*html.Node) []*html.Node {
+	var words []*html.Node
+	for _, n := range nodes {
+		if n.Type != html.TextNode {
+			words = append(words, n)
+			continue
+		}
+
+		s := n.Data
+		for s != "" {
+			space := isHTMLSpace(rune(s[0]))
+			i := 1
+			for i < len(s) && isHTMLSpace(rune(s[i])) == space {
+				i++
+			}
+			words = append(words, text(s[:i]))
+			s = s[i:]
+		}
+	}
+	return words
+}
+
+// diffWords compares two lists of sibling nodes, appending each change to
+// edits if it is non-nil and the merged nodes to parent if it is non-nil.
+func diffWords(prefix string, olds, news []*html.Node, parent *html.Node, edits *[]Edit) {
+	olds, news = splitWords(olds), splitWords(news)
+
+	oldHTML := make([]string, len(olds))
+	for i, n := range olds {
+		oldHTML[i] = Render(n)
+	}
+	newHTML := make([]string, len(news))
+	for j, n := range news {
+		newHTML[j] = Render(n)
+	}
+
+	emit := func(op EditOp, s string, n *html.Node, into *html.Node) {
+		if edits != nil {
+			*edits = append(*edits, Edit{Op: op, Path: prefix, HTML: s})
+		}
+		if into != nil {
+			into.AppendChild(deepCopy(n))
+		}
+	}
+
+	// emitChanged adds the unmatched nodes between two matched nodes,
+	// with the deleted nodes first.
+	emitChanged := func(deleted, inserted []int) {
+		var del, ins *html.Node
+		if parent != nil && len(deleted) != 0 {
+			del = &html.Node{Type: html.ElementNode, Data: "del", DataAtom: atom.Del}
+			parent.AppendChild(del)
+		}
+		if parent != nil && len(inserted) != 0 {
+			ins = &html.Node{Type: html.ElementNode, Data: "ins", DataAtom: atom.Ins}
+			parent.AppendChild(ins)
+		}
+		for _, i := range deleted {
+			emit(EditDelete, oldHTML[i], olds[i], del)
+		}
+		for _, j := range inserted {
+			emit(EditInsert, newHTML[j], news[j], ins)
+		}
+	}
+
+	var gapOld, gapNew []int
+	flush := func() {
+		start := 0
+		for k := 0; k < len(gapOld) && k < len(gapNew); k++ {
+			i, j := gapOld[k], gapNew[k]
+			if !sameTag(olds[i], news[j]) {
+				continue
+			}
+
+			emitChanged(gapOld[start:k], gapNew[start:k])
+			start = k + 1
+
+			var merged *html.Node
+			if parent != nil {
+				merged = &html.Node{
+					Type:      news[j].Type,
+					Data:      news[j].Data,
+					DataAtom:  news[j].DataAtom,
+					Namespace: news[j].Namespace,
+					Attr:      append([]html.Attribute(nil), news[j].Attr...),
+				}
+				parent.AppendChild(merged)
+			}
+			diffWords(nodePath(prefix, news, j), childNodes(olds[i]), childNodes(news[j]), merged, edits)
+		}
+		emitChanged(gapOld[start:], gapNew[start:])
+		gapOld, gapNew = gapOld[:0], gapNew[:0]
+	}
+
+	i, j := 0, 0
+	for _, m := range append(commonSubsequence(oldHTML, newHTML), [2]int{len(olds), len(news)}) {
+		for ; i < m[0]; i++ {
+			gapOld = append(gapOld, i)
+		}
+		for ; j < m[1]; j++ {
+			gapNew = append(gapNew, j)
+		}
+		flush()
+		if j < len(news) {
+			emit(EditEqual, newHTML[j], news[j], parent)
+		}
+		i++
+		j++
+	}
+}
@@ -0,0 +1,69 @@
+package htmlcleaner
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		a, b  string
+		edits []Edit
+		html  string
+	}{
+		{"Same", `a b`, `a b`, []Edit{
+			{EditEqual, "", "a"},
+			{EditEqual, "", " "},
+			{EditEqual, "", "b"},
+		}, `a b`},
+		{"Word", `<p>the cat sat</p>`, `<p>the dog sat</p>`, []Edit{
+			{EditEqual, "p[0]", "the"},
+			{EditEqual, "p[0]", " "},
+			{EditDelete, "p[0]", "cat"},
+			{EditInsert, "p[0]", "dog"},
+			{EditEqual, "p[0]", " "},
+			{EditEqual, "p[0]", "sat"},
+		}, `<p>the <del>cat</del><ins>dog</ins> sat</p>`},
+		{"Element", `<p>a</p>`, `<p>a</p><p>b</p>`, []Edit{
+			{EditEqual, "", "<p>a</p>"},
+			{EditInsert, "", "<p>b</p>"},
+		}, `<p>a</p><ins><p>b</p></ins>`},
+		{"Nested", `<p>x</p><p><b>a &amp; b</b></p>`, `<p>x</p><p><b>a &amp; c</b></p>`, []Edit{
+			{EditEqual, "", "<p>x</p>"},
+			{EditEqual, "p[1]/b[0]", "a"},
+			{EditEqual, "p[1]/b[0]", " "},
+			{EditEqual, "p[1]/b[0]", "&amp;"},
+			{EditEqual, "p[1]/b[0]", " "},
+			{EditDelete, "p[1]/b[0]", "b"},
+			{EditInsert, "p[1]/b[0]", "c"},
+		}, `<p>x</p><p><b>a &amp; <del>b</del><ins>c</ins></b></p>`},
+		{"Attribute", `<a href="/a">x</a>`, `<a href="/b">x</a>`, []Edit{
+			{EditDelete, "", `<a href="/a">x</a>`},
+			{EditInsert, "", `<a href="/b">x</a>`},
+		}, `<del><a href="/a">x</a></del><ins><a href="/b">x</a></ins>`},
+	} {
+		if edits := Diff(tt.a, tt.b); !reflect.DeepEqual(edits, tt.edits) {
+			t.Errorf("%s: expected %q\ngot %q", tt.name, tt.edits, edits)
+		}
+		if actual := DiffHTML(tt.a, tt.b); actual != tt.html {
+			t.Errorf("%s: expected %q, actual %q", tt.name, tt.html, actual)
+		}
+	}
+}
+
+func TestDiffLarge(t *testing.T) {
+	a := strings.Repeat("a ", 20000)
+	b := strings.Repeat("b ", 20000)
+
+	start := time.Now()
+	// only the final space is matched
+	if edits := Diff(a, b); len(edits) != 79999 {
+		t.Errorf("expected 79999 edits, got %d", len(edits))
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("took %v", elapsed)
+	}
+}
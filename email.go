@@ -0,0 +1,185 @@
+package htmlcleaner
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+)
+
+// EmailConfig is a Config suitable for displaying HTML email. It allows the
+// table and text layout elements commonly found in email, removes comments
+// (which some clients use for conditional markup), and allows cid: URLs so
+// images can refer to InlineImages.
+var EmailConfig = DefaultConfig.Clone().
+	SetValidateURL(emailURL).
+	SetRemoveComments(true).
+	ElemAtom(atom.Div, atom.Span, atom.Br, atom.Hr).
+	ElemAtom(atom.Ul, atom.Ol, atom.Li, atom.Dl, atom.Dt, atom.Dd).
+	ElemAtom(atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6).
+	ElemAtom(atom.Table, atom.Caption, atom.Thead, atom.Tbody, atom.Tfoot, atom.Tr).
+	ElemAttrAtom(atom.Td, atom.Colspan, atom.Rowspan).
+	ElemAttrAtom(atom.Th, atom.Colspan, atom.Rowspan).
+	ElemAttrAtom(atom.Img, atom.Width, atom.Height)
+
+func emailURL(u *url.URL) bool {
+	return SafeURLScheme(u) || u.Scheme == "cid"
+}
+
+// ErrNoHTML is returned by CleanEmail if the message has no text/html part.
+var ErrNoHTML = errors.New("htmlcleaner: message has no text/html part")
+
+// maxEmailDepth is the number of levels of multipart parts that CleanEmail
+// searches. Parts nested more deeply are ignored.
+const maxEmailDepth = 10
+
+// Email is the result of CleanEmail.
+type Email struct {
+	// The cleaned HTML body of the message.
+	HTML string
+
+	// Images that were attached to the message with a Content-ID, which
+	// the HTML may refer to using cid: URLs.
+	Images []InlineImage
+}
+
+// InlineImage is an image attached to an email.
+type InlineImage struct {
+	// The Content-ID of the image, without angle brackets.
+	ContentID string
+
+	// The media type of the image, such as "image/png".
+	ContentType string
+
+	// The decoded image data.
+	Data []byte
+}
+
+type mimeHeader interface {
+	Get(key string) string
+}
+
+// CleanEmail finds the text/html part of msg, decodes it, and cleans it
+// using the specified Config, or EmailConfig if it is nil. Images with a
+// Content-ID are returned alongside the HTML. Other parts are skipped without
+// being read.
+func CleanEmail(c *Config, msg *mail.Message) (*Email, error) {
+	if c == nil {
+		c = EmailConfig
+	}
+
+	e := &Email{}
+	body, err := findEmailHTML(e, msg.Header, msg.Body, 0)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, ErrNoHTML
+	}
+
+	e.HTML = Clean(c, string(body))
+
+	return e, nil
+}
+
+// findEmailHTML returns the decoded body of the first text/html part and
+// adds any inline images to e. depth is the number of enclosing multipart
+// parts.
+func findEmailHTML(e *Email, header mimeHeader, body io.Reader, depth int) ([]byte, error) {
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if depth >= maxEmailDepth {
+			return nil, nil
+		}
+
+		var found []byte
+		r := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := r.NextPart()
+			if err == io.EOF {
+				return found, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			b, err := findEmailHTML(e, part.Header, part, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if found == nil {
+				found = b
+			}
+		}
+	}
+
+	if mediaType == "text/html" {
+		r, err := charset.NewReader(decodeTransferEncoding(header, body), contentType)
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(r)
+	}
+
+	id := header.Get("Content-Id")
+	if id == "" || !strings.HasPrefix(mediaType, "image/") {
+		// the multipart reader skips the rest of the part
+		return nil, nil
+	}
+
+	decoded, err := io.ReadAll(decodeTransferEncoding(header, body))
+	if err != nil {
+		return nil, err
+	}
+	e.Images = append(e.Images, InlineImage{
+		ContentID:   strings.TrimSuffix(strings.TrimPrefix(id, "<"), ">"),
+		ContentType: mediaType,
+		Data:        decoded,
+	})
+
+	return nil, nil
+}
+
+func decodeTransferEncoding(header mimeHeader, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding"))) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &base64Cleaner{r: body})
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// base64Cleaner removes the line breaks from base64-encoded MIME parts.
+type base64Cleaner struct {
+	r io.Reader
+}
+
+func (b *base64Cleaner) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	j := 0
+	for _, c := range p[:n] {
+		if c != '\r' && c != '\n' && c != ' ' && c != '\t' {
+			p[j] = c
+			j++
+		}
+	}
+	return j, err
+}
@@ -0,0 +1,102 @@
+package htmlcleaner_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/BenLubar/htmlcleaner"
+)
+
+const testEmail = "From: a@example.com\r\n" +
+	"To: b@example.com\r\n" +
+	"Subject: test\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/related; boundary=\"rel\"\r\n" +
+	"\r\n" +
+	"--rel\r\n" +
+	"Content-Type: multipart/alternative; boundary=\"alt\"\r\n" +
+	"\r\n" +
+	"--alt\r\n" +
+	"Content-Type: text/plain; charset=us-ascii\r\n" +
+	"\r\n" +
+	"plain text\r\n" +
+	"--alt\r\n" +
+	"Content-Type: text/html; charset=iso-8859-1\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"<table><tr><td>caf=E9</td></tr></table><!--[if mso]>x<![endif]-->=\r\n" +
+	"<img src=3D\"cid:logo@example.com\" onerror=3D\"x()\"><script>y()</script>\r\n" +
+	"--alt--\r\n" +
+	"--rel\r\n" +
+	"Content-Type: application/octet-stream\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"not base64!\r\n" +
+	"--rel\r\n" +
+	"Content-Type: image/gif\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"Content-ID: <logo@example.com>\r\n" +
+	"\r\n" +
+	"R0lGODlh\r\n" +
+	"AQABAA==\r\n" +
+	"--rel--\r\n"
+
+func TestCleanEmail(t *testing.T) {
+	msg, err := mail.ReadMessage(strings.NewReader(testEmail))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := htmlcleaner.CleanEmail(nil, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<table><tbody><tr><td>café</td></tr></tbody></table><img src="cid:logo@example.com"/>&lt;script&gt;y()&lt;/script&gt;`
+	if e.HTML != expected {
+		t.Errorf("expected HTML %q", expected)
+		t.Errorf("actual HTML   %q", e.HTML)
+	}
+
+	if len(e.Images) != 1 {
+		t.Fatalf("expected 1 image, got %d", len(e.Images))
+	}
+	if img := e.Images[0]; img.ContentID != "logo@example.com" || img.ContentType != "image/gif" || string(img.Data) != "GIF89a\x01\x00\x01\x00" {
+		t.Errorf("unexpected image: %+v", img)
+	}
+}
+
+func TestCleanEmailNoHTML(t *testing.T) {
+	msg, err := mail.ReadMessage(strings.NewReader("Subject: test\r\n\r\nplain text\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = htmlcleaner.CleanEmail(nil, msg); err != htmlcleaner.ErrNoHTML {
+		t.Errorf("expected ErrNoHTML, got %v", err)
+	}
+}
+
+func TestCleanEmailTooDeep(t *testing.T) {
+	var b bytes.Buffer
+	b.WriteString("Content-Type: multipart/mixed; boundary=\"b0\"\r\n\r\n")
+	for i := 1; i < 20; i++ {
+		fmt.Fprintf(&b, "--b%d\r\nContent-Type: multipart/mixed; boundary=\"b%d\"\r\n\r\n", i-1, i)
+	}
+	b.WriteString("--b19\r\nContent-Type: text/html\r\n\r\n<b>deep</b>\r\n")
+	for i := 19; i >= 0; i-- {
+		fmt.Fprintf(&b, "--b%d--\r\n", i)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = htmlcleaner.CleanEmail(nil, msg); err != htmlcleaner.ErrNoHTML {
+		t.Errorf("expected ErrNoHTML, got %v", err)
+	}
+}
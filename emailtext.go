@@ -0,0 +1,81 @@
+package htmlcleaner
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// EmailTextWidth is the line length used by RenderEmailText.
+const EmailTextWidth = 78
+
+// RenderEmailText cleans a fragment of HTML using the specified Config, or the
+// FallbackConfig if it is nil, and converts it to plain text for the
+// text/plain part of an email. Links are written as "text <url>", lists are
+// bulleted with "-" or numbered, quotations are prefixed with "> ", and lines
+// are wrapped at EmailTextWidth columns, except in preformatted text and
+// words that are too long to fit.
+func RenderEmailText(c *Config, fragment string) string {
+	w := newTextWriter(emailTextElement)
+	w.width = EmailTextWidth
+	w.nodes(cleanFragment(c, fragment))
+
+	return w.String()
+}
+
+func emailTextElement(w *textWriter, n *html.Node) {
+	children := func() { w.children(n) }
+
+	switch n.DataAtom {
+	case atom.A:
+		children()
+		href, _ := getAttr(n, "href")
+		if text := textContent(n); href != "" && href != text && href != "mailto:"+text {
+			w.text(" <" + href + ">")
+		}
+	case atom.Img:
+		alt, _ := getAttr(n, "alt")
+		if alt == "" {
+			alt = "image"
+		}
+		w.text("[" + alt + "]")
+		if src, _ := getAttr(n, "src"); src != "" {
+			w.text(" <" + src + ">")
+		}
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.P:
+		w.block(2)
+		children()
+		w.block(2)
+	case atom.Hr:
+		w.block(2)
+		w.write(strings.Repeat("-", 20))
+		w.block(2)
+	case atom.Blockquote:
+		w.block(2)
+		w.indent("> ", children)
+		w.block(2)
+	case atom.Ul, atom.Ol:
+		w.block(1)
+		i := 0
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+				w.node(c)
+				continue
+			}
+			i++
+			marker := "- "
+			if n.DataAtom == atom.Ol {
+				marker = strconv.Itoa(i) + ". "
+			}
+			w.block(1)
+			w.write(marker)
+			w.indent(strings.Repeat(" ", len(marker)), func() { w.children(c) })
+			w.block(1)
+		}
+		w.block(1)
+	default:
+		w.defaultElement(n)
+	}
+}
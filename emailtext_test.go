@@ -0,0 +1,24 @@
+package htmlcleaner
+
+import "testing"
+
+var emailTextConfig = DefaultConfig.Clone().Elem("ul", "ol", "li", "h1", "hr")
+
+var testTableEmailText = []testTable{
+	{"Empty", ``, ``, emailTextConfig},
+	{"Paragraphs", `<h1>Title</h1><p>a</p><p>b</p>`, "Title\n\na\n\nb", emailTextConfig},
+	{"Link", `<a href="https://example.com/">example</a>`, "example <https://example.com/>", emailTextConfig},
+	{"LinkSameText", `<a href="https://example.com/">https://example.com/</a> <a href="mailto:a@example.com">a@example.com</a>`, "https://example.com/ a@example.com", emailTextConfig},
+	{"Image", `<img src="/a.png" alt="cat">`, "[cat] </a.png>", emailTextConfig},
+	{"Lists", `<ul><li>a</li><li>b</li></ul><ol><li>c</li></ol>`, "- a\n- b\n1. c", emailTextConfig},
+	{"Blockquote", `<blockquote><p>a</p><p>b</p></blockquote>c`, "> a\n>\n> b\n\nc", emailTextConfig},
+	{"Wrap", `<p>Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.</p>`, "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor\nincididunt ut labore et dolore magna aliqua.", emailTextConfig},
+	{"WrapQuote", `<blockquote>Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt</blockquote>`, "> Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod\n> tempor incididunt", emailTextConfig},
+	{"WrapList", `<ul><li>Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor</li></ul>`, "- Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod\n  tempor", emailTextConfig},
+	{"LongWord", `<p>a https://example.com/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa b</p>`, "a\nhttps://example.com/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\nb", emailTextConfig},
+	{"Pre", "<pre>Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt</pre>", "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt", emailTextConfig},
+}
+
+func TestRenderEmailText(t *testing.T) {
+	doTableTest(RenderEmailText, t, testTableEmailText)
+}
@@ -0,0 +1,51 @@
+package htmlcleaner
+
+import (
+	"regexp"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var emojiShortcode = regexp.MustCompile(`:([A-Za-z0-9_+-]+):`)
+
+// EmojiShortcodes adds a text rule that replaces shortcodes such as :smile:
+// using lookup, which is passed the name between the colons. If lookup
+// returns a non-empty src, the shortcode is replaced by an
+// <img class="emoji"> element with that src, which is added even if the Config
+// does not otherwise allow it. If lookup returns only text, the shortcode is
+// replaced by that text. If lookup returns false, the shortcode is left alone,
+// and its closing colon can start another shortcode, as in "10:00:smile:".
+//
+// Like other text rules, shortcodes inside <a>, <code>, and <pre> are not
+// replaced. The receiver is returned to allow call chaining.
+func (c *Config) EmojiShortcodes(lookup func(name string) (text, src string, ok bool)) *Config {
+	c.textRules = append(c.textRules, textRule{
+		re:      emojiShortcode,
+		trusted: true,
+		adds:    map[atom.Atom][]string{atom.Img: {"class", "src", "alt", "title"}},
+		build: func(m []string) ([]*html.Node, bool) {
+			s, src, ok := lookup(m[1])
+			if !ok {
+				// the closing colon may start another shortcode
+				return nil, false
+			}
+			if src == "" {
+				return []*html.Node{text(s)}, true
+			}
+
+			return []*html.Node{{
+				Type:     html.ElementNode,
+				Data:     "img",
+				DataAtom: atom.Img,
+				Attr: []html.Attribute{
+					{Key: "class", Val: "emoji"},
+					{Key: "src", Val: src},
+					{Key: "alt", Val: m[0]},
+					{Key: "title", Val: m[0]},
+				},
+			}}, true
+		},
+	})
+	return c
+}
@@ -0,0 +1,48 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/html/atom"
+)
+
+var emojiConfig = (&Config{}).ElemAtom(atom.Code).EmojiShortcodes(func(name string) (string, string, bool) {
+	switch name {
+	case "smile":
+		return "😄", "", true
+	case "party_parrot":
+		return "", "/emoji/party_parrot.gif", true
+	}
+	return "", "", false
+})
+
+var testTableEmoji = []testTable{
+	{"Text", `hi :smile:!`, `hi 😄!`, emojiConfig},
+	{"Image", `:party_parrot:`, `<img class="emoji" src="/emoji/party_parrot.gif" alt=":party_parrot:" title=":party_parrot:"/>`, emojiConfig},
+	{"Unknown", `:unknown: :smile:`, `:unknown: 😄`, emojiConfig},
+	{"Time", `12:30:45`, `12:30:45`, emojiConfig},
+	{"AfterTime", `10:00:smile:`, `10:00😄`, emojiConfig},
+	{"AfterUnknown", `:unknown:smile:`, `:unknown😄`, emojiConfig},
+	{"Adjacent", `:smile::smile:`, `😄😄`, emojiConfig},
+	{"Code", `<code>:smile:</code>`, `<code>:smile:</code>`, emojiConfig},
+	{"UserImage", `<img src="/emoji/party_parrot.gif">`, `&lt;img src=&#34;/emoji/party_parrot.gif&#34;/&gt;`, emojiConfig},
+}
+
+func TestEmojiShortcodes(t *testing.T) {
+	doTableTest(Clean, t, testTableEmoji)
+}
+
+func TestEmojiShortcodesLarge(t *testing.T) {
+	input := strings.Repeat(":unknown:", 20000) + ":smile:"
+
+	start := time.Now()
+	output := Clean(emojiConfig, input)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("took %v", elapsed)
+	}
+	if !strings.HasSuffix(output, ":unknown:😄") {
+		t.Errorf("unexpected output suffix %q", output[len(output)-20:])
+	}
+}
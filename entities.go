@@ -0,0 +1,140 @@
+package htmlcleaner
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// NamedEntities makes Clean write the characters for the specified named
+// character references, such as "nbsp" or "mdash", as references instead of
+// literal characters. Names for references that expand to more than one
+// character are ignored, and names for ASCII characters are only used for
+// characters given to AlwaysEscape, as ASCII characters are otherwise
+// already escaped where needed. It panics if a name is not a known
+// character reference. The receiver is returned to allow call chaining.
+func (c *Config) NamedEntities(names ...string) *Config {
+	for _, name := range names {
+		ref := "&" + strings.TrimSuffix(strings.TrimPrefix(name, "&"), ";") + ";"
+		// Legacy references such as &not can be recognized without a
+		// semicolon, so an unknown name can still change the string.
+		s := html.UnescapeString(ref)
+		if s == ref || utf8.RuneCountInString(s) > 2 {
+			panic("htmlcleaner: unknown character reference " + ref)
+		}
+
+		r, size := utf8.DecodeRuneInString(s)
+		if size != len(s) {
+			continue
+		}
+
+		if c.namedEntities == nil {
+			c.namedEntities = make(map[rune]string)
+		}
+		c.namedEntities[r] = ref
+	}
+
+	return c
+}
+
+// AlwaysEscape makes Clean write each of the specified characters as a
+// character reference wherever it appears in text or attribute values,
+// using the name given to NamedEntities if there is one and a numeric
+// reference otherwise. This can protect output from systems that treat
+// characters such as '{' or '`' specially. The characters &, <, >, ", and '
+// are always escaped already and are ignored. The receiver is returned to
+// allow call chaining.
+func (c *Config) AlwaysEscape(chars string) *Config {
+	for _, r := range chars {
+		if strings.ContainsRune(`&<>"'`, r) {
+			continue
+		}
+
+		if c.escapeChars == nil {
+			c.escapeChars = make(map[rune]struct{})
+		}
+		c.escapeChars[r] = struct{}{}
+	}
+
+	return c
+}
+
+// rawTextElements are the elements whose contents are not parsed for
+// character references.
+var rawTextElements = map[string]bool{
+	"iframe":    true,
+	"noembed":   true,
+	"noframes":  true,
+	"noscript":  true,
+	"plaintext": true,
+	"script":    true,
+	"style":     true,
+	"xmp":       true,
+}
+
+// encodeEntities rewrites characters in rendered HTML according to
+// NamedEntities, AlwaysEscape, and NumericEntities.
+func (c *Config) encodeEntities(rendered string) string {
+	if len(c.namedEntities) == 0 && len(c.escapeChars) == 0 && !c.NumericEntities {
+		return rendered
+	}
+
+	var buf bytes.Buffer
+	raw := false
+
+	t := html.NewTokenizer(strings.NewReader(rendered))
+	for {
+		tok := t.Next()
+		switch tok {
+		case html.ErrorToken:
+			// The input was produced by Render, so the only
+			// possible error is the end of the input.
+			expectError(t.Err(), io.EOF)
+			return buf.String()
+		case html.TextToken:
+			if raw {
+				buf.Write(t.Raw())
+			} else {
+				buf.WriteString(c.encodeChars(string(t.Raw())))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := t.Token()
+			raw = tok == html.StartTagToken && rawTextElements[token.Data]
+
+			buf.WriteString("<" + token.Data)
+			for _, a := range token.Attr {
+				buf.WriteString(" ")
+				if a.Namespace != "" {
+					buf.WriteString(a.Namespace + ":")
+				}
+				buf.WriteString(a.Key + `="` + c.encodeChars(html.EscapeString(a.Val)) + `"`)
+			}
+			if tok == html.SelfClosingTagToken {
+				buf.WriteString("/")
+			}
+			buf.WriteString(">")
+		default:
+			raw = false
+			buf.Write(t.Raw())
+		}
+	}
+}
+
+func (c *Config) encodeChars(s string) string {
+	var buf bytes.Buffer
+	for _, r := range s {
+		_, escape := c.escapeChars[r]
+		if ref, ok := c.namedEntities[r]; ok && (escape || r >= utf8.RuneSelf) {
+			buf.WriteString(ref)
+		} else if escape || (r >= utf8.RuneSelf && c.NumericEntities) {
+			buf.WriteString("&#" + strconv.Itoa(int(r)) + ";")
+		} else {
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,42 @@
+package htmlcleaner
+
+import "testing"
+
+var namedEntityConfig = DefaultConfig.Clone().NamedEntities("nbsp", "&mdash;", "amp", "NotEqualTilde")
+
+var numericEntityConfig = func() *Config {
+	c := DefaultConfig.Clone().NamedEntities("nbsp")
+
+	c.NumericEntities = true
+
+	return c
+}()
+
+var escapeCharsConfig = DefaultConfig.Clone().NamedEntities("lbrace").AlwaysEscape("{}`é&<")
+
+var testTableEntities = []testTable{
+	{"Default", `a&nbsp;b&#160;c&mdash;é`, "a b c—é", nil},
+	{"Named", `a&nbsp;b&#160;c&mdash;é &amp; &lt;`, `a&nbsp;b&nbsp;c&mdash;é &amp; &lt;`, namedEntityConfig},
+	{"NamedAttr", `<a title="a&nbsp;b&quot;">c</a>`, `<a title="a&nbsp;b&#34;">c</a>`, namedEntityConfig},
+	{"Numeric", `a&nbsp;b&mdash;é😀`, `a&nbsp;b&#8212;&#233;&#128512;`, numericEntityConfig},
+	{"NumericAttr", `<img src="/é.png" alt="é">`, `<img src="/%C3%A9.png" alt="&#233;"/>`, numericEntityConfig},
+	{"Comment", `<!--é-->`, `<!--é-->`, numericEntityConfig},
+	{"RawText", `<style>é</style>`, `<style>é</style>`, numericEntityConfig.Clone().Elem("style")},
+	{"AlwaysEscape", "{{x}} `y` é &amp; &lt; ü", "&lbrace;&lbrace;x&#125;&#125; &#96;y&#96; &#233; &amp; &lt; ü", escapeCharsConfig},
+	{"AlwaysEscapeAttr", `<a title="{x}">y</a>`, `<a title="&lbrace;x&#125;">y</a>`, escapeCharsConfig},
+	{"AlwaysEscapeRawText", `<style>{}</style>`, `<style>{}</style>`, escapeCharsConfig.Clone().Elem("style")},
+}
+
+func TestEntities(t *testing.T) {
+	doTableTest(Clean, t, testTableEntities)
+}
+
+func TestNamedEntitiesUnknown(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic for an unknown name")
+		}
+	}()
+
+	(&Config{}).NamedEntities("notarealentity")
+}
@@ -0,0 +1,111 @@
+package htmlcleaner
+
+import (
+	"bytes"
+	"errors"
+
+	"golang.org/x/net/html"
+)
+
+// Errors returned by the functions whose names end in E, such as CleanE,
+// for content that was removed or limited. The output is still safe to use.
+var (
+	// ErrTooDeep means nodes nested more deeply than the depth limit
+	// were omitted.
+	ErrTooDeep = errors.New("htmlcleaner: fragment is nested too deeply")
+
+	// ErrTooLarge means the input or output was cut short by a size
+	// limit, such as MaxInputBytes or MaxExpansion.
+	ErrTooLarge = errors.New("htmlcleaner: output is too large")
+
+	// ErrBadURL means a URL was removed because it was not allowed.
+	ErrBadURL = errors.New("htmlcleaner: URL is not allowed")
+)
+
+// unexpectedError is the panic value of expectError. The functions whose
+// names end in E recover it and return the error instead.
+type unexpectedError struct {
+	err error
+}
+
+func (e unexpectedError) Error() string {
+	return "htmlcleaner: unexpected error: " + e.err.Error()
+}
+
+// catchError is deferred by the functions whose names end in E. It recovers
+// from a panic by expectError and sets *err. Other panics, such as from a
+// function set in the Config, continue to the caller.
+func catchError(err *error) {
+	if r := recover(); r != nil {
+		e, ok := r.(unexpectedError)
+		if !ok {
+			panic(r)
+		}
+		*err = e.err
+	}
+}
+
+// parse is Parse, remembering whether nodes were omitted for CleanE.
+func (c *cleaner) parse(fragment string) []*html.Node {
+	nodes, omitted := parseDepth(fragment, DefaultMaxDepth)
+	if omitted {
+		c.tooDeep = true
+	}
+	return nodes
+}
+
+// ParseE is like Parse, but returns ErrTooDeep along with the nodes if any
+// nodes were omitted, and returns other errors instead of panicking.
+func ParseE(fragment string) ([]*html.Node, error) {
+	return ParseDepthE(fragment, DefaultMaxDepth)
+}
+
+// ParseDepthE is like ParseDepth, but returns ErrTooDeep along with the
+// nodes if any nodes were omitted, and returns other errors instead of
+// panicking.
+func ParseDepthE(fragment string, maxDepth int) (nodes []*html.Node, err error) {
+	defer catchError(&err)
+
+	nodes, omitted := parseDepth(fragment, maxDepth)
+	if omitted {
+		err = ErrTooDeep
+	}
+	return nodes, err
+}
+
+// RenderE is like Render, but returns an error instead of panicking if the
+// nodes cannot be rendered, such as a void element with children.
+func RenderE(nodes ...*html.Node) (string, error) {
+	var buf bytes.Buffer
+
+	for _, n := range nodes {
+		if err := html.Render(&buf, n); err != nil {
+			return "", err
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// CleanE is like Clean, but returns an error instead of panicking if
+// cleaning fails, such as if a node created by HighlightCode or a text rule
+// cannot be rendered, in which case the output is empty. If cleaning
+// succeeds but content was removed or limited, the output is returned with
+// ErrTooLarge, ErrTooDeep, or ErrBadURL, in that order of precedence. If
+// FailureAction is FailureEscape, failures are handled as they are by Clean.
+func CleanE(c *Config, fragment string) (output string, err error) {
+	defer catchError(&err)
+
+	cl := newCleaner(c)
+	output = cl.clean(fragment, nil)
+
+	switch {
+	case cl.inputExceeded || cl.expansionExceeded != 0:
+		err = ErrTooLarge
+	case cl.tooDeep:
+		err = ErrTooDeep
+	case cl.rejectedURLs != 0:
+		err = ErrBadURL
+	}
+	return output, err
+}
@@ -0,0 +1,76 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func TestCleanE(t *testing.T) {
+	deep := strings.Repeat("<b>", DefaultMaxDepth+1) + "x"
+	bad := (&Config{ValidateURL: SafeURLScheme}).Elem("p").ElemAttr("a", "href")
+
+	for _, tt := range []struct {
+		name  string
+		c     *Config
+		input string
+		err   error
+	}{
+		{"OK", nil, `<b>x</b>`, nil},
+		{"TooDeep", nil, deep, ErrTooDeep},
+		{"TooLarge", expansionAbortConfig, strings.Repeat(`<p><b>"a"</b><i>b</i><br></p>`, 1000), ErrTooLarge},
+		{"BadURL", bad, `<a href="javascript:alert(1)">x</a>`, ErrBadURL},
+	} {
+		output, err := CleanE(tt.c, tt.input)
+		if err != tt.err {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.err, err)
+		}
+		if expected := Clean(tt.c, tt.input); output != expected {
+			t.Errorf("%s: expected %q, actual %q", tt.name, expected, output)
+		}
+	}
+}
+
+func TestCleanEFailure(t *testing.T) {
+	c := DefaultConfig.Clone().Elem("pre")
+	c.HighlightCode = func(lang, code string) []*html.Node {
+		br := &html.Node{Type: html.ElementNode, Data: "br", DataAtom: atom.Br}
+		br.AppendChild(text(code))
+		return []*html.Node{br}
+	}
+
+	output, err := CleanE(c, `<pre><code>x</code></pre>`)
+	if err == nil || output != "" {
+		t.Errorf("expected an error, got %q", output)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Clean to panic")
+		}
+	}()
+	Clean(c, `<pre><code>x</code></pre>`)
+}
+
+func TestParseE(t *testing.T) {
+	if nodes, err := ParseE(`<b>x</b>`); err != nil || len(nodes) != 1 {
+		t.Errorf("unexpected result %v, %v", nodes, err)
+	}
+	if nodes, err := ParseDepthE(`<b><i>x</i></b>`, 2); err != ErrTooDeep || Render(nodes...) != `<b><i>[omitted]</i></b>` {
+		t.Errorf("unexpected result %q, %v", Render(nodes...), err)
+	}
+}
+
+func TestRenderE(t *testing.T) {
+	br := &html.Node{Type: html.ElementNode, Data: "br", DataAtom: atom.Br}
+	if output, err := RenderE(br); err != nil || output != `<br/>` {
+		t.Errorf("unexpected result %q, %v", output, err)
+	}
+
+	br.AppendChild(text("x"))
+	if _, err := RenderE(br); err == nil {
+		t.Error("expected an error")
+	}
+}
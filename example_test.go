@@ -47,3 +47,21 @@ func ExampleCleanNode() {
 	// <a href="http://golang.org/" title="Go">hello</a>
 	// &lt;script&gt;malicious()&lt;/script&gt;
 }
+
+func ExampleConfig_Allow() {
+	config := (&htmlcleaner.Config{
+		WrapText: true,
+	}).Allow(htmlcleaner.AllowList{
+		Elems: map[string][]string{
+			"p": {},
+			"a": {"href"},
+		},
+		GlobalAttrs: []string{"title"},
+		Unwrap:      []string{"div"},
+	})
+
+	fmt.Println(htmlcleaner.Clean(config, `<div><a href="/" title="Home" class="x">hello</a></div>`))
+
+	// Output:
+	// <p><a href="/" title="Home">hello</a></p>
+}
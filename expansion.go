@@ -0,0 +1,220 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// ExpansionAction is what Clean does when its output is too large compared
+// to its input. See Config.MaxExpansion.
+type ExpansionAction int
+
+const (
+	// ExpansionTruncate removes nodes from the end of the output until it
+	// fits, keeping the output well-formed.
+	ExpansionTruncate ExpansionAction = iota
+
+	// ExpansionAbort makes Clean return an empty string.
+	ExpansionAbort
+)
+
+// minExpansionLimit is the output size that MaxExpansion always allows, so
+// that very short inputs are not truncated by wrapping and escaping.
+const minExpansionLimit = 1024
+
+// expansionLimit returns the maximum output size for an input of n bytes,
+// or -1 if there is no limit.
+func (c *Config) expansionLimit(n int) int {
+	if c.MaxExpansion <= 0 {
+		return -1
+	}
+	limit := int(c.MaxExpansion * float64(n))
+	if limit < minExpansionLimit {
+		limit = minExpansionLimit
+	}
+	return limit
+}
+
+// limitExpansion applies MaxExpansion to the cleaned nodes of an input of n
+// bytes.
+func (c *cleaner) limitExpansion(nodes []*html.Node, n int) []*html.Node {
+	limit := c.expansionLimit(n)
+	if limit < 0 {
+		return nodes
+	}
+
+	sizes := renderedSizes{}
+	total := 0
+	for _, n := range nodes {
+		total += sizes.size(n)
+	}
+	if total <= limit {
+		return nodes
+	}
+
+	c.expansionExceeded++
+	if c.ExpansionAction == ExpansionAbort {
+		return nil
+	}
+	return sizes.truncate(nodes, limit)
+}
+
+// truncateRendered returns the longest prefix of nodes, descending into
+// elements and text, that renders to at most limit bytes.
+func truncateRendered(nodes []*html.Node, limit int) []*html.Node {
+	return renderedSizes{}.truncate(nodes, limit)
+}
+
+// renderedSizes remembers the number of bytes each node renders to, so that
+// each node is only measured once.
+type renderedSizes map[*html.Node]int
+
+// literalTextElements are the elements whose text is rendered without
+// escaping.
+var literalTextElements = map[string]bool{
+	"iframe":    true,
+	"noembed":   true,
+	"noframes":  true,
+	"noscript":  true,
+	"plaintext": true,
+	"script":    true,
+	"style":     true,
+	"xmp":       true,
+}
+
+// literalText reports whether the text node n is rendered without escaping.
+func literalText(n *html.Node) bool {
+	p := n.Parent
+	return p != nil && p.Type == html.ElementNode && p.Namespace == "" && literalTextElements[p.Data]
+}
+
+// size returns the number of bytes n renders to.
+func (sizes renderedSizes) size(n *html.Node) int {
+	if size, ok := sizes[n]; ok {
+		return size
+	}
+
+	var size int
+	switch n.Type {
+	case html.TextNode:
+		size = len(n.Data)
+		if !literalText(n) {
+			size = escapedPrefix(n.Data, -1)
+		}
+	case html.ElementNode:
+		size = tagSize(n)
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			size += sizes.size(c)
+		}
+	default:
+		size = len(Render(n))
+	}
+
+	sizes[n] = size
+	return size
+}
+
+// tagSize returns the number of bytes the tags of the element n render to,
+// without its children.
+func tagSize(n *html.Node) int {
+	size := len(Render(&html.Node{
+		Type:      n.Type,
+		Data:      n.Data,
+		DataAtom:  n.DataAtom,
+		Namespace: n.Namespace,
+		Attr:      n.Attr,
+	}))
+	if c := n.FirstChild; c != nil && c.Type == html.TextNode && strings.HasPrefix(c.Data, "\n") {
+		switch n.Data {
+		case "pre", "listing", "textarea":
+			size++
+		}
+	}
+	return size
+}
+
+// escapedPrefix returns the length of the longest prefix of s whose escaped
+// form fits in limit bytes, or the length of all of s escaped if limit is
+// negative.
+func escapedPrefix(s string, limit int) int {
+	used := 0
+	for i, r := range s {
+		size := utf8.RuneLen(r)
+		if r == utf8.RuneError {
+			_, size = utf8.DecodeRuneInString(s[i:])
+		}
+		if r < utf8.RuneSelf {
+			size = len(html.EscapeString(s[i : i+1]))
+		}
+		if limit >= 0 && used+size > limit {
+			return i
+		}
+		used += size
+	}
+	if limit >= 0 {
+		return len(s)
+	}
+	return used
+}
+
+// truncate returns the longest prefix of nodes, descending into elements
+// and text, that renders to at most limit bytes.
+func (sizes renderedSizes) truncate(nodes []*html.Node, limit int) []*html.Node {
+	used := 0
+	for i, n := range nodes {
+		size := sizes.size(n)
+		if used+size <= limit {
+			used += size
+			continue
+		}
+
+		if sizes.truncateNode(n, limit-used) {
+			return nodes[:i+1]
+		}
+		return nodes[:i]
+	}
+	return nodes
+}
+
+// truncateNode shortens n to render to at most limit bytes. It reports
+// false if nothing of n can be kept.
+func (sizes renderedSizes) truncateNode(n *html.Node, limit int) bool {
+	switch n.Type {
+	case html.TextNode:
+		if literalText(n) {
+			n.Data = n.Data[:limit]
+			for !utf8.ValidString(n.Data) && n.Data != "" {
+				_, size := utf8.DecodeLastRuneInString(n.Data)
+				n.Data = n.Data[:len(n.Data)-size]
+			}
+		} else {
+			n.Data = n.Data[:escapedPrefix(n.Data, limit)]
+		}
+		delete(sizes, n)
+		return n.Data != ""
+
+	case html.ElementNode:
+		if voidElements[n.DataAtom] {
+			return false
+		}
+
+		overhead := tagSize(n)
+		if overhead >= limit {
+			return false
+		}
+
+		// the children stay attached while they are truncated, so that
+		// text can tell whether it is escaped
+		children := childNodes(n)
+		kept := sizes.truncate(children, limit-overhead)
+		for _, child := range children[len(kept):] {
+			n.RemoveChild(child)
+		}
+		delete(sizes, n)
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,87 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var expansionAbortConfig = func() *Config {
+	c := (&Config{}).Elem("p", "b", "br")
+
+	c.MaxExpansion = 1.5
+	c.ExpansionAction = ExpansionAbort
+
+	return c
+}()
+
+var expansionTruncateConfig = func() *Config {
+	c := expansionAbortConfig.Clone()
+
+	c.ExpansionAction = ExpansionTruncate
+
+	return c
+}()
+
+func TestMaxExpansion(t *testing.T) {
+	small := `<i>x</i>`
+	if output := Clean(expansionAbortConfig, small); output != `&lt;i&gt;x&lt;/i&gt;` {
+		t.Errorf("short input should not be limited: %q", output)
+	}
+
+	input := strings.Repeat(`<p><b>"a"</b><i>b</i><br></p>`, 1000)
+	limit := len(input) * 3 / 2
+
+	if output := Clean(expansionAbortConfig, input); output != "" {
+		t.Errorf("expected empty output, got %d bytes", len(output))
+	}
+
+	output := Clean(expansionTruncateConfig, input)
+	if len(output) > limit || len(output) < limit-100 {
+		t.Errorf("expected about %d bytes, got %d", limit, len(output))
+	}
+	if err := expansionTruncateConfig.CheckOutput(output); err != nil {
+		t.Error(err)
+	}
+	if !strings.HasPrefix(Clean(expansionAbortConfig.Clone().Elem("i"), input), "<p>") {
+		t.Error("output within the limit should not change")
+	}
+}
+
+func TestTruncateRendered(t *testing.T) {
+	for limit, expected := range map[int]string{
+		0:  ``,
+		6:  ``,
+		8:  `<p>a</p>`,
+		12: `<p>a</p>`,
+		13: `<p>a&amp;</p>`,
+		14: `<p>a&amp;b</p>`,
+		19: `<p>a&amp;b<br/></p>`,
+		20: `<p>a&amp;b<br/></p>c`,
+	} {
+		output := Render(truncateRendered(Parse(`<p>a&amp;b<br></p>cd`), limit)...)
+		if output != expected {
+			t.Errorf("limit %d: expected %q, got %q", limit, expected, output)
+		}
+	}
+}
+
+func TestMaxExpansionLinear(t *testing.T) {
+	input := strings.Repeat("<", 80000)
+
+	start := time.Now()
+	output := Clean(expansionTruncateConfig, input)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("took %v", elapsed)
+	}
+	if limit := len(input) * 3 / 2; len(output) > limit || len(output) < limit-4 {
+		t.Errorf("expected about %d bytes, got %d", limit, len(output))
+	}
+}
+
+func TestTruncateRenderedLiteral(t *testing.T) {
+	nodes := ParseDepth(`<noscript>a&b</noscript>`, 0)
+	if output := Render(truncateRendered(nodes, len(`<noscript>a&</noscript>`))...); output != `<noscript>a&</noscript>` {
+		t.Errorf("unexpected output %q", output)
+	}
+}
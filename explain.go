@@ -0,0 +1,75 @@
+package htmlcleaner
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/net/html"
+)
+
+// Explain cleans fragment like Clean, and returns a human-readable
+// description of each decision made about an element or attribute, one per
+// line, followed by the output. It is meant for finding out why a particular
+// input was changed, not for use in production, so Shadow is not used.
+func Explain(c *Config, fragment string) string {
+	cl := newCleaner(c)
+
+	var buf bytes.Buffer
+	cl.explain = &buf
+
+	output := cl.clean(fragment, nil)
+
+	fmt.Fprintf(&buf, "output: %s\n", output)
+	return buf.String()
+}
+
+// explainNote records a decision about the whole fragment for Explain.
+func (c *cleaner) explainNote(format string, args ...interface{}) {
+	if c.explain == nil {
+		return
+	}
+
+	fmt.Fprintf(c.explain, format, args...)
+	c.explain.WriteByte('\n')
+}
+
+// explainf records a decision about n for Explain. The elements enclosing n
+// are listed first.
+func (c *cleaner) explainf(n *html.Node, format string, args ...interface{}) {
+	if c.explain == nil {
+		return
+	}
+
+	for _, a := range c.stack {
+		c.explain.WriteString("<" + a.Data + "> ")
+	}
+	switch n.Type {
+	case html.ElementNode:
+		c.explain.WriteString("<" + n.Data + ">")
+	case html.CommentNode:
+		c.explain.WriteString("comment")
+	case html.DoctypeNode:
+		c.explain.WriteString("doctype")
+	default:
+		c.explain.WriteString("node")
+	}
+	c.explain.WriteString(": ")
+	fmt.Fprintf(c.explain, format, args...)
+	c.explain.WriteByte('\n')
+}
+
+// limitVerb describes what was done to input or output that was too long.
+func limitVerb(abort bool) string {
+	if abort {
+		return "discarded"
+	}
+	return "cut short"
+}
+
+var attrReasons = [...]string{
+	attrNotAllowed: "not allowed",
+	attrBadURL:     "URL rejected",
+	attrNoMatch:    "value does not match",
+	attrTooLong:    "value too long",
+	attrUnsafe:     "unsafe",
+}
@@ -0,0 +1,45 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	c := DefaultConfig.Clone().MaxElem("img", 1).Unwrap("font")
+	c.MaxElemPlaceholder = "[image]"
+
+	const expected = `<p> <a>: removed attribute onclick="x()": not allowed
+<p> <a>: kept
+<p> <a>: removed attribute href="javascript:x()": URL rejected
+<p> <a>: kept
+<p> <font>: unwrapped: not allowed
+<p> <script>: escaped: not allowed
+<p>: kept
+<img>: kept
+<img>: replaced with MaxElemPlaceholder: more than MaxElem
+output: <p><a href="https://example.com/">a</a><a>b</a>c&lt;script&gt;d&lt;/script&gt;</p><img src="/1.png"/>[image]
+`
+	actual := Explain(c, `<p><a href="https://example.com/" onclick="x()">a</a><a href="javascript:x()">b</a><font>c</font><script>d</script></p><img src="/1.png"><img src="/2.png">`)
+	if actual != expected {
+		t.Errorf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+}
+
+func TestExplainLimits(t *testing.T) {
+	c := DefaultConfig.Clone()
+	c.MaxInputBytes = 20
+	c.MaxCharRefs = 1
+
+	const expected = `input is longer than MaxInputBytes, so it was cut short
+character references over MaxCharRefs or MaxCharRefLength: 1
+<b>: kept
+output: <b>&amp;&amp;amp;</b>xyz
+`
+	if actual := Explain(c, "<b>&amp;&amp;</b>xyzxyz"); actual != expected {
+		t.Errorf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+	if actual, clean := Explain(c, "<b>&amp;&amp;</b>xyzxyz"), Clean(c, "<b>&amp;&amp;</b>xyzxyz"); !strings.HasSuffix(actual, "output: "+clean+"\n") {
+		t.Errorf("Explain output %q does not match Clean output %q", actual, clean)
+	}
+}
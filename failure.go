@@ -0,0 +1,51 @@
+package htmlcleaner
+
+import (
+	"golang.org/x/net/html"
+)
+
+// FailureAction is what Preprocess, Clean, and CleanNodes do if they fail
+// unexpectedly, such as by a bug in this package or a panic in a function
+// set in the Config. See Config.FailureAction.
+type FailureAction int
+
+const (
+	// FailurePanic lets the panic continue to the caller.
+	FailurePanic FailureAction = iota
+
+	// FailureEscape recovers from the panic and returns the whole input
+	// as escaped text, so that no content is lost.
+	FailureEscape
+)
+
+// recoverString is deferred by Preprocess and Clean. If c.FailureAction is
+// FailureEscape, it recovers from a panic and replaces *output with the
+// escaped fragment.
+func (c *Config) recoverString(output *string, fragment string, span Span) {
+	if c.FailureAction != FailureEscape {
+		return
+	}
+
+	if r := recover(); r != nil {
+		*output = html.EscapeString(fragment)
+		span.SetAttribute("failed", 1)
+	}
+}
+
+// recoverNodes is deferred by CleanNodes. If c.FailureAction is
+// FailureEscape, it recovers from a panic and replaces *output with a text
+// node holding the rendered input, or with nothing if the input cannot be
+// rendered.
+func (c *Config) recoverNodes(output *[]*html.Node, input []*html.Node, span Span) {
+	if c.FailureAction != FailureEscape {
+		return
+	}
+
+	if r := recover(); r != nil {
+		*output = nil
+		if rendered, err := RenderE(input...); err == nil {
+			*output = []*html.Node{text(rendered)}
+		}
+		span.SetAttribute("failed", 1)
+	}
+}
@@ -0,0 +1,70 @@
+package htmlcleaner_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/BenLubar/htmlcleaner"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func failingConfig(action htmlcleaner.FailureAction) *htmlcleaner.Config {
+	c := htmlcleaner.DefaultConfig.Clone()
+	c.ValidateURL = func(*url.URL) bool {
+		panic("broken")
+	}
+	c.FailureAction = action
+	return c
+}
+
+func TestFailureEscape(t *testing.T) {
+	tracer := &recordingTracer{}
+	c := failingConfig(htmlcleaner.FailureEscape)
+	c.Tracer = tracer
+
+	input := `<p>see <a href="https://example.com/">this</a></p>`
+	expected := `&lt;p&gt;see &lt;a href=&#34;https://example.com/&#34;&gt;this&lt;/a&gt;&lt;/p&gt;`
+	if actual := htmlcleaner.Clean(c, input); actual != expected {
+		t.Errorf("expected %q, actual %q", expected, actual)
+	}
+	if len(tracer.spans) != 1 || tracer.spans[0].attrs["failed"] != 1 || !tracer.spans[0].ended {
+		t.Errorf("unexpected spans %+v", tracer.spans)
+	}
+
+	nodes := htmlcleaner.Parse(input)
+	if actual := htmlcleaner.Render(htmlcleaner.CleanNodes(c, nodes)...); actual != expected {
+		t.Errorf("expected %q, actual %q", expected, actual)
+	}
+	if actual := htmlcleaner.Render(nodes...); actual != input {
+		t.Errorf("input was modified: %q", actual)
+	}
+
+	// Inputs that do not fail are cleaned normally.
+	if actual := htmlcleaner.Clean(c, `<b>ok</b><i onclick="x()">`); actual != `<b>ok</b><i></i>` {
+		t.Errorf("unexpected output %q", actual)
+	}
+}
+
+func TestFailureEscapeUnrenderable(t *testing.T) {
+	c := failingConfig(htmlcleaner.FailureEscape)
+
+	// a void element with children cannot be rendered
+	br := &html.Node{Type: html.ElementNode, Data: "br", DataAtom: atom.Br}
+	br.AppendChild(htmlcleaner.Parse(`<a href="https://example.com/">this</a>`)[0])
+
+	if actual := htmlcleaner.CleanNodes(c, []*html.Node{br}); len(actual) != 0 {
+		t.Errorf("unexpected output %q", htmlcleaner.Render(actual...))
+	}
+}
+
+func TestFailurePanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "broken" {
+			t.Errorf("unexpected panic %v", r)
+		}
+	}()
+
+	htmlcleaner.Clean(failingConfig(htmlcleaner.FailurePanic), `<a href="/">x</a>`)
+	t.Error("expected a panic")
+}
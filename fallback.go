@@ -0,0 +1,25 @@
+package htmlcleaner
+
+import "sync/atomic"
+
+// fallback holds the *Config set by SetFallbackConfig.
+var fallback atomic.Value
+
+// SetFallbackConfig sets the Config that is used in place of a nil Config,
+// so that an organization can make its own hardened policy the default for
+// a whole program. Wherever this package documents that DefaultConfig is
+// used for a nil Config, the fallback is used instead. Passing nil restores
+// DefaultConfig. It is safe to call at any time, but is meant to be called
+// once during initialization.
+func SetFallbackConfig(c *Config) {
+	fallback.Store(c)
+}
+
+// FallbackConfig returns the Config that is used in place of a nil Config:
+// the one given to SetFallbackConfig, or DefaultConfig.
+func FallbackConfig() *Config {
+	if c, _ := fallback.Load().(*Config); c != nil {
+		return c
+	}
+	return DefaultConfig
+}
@@ -0,0 +1,21 @@
+package htmlcleaner
+
+import "testing"
+
+func TestSetFallbackConfig(t *testing.T) {
+	strict := (&Config{}).Elem("p")
+	SetFallbackConfig(strict)
+	defer SetFallbackConfig(nil)
+
+	if actual, expected := Clean(nil, `<p><b>a</b></p>`), `<p>&lt;b&gt;a&lt;/b&gt;</p>`; actual != expected {
+		t.Errorf("expected %q, actual %q", expected, actual)
+	}
+	if FallbackConfig() != strict {
+		t.Error("FallbackConfig did not return the fallback")
+	}
+
+	SetFallbackConfig(nil)
+	if actual, expected := Clean(nil, `<p><b>a</b></p>`), `<p><b>a</b></p>`; actual != expected {
+		t.Errorf("expected %q, actual %q", expected, actual)
+	}
+}
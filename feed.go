@@ -0,0 +1,72 @@
+package htmlcleaner
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// FeedConfig is a Config suitable for the content of RSS and Atom feed
+// entries. It allows the text and list elements that feed readers display
+// well, escapes comments, and unwraps tables, which many readers lay out
+// badly. To keep tables, allow their elements with ElemAtom on a clone.
+var FeedConfig = DefaultConfig.Clone().
+	SetEscapeComments(true).
+	ElemAtom(atom.Br, atom.Hr, atom.Div, atom.Span).
+	ElemAtom(atom.Ul, atom.Ol, atom.Li, atom.Dl, atom.Dt, atom.Dd).
+	ElemAtom(atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6).
+	ElemAtom(atom.Figure, atom.Figcaption).
+	UnwrapAtom(atom.Table, atom.Caption, atom.Thead, atom.Tbody, atom.Tfoot, atom.Tr, atom.Td, atom.Th).
+	UnwrapAtom(atom.Form, atom.Fieldset, atom.Label)
+
+// CleanForFeed cleans a fragment of HTML using the specified Config, or
+// FeedConfig if it is nil, for use in a feed entry. Relative URLs are resolved
+// against base, if it is not nil, as feed readers do not know the address of
+// the page the content came from. The output never contains "]]>", so it can
+// be wrapped in a CDATA section.
+func CleanForFeed(c *Config, fragment string, base *url.URL) string {
+	if c == nil {
+		c = FeedConfig
+	}
+
+	nodes := cleanFragment(c, fragment)
+	if base != nil {
+		for _, n := range nodes {
+			resolveURLs(n, base)
+		}
+	}
+
+	return strings.Replace(Render(nodes...), "]]>", "]]&gt;", -1)
+}
+
+// resolveURLs resolves the URLs in n and its descendants against base.
+func resolveURLs(n *html.Node, base *url.URL) {
+	for i, a := range n.Attr {
+		if a.Namespace != "" {
+			continue
+		}
+
+		switch k := atom.Lookup([]byte(a.Key)); {
+		case isURLAttr(k):
+			if u, err := url.Parse(a.Val); err == nil {
+				n.Attr[i].Val = base.ResolveReference(u).String()
+			}
+		case k == atom.Srcset:
+			n.Attr[i].Val = cleanSrcset(a.Val, func(u *url.URL) bool {
+				*u = *base.ResolveReference(u)
+				return true
+			})
+		case k == atom.Style:
+			n.Attr[i].Val = cleanStyleURLs(a.Val, func(u *url.URL) bool {
+				*u = *base.ResolveReference(u)
+				return true
+			})
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		resolveURLs(c, base)
+	}
+}
@@ -0,0 +1,27 @@
+package htmlcleaner
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestCleanForFeed(t *testing.T) {
+	base, _ := url.Parse("https://example.com/posts/1")
+
+	for _, tt := range []struct {
+		name, input, output string
+		config              *Config
+	}{
+		{"Relative", `<a href="../about">a</a><img src="/a.png" alt="">`, `<a href="https://example.com/about">a</a><img src="https://example.com/a.png" alt=""/>`, nil},
+		{"Absolute", `<a href="https://example.org/">a</a><a href="#top">b</a>`, `<a href="https://example.org/">a</a><a href="https://example.com/posts/1#top">b</a>`, nil},
+		{"Style", `<div style="background: url(bg.png)">a</div>`, `<div style="background: url(&#34;https://example.com/posts/bg.png&#34;)">a</div>`, FeedConfig.Clone().ElemAttr("div", "style")},
+		{"Tables", `<table><tr><td>a</td></tr></table>`, `a`, nil},
+		{"Forms", `<form action="/x"><label>a</label><input name="b"></form><iframe src="x"></iframe>`, `a&lt;input name=&#34;b&#34;/&gt;&lt;iframe src=&#34;x&#34;&gt;&lt;/iframe&gt;`, nil},
+		{"CDATA", `a]]>b<!--]]>-->`, `a]]&gt;b&lt;!--]]&gt;--&gt;`, nil},
+		{"CDATAComment", `<!--]]>-->`, `<!--]]&gt;-->`, (&Config{}).SetEscapeComments(false)},
+	} {
+		if actual := CleanForFeed(tt.config, tt.input, base); actual != tt.output {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.output, actual)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package htmlcleaner
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// forcedAttrs returns the attributes that are always removed from elements
+// of type e and the attributes that replace them, according to options
+// such as SandboxMedia and ImageLoading.
+func (c *Config) forcedAttrs(e atom.Atom) (remove []string, forced []html.Attribute) {
+	switch e {
+	case atom.Video, atom.Audio:
+		if !c.SandboxMedia {
+			return nil, nil
+		}
+		remove = []string{"autoplay", "loop"}
+		forced = []html.Attribute{
+			{Key: "controls"},
+			{Key: "preload", Val: "none"},
+		}
+		forced = appendForced(forced, "crossorigin", c.MediaCrossOrigin)
+	case atom.Img:
+		forced = appendForced(forced, "loading", c.ImageLoading)
+		forced = appendForced(forced, "decoding", c.ImageDecoding)
+		forced = appendForced(forced, "referrerpolicy", c.ReferrerPolicy)
+	case atom.Iframe:
+		forced = appendForced(forced, "loading", c.ImageLoading)
+		forced = appendForced(forced, "referrerpolicy", c.ReferrerPolicy)
+	}
+	return remove, forced
+}
+
+func appendForced(forced []html.Attribute, key, val string) []html.Attribute {
+	if val == "" {
+		return forced
+	}
+	return append(forced, html.Attribute{Key: key, Val: val})
+}
+
+// forceAttrs applies forcedAttrs to n and reports whether it changed n.
+func (c *Config) forceAttrs(n *html.Node) bool {
+	remove, forced := c.forcedAttrs(n.DataAtom)
+	if len(remove) == 0 && len(forced) == 0 {
+		return false
+	}
+
+	changed := false
+	attrs := make([]html.Attribute, 0, len(n.Attr)+len(forced))
+	for _, attr := range n.Attr {
+		if attr.Namespace == "" && (containsString(remove, attr.Key) || hasAttrKey(forced, attr.Key)) {
+			changed = changed || !containsAttr(forced, attr)
+			continue
+		}
+		attrs = append(attrs, attr)
+	}
+	for _, attr := range forced {
+		changed = changed || !containsAttr(n.Attr, attr)
+	}
+	n.Attr = append(attrs, forced...)
+
+	return changed
+}
+
+func hasAttrKey(attrs []html.Attribute, key string) bool {
+	for _, a := range attrs {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAttr(attrs []html.Attribute, attr html.Attribute) bool {
+	for _, a := range attrs {
+		if a.Namespace == "" && a.Key == attr.Key && a.Val == attr.Val {
+			return true
+		}
+	}
+	return false
+}
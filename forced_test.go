@@ -0,0 +1,45 @@
+package htmlcleaner
+
+import (
+	"testing"
+
+	"golang.org/x/net/html/atom"
+)
+
+var imageAttrsConfig = func() *Config {
+	c := DefaultConfig.Clone().ElemAttrAtom(atom.Iframe, atom.Src).ElemAttr("img", "loading")
+
+	c.ImageLoading = "lazy"
+	c.ImageDecoding = "async"
+	c.ReferrerPolicy = "no-referrer"
+
+	return c
+}()
+
+var referrerConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.ReferrerPolicy = "same-origin"
+
+	return c
+}()
+
+var testTableImageAttrs = []testTable{
+	{"Image", `<img src="/a.png" alt="a">`, `<img src="/a.png" alt="a" loading="lazy" decoding="async" referrerpolicy="no-referrer"/>`, imageAttrsConfig},
+	{"Replaced", `<img loading="eager" src="/a.png">`, `<img src="/a.png" loading="lazy" decoding="async" referrerpolicy="no-referrer"/>`, imageAttrsConfig},
+	{"Iframe", `<iframe src="https://example.com/"></iframe>`, `<iframe src="https://example.com/" loading="lazy" referrerpolicy="no-referrer"></iframe>`, imageAttrsConfig},
+	{"IframeNotAllowed", `<iframe src="https://example.com/"></iframe>`, `&lt;iframe src=&#34;https://example.com/&#34;&gt;&lt;/iframe&gt;`, referrerConfig},
+	{"OnlyReferrer", `<img src="/a.png" loading="eager">`, `<img src="/a.png" referrerpolicy="same-origin"/>`, referrerConfig},
+	{"NoSrc", `<img alt="a">`, ``, imageAttrsConfig},
+	{"Off", `<img src="/a.png" loading="eager">`, `<img src="/a.png"/>`, nil},
+}
+
+func TestImageAttrs(t *testing.T) {
+	doTableTest(Clean, t, testTableImageAttrs)
+
+	for _, tt := range testTableImageAttrs {
+		if err := tt.Config.CheckOutput(tt.Output); err != nil {
+			t.Errorf("%s: %v", tt.Name, err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package htmlcleaner
+
+// fuzzSeeds are inputs added to FuzzCorpus in addition to the SelfCheck
+// canaries, chosen to reach the parser's less common states.
+var fuzzSeeds = []string{
+	``,
+	`<p>a<p>b`,
+	`<table><tr><td>a</table>`,
+	`<ul><li>a<li>b</ul>`,
+	`<pre>  a&amp;b</pre>`,
+	`<math><mi>x</mi></math>`,
+	`<a href="/a?b=c&amp;d=e#f" title='x'>y</a>`,
+	`<img src="/a.png" srcset="/b.png 2x" alt="">`,
+	`<![CDATA[x]]>`,
+	`<template><b>x</b></template>`,
+	`</p></b></a>`,
+	`&#0;&#xd800;&#x110000;&notanentity;`,
+}
+
+// FuzzCorpus returns a set of inputs suitable as the seed corpus of a fuzz
+// test of a Config, such as one that checks the output of Clean with
+// CheckInvariants.
+func FuzzCorpus() []string {
+	corpus := make([]string, 0, len(selfCheckCanaries)+len(fuzzSeeds))
+	for _, canary := range selfCheckCanaries {
+		corpus = append(corpus, canary.input)
+	}
+	return append(corpus, fuzzSeeds...)
+}
+
+// CheckInvariants checks the output of Clean or Preprocess with c. It
+// returns a *VerifyError if the output contains an element, attribute, or
+// URL that c does not allow, as reported by CheckOutput, or a script element
+// or event handler attribute unless c.AllowUnsafe is set. It is meant to be
+// called by fuzz tests of a Config.
+func CheckInvariants(output string, c *Config) error {
+	if c == nil {
+		c = FallbackConfig()
+	}
+
+	var problems []string
+	if err := c.CheckOutput(output); err != nil {
+		problems = append(problems, err.(*VerifyError).Problems...)
+	}
+	if !c.AllowUnsafe {
+		for _, n := range ParseDepth(output, 0) {
+			if problem := unsafeContent(n); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+	}
+
+	if len(problems) != 0 {
+		return &VerifyError{Output: output, Problems: problems}
+	}
+	return nil
+}
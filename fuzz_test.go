@@ -0,0 +1,51 @@
+//go:build go1.18
+// +build go1.18
+
+package htmlcleaner
+
+import "testing"
+
+func FuzzClean(f *testing.F) {
+	for _, input := range FuzzCorpus() {
+		f.Add(input)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		for _, c := range []*Config{DefaultConfig, DefaultConfig.Clone().SetWrapText(true), {}} {
+			if err := CheckInvariants(Clean(c, input), c); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+}
+
+func FuzzPreprocess(f *testing.F) {
+	for _, input := range FuzzCorpus() {
+		f.Add(input)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		c := DefaultConfig
+		if err := CheckInvariants(Clean(c, Preprocess(c, input)), c); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestCheckInvariants(t *testing.T) {
+	if err := CheckInvariants(`<b>x</b>`, nil); err != nil {
+		t.Error(err)
+	}
+
+	c := (&Config{}).Elem("a", "script").ElemAttr("a", "onclick")
+	c.AllowUnsafe = true
+	output := `<script>x</script><a onclick="y">z</a><i>w</i>`
+	if err := CheckInvariants(output, c); err == nil || len(err.(*VerifyError).Problems) != 1 {
+		t.Errorf("expected one problem, got %v", err)
+	}
+
+	c.AllowUnsafe = false
+	if err := CheckInvariants(output, c); err == nil || len(err.(*VerifyError).Problems) < 2 {
+		t.Errorf("expected unsafe content to be reported, got %v", err)
+	}
+}
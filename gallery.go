@@ -0,0 +1,173 @@
+package htmlcleaner
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Gallery groups runs of consecutive images, such as in a post with several
+// photos, so that they can be styled together. See Config.Gallery.
+type Gallery struct {
+	// The element and class of the wrapper around each group. If Elem is
+	// empty, "div" is used.
+	Elem  string
+	Class string
+
+	// The smallest number of images to group. Runs of fewer images are
+	// left alone. If MinImages is less than 2, 2 is used.
+	MinImages int
+}
+
+// The cleaned output of a group of images looks like this, where the
+// figcaption holds the image's title, or its alt text if it has no title:
+//
+//	<div class="gallery">
+//	<figure><img src="a.png" alt="A"/><figcaption>A</figcaption></figure>
+//	<figure><a href="b.html"><img src="b.png"/></a></figure>
+//	</div>
+//
+// Images are consecutive if they are separated only by whitespace and <br>
+// elements, which are removed. Linked images count as images. Images inside
+// elements that may only contain inline content, such as <p>, are not
+// grouped.
+
+func (g *Gallery) elem() string {
+	if g.Elem == "" {
+		return "div"
+	}
+	return g.Elem
+}
+
+// galleryImage returns the <img> element shown by n, if n is an image or a
+// link around an image.
+func galleryImage(n *html.Node) *html.Node {
+	if n.Type != html.ElementNode {
+		return nil
+	}
+	if n.DataAtom == atom.Img {
+		return n
+	}
+	if n.DataAtom != atom.A {
+		return nil
+	}
+
+	var img *html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.TextNode && strings.TrimSpace(c.Data) == "":
+		case img == nil && c.Type == html.ElementNode && c.DataAtom == atom.Img:
+			img = c
+		default:
+			return nil
+		}
+	}
+	return img
+}
+
+// gallerySeparator reports whether n may appear between consecutive images.
+func gallerySeparator(n *html.Node) bool {
+	return (n.Type == html.TextNode && strings.TrimSpace(n.Data) == "") ||
+		(n.Type == html.ElementNode && n.DataAtom == atom.Br)
+}
+
+// groupImages applies Gallery to nodes and the elements inside them.
+func groupImages(c *Config, nodes []*html.Node) []*html.Node {
+	parent := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range nodes {
+		parent.AppendChild(n)
+	}
+
+	groupChildren(c.Gallery, parent)
+
+	nodes = nodes[:0]
+	for parent.FirstChild != nil {
+		n := parent.FirstChild
+		parent.RemoveChild(n)
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+func groupChildren(g *Gallery, parent *html.Node) {
+	if phrasingOnly[parent.DataAtom] || !isBlockElement[parent.DataAtom] {
+		return
+	}
+
+	for n := parent.FirstChild; n != nil; n = n.NextSibling {
+		if galleryImage(n) == nil {
+			groupChildren(g, n)
+			continue
+		}
+
+		// Find the run of images starting at n.
+		images := []*html.Node{n}
+		last := n
+		for next := n.NextSibling; next != nil; next = next.NextSibling {
+			if galleryImage(next) != nil {
+				images = append(images, next)
+				last = next
+			} else if !gallerySeparator(next) {
+				break
+			}
+		}
+
+		if len(images) < g.MinImages || len(images) < 2 {
+			n = last
+			continue
+		}
+
+		elem := g.elem()
+		wrapper := &html.Node{Type: html.ElementNode, Data: elem, DataAtom: atom.Lookup([]byte(elem))}
+		if g.Class != "" {
+			wrapper.Attr = []html.Attribute{{Key: "class", Val: g.Class}}
+		}
+		parent.InsertBefore(wrapper, n)
+
+		for next, stop := n, last.NextSibling; next != stop; {
+			after := next.NextSibling
+			parent.RemoveChild(next)
+			if galleryImage(next) != nil {
+				wrapper.AppendChild(galleryFigure(next))
+			}
+			next = after
+		}
+
+		n = wrapper
+	}
+}
+
+// galleryFigure wraps an image in a <figure> with a caption.
+func galleryFigure(n *html.Node) *html.Node {
+	figure := &html.Node{Type: html.ElementNode, Data: "figure", DataAtom: atom.Figure}
+	figure.AppendChild(n)
+
+	img := galleryImage(n)
+	caption, ok := getAttr(img, "title")
+	if !ok || caption == "" {
+		caption, _ = getAttr(img, "alt")
+	}
+	if caption != "" {
+		figcaption := &html.Node{Type: html.ElementNode, Data: "figcaption", DataAtom: atom.Figcaption}
+		figcaption.AppendChild(text(caption))
+		figure.AppendChild(figcaption)
+	}
+
+	return figure
+}
+
+// addsGalleryElem reports whether Gallery may add an element that is not
+// explicitly allowed.
+func (c *Config) addsGalleryElem(name string) bool {
+	if c.Gallery == nil {
+		return false
+	}
+	return name == "figure" || name == "figcaption" || name == c.Gallery.elem()
+}
+
+// addsGalleryAttr reports whether Gallery may add an attribute that is not
+// explicitly allowed.
+func (c *Config) addsGalleryAttr(elem, key string) bool {
+	return key == "class" && c.Gallery != nil && c.Gallery.Class != "" && elem == c.Gallery.elem()
+}
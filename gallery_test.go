@@ -0,0 +1,72 @@
+package htmlcleaner
+
+import "testing"
+
+var galleryConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.Gallery = &Gallery{MinImages: 2}
+
+	return c
+}()
+
+var galleryClassConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.Gallery = &Gallery{Class: "gallery", MinImages: 2}
+
+	return c
+}()
+
+var gallerySectionConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.Gallery = &Gallery{Elem: "section", MinImages: 2}
+
+	return c
+}()
+
+var galleryMinConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.Gallery = &Gallery{MinImages: 3}
+
+	return c
+}()
+
+var galleryDefaultMinConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.Gallery = &Gallery{}
+
+	return c
+}()
+
+var testTableGallery = []testTable{
+	{"Single", `<img src="/a.png" alt="A">`, `<img src="/a.png" alt="A"/>`, galleryClassConfig},
+	{"Pair", `<img src="/a.png" alt="A"> <img src="/b.png" title="B" alt="b">`, `<div class="gallery"><figure><img src="/a.png" alt="A"/><figcaption>A</figcaption></figure><figure><img src="/b.png" title="B" alt="b"/><figcaption>B</figcaption></figure></div>`, galleryClassConfig},
+	{"NoCaption", `<img src="/a.png"><br><img src="/b.png">`, `<div><figure><img src="/a.png"/></figure><figure><img src="/b.png"/></figure></div>`, galleryConfig.Clone().Elem("br")},
+	{"Link", `<a href="/a.html"><img src="/a.png"></a><img src="/b.png">`, `<section><figure><a href="/a.html"><img src="/a.png"/></a></figure><figure><img src="/b.png"/></figure></section>`, gallerySectionConfig},
+	{"LinkText", `<a href="/a.html"><img src="/a.png">A</a><img src="/b.png">`, `<a href="/a.html"><img src="/a.png"/>A</a><img src="/b.png"/>`, galleryConfig},
+	{"TooFew", `<img src="/a.png"><img src="/b.png">`, `<img src="/a.png"/><img src="/b.png"/>`, galleryMinConfig},
+	{"DefaultMin", `<img src="/a.png"><img src="/b.png">`, `<div><figure><img src="/a.png"/></figure><figure><img src="/b.png"/></figure></div>`, galleryDefaultMinConfig},
+	{"Separated", `<img src="/a.png">text<img src="/b.png"><img src="/c.png">`, `<img src="/a.png"/>text<div><figure><img src="/b.png"/></figure><figure><img src="/c.png"/></figure></div>`, galleryConfig},
+	{"Nested", `<blockquote><img src="/a.png"><img src="/b.png"></blockquote>`, `<blockquote><div><figure><img src="/a.png"/></figure><figure><img src="/b.png"/></figure></div></blockquote>`, galleryConfig},
+	{"Inline", `<b><img src="/a.png"><img src="/b.png"></b>`, `<b><img src="/a.png"/><img src="/b.png"/></b>`, galleryConfig},
+}
+
+func TestGallery(t *testing.T) {
+	doTableTest(Clean, t, testTableGallery)
+
+	// The added elements are allowed even if the Config does not allow
+	// them.
+	c := (&Config{ValidateURL: SafeURLScheme}).Elem("img").ElemAttr("img", "src")
+	c.Gallery = &Gallery{Class: "gallery", MinImages: 2}
+	output := Clean(c, `<img src="/a.png"><img src="/b.png">`)
+	if expected := `<div class="gallery"><figure><img src="/a.png"/></figure><figure><img src="/b.png"/></figure></div>`; output != expected {
+		t.Errorf("expected %q, actual %q", expected, output)
+	}
+	if err := c.CheckOutput(output); err != nil {
+		t.Error(err)
+	}
+}
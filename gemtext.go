@@ -0,0 +1,137 @@
+package htmlcleaner
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// RenderGemtext cleans a fragment of HTML using the specified Config, or the
+// FallbackConfig if it is nil, and converts it to gemtext, the markup language
+// of the Gemini protocol. Gemtext has no inline links, so links and images are
+// listed on their own lines after the block of text containing them. Lines of
+// text that start like gemtext markup are prefixed with a space.
+func RenderGemtext(c *Config, fragment string) string {
+	r := &gemtextRenderer{}
+	w := newTextWriter(r.element)
+	w.escape = r.escape
+	w.nodes(cleanFragment(c, fragment))
+	r.flushLinks(w)
+
+	return w.String()
+}
+
+type gemtextRenderer struct {
+	links []string
+	quote int
+}
+
+func (r *gemtextRenderer) flushLinks(w *textWriter) {
+	if r.quote != 0 || len(r.links) == 0 {
+		return
+	}
+
+	w.block(1)
+	for _, link := range r.links {
+		w.write(link)
+		w.lineBreak()
+	}
+	w.block(1)
+
+	r.links = r.links[:0]
+}
+
+// escape adds a space before text at the start of a line that gemtext would
+// otherwise read as a link, heading, list item, quotation, or preformatting
+// toggle. The markup may be split across text nodes, so only the first
+// character is checked.
+func (r *gemtextRenderer) escape(w *textWriter, s string) string {
+	if len(w.prefix) != 0 || s == "" {
+		return s
+	}
+	if w.pre != 0 {
+		if s[0] == '`' {
+			return " " + s
+		}
+		return s
+	}
+	if strings.IndexByte("=#*>`", s[0]) != -1 {
+		return " " + s
+	}
+	return s
+}
+
+func (r *gemtextRenderer) link(url, text string) {
+	text = strings.Join(strings.FieldsFunc(text, isHTMLSpace), " ")
+	link := "=> " + url
+	if text != "" && text != url {
+		link += " " + text
+	}
+	r.links = append(r.links, link)
+}
+
+func (r *gemtextRenderer) element(w *textWriter, n *html.Node) {
+	if n.DataAtom == 0 || !isBlockElement[n.DataAtom] {
+		r.inline(w, n)
+		return
+	}
+
+	r.flushLinks(w)
+	w.block(1)
+
+	switch n.DataAtom {
+	case atom.H1:
+		w.write("# ")
+		w.children(n)
+	case atom.H2:
+		w.write("## ")
+		w.children(n)
+	case atom.H3, atom.H4, atom.H5, atom.H6:
+		w.write("### ")
+		w.children(n)
+	case atom.Li:
+		w.write("* ")
+		w.children(n)
+	case atom.Blockquote:
+		r.quote++
+		w.indent("> ", func() { w.children(n) })
+		r.quote--
+	case atom.Pre, atom.Listing, atom.Plaintext:
+		w.write("```")
+		w.lineBreak()
+		w.preformatted(func() { w.children(n) })
+		w.lineBreak()
+		w.write("```")
+	default:
+		w.children(n)
+	}
+
+	w.block(1)
+	r.flushLinks(w)
+}
+
+func (r *gemtextRenderer) inline(w *textWriter, n *html.Node) {
+	switch n.DataAtom {
+	case atom.A:
+		w.children(n)
+		if href, _ := getAttr(n, "href"); href != "" {
+			r.link(href, textContent(n))
+		}
+	case atom.Img:
+		alt, _ := getAttr(n, "alt")
+		if alt != "" {
+			w.text(alt)
+		}
+		if src, _ := getAttr(n, "src"); src != "" {
+			if alt == "" {
+				alt = "image"
+			}
+			r.link(src, alt)
+		}
+	case atom.Br:
+		w.lineBreak()
+	default:
+		w.children(n)
+	}
+}
@@ -0,0 +1,35 @@
+package htmlcleaner
+
+import (
+	"testing"
+
+	"golang.org/x/net/html/atom"
+)
+
+var gemtextConfig = DefaultConfig.Clone().ElemAtom(atom.Ul, atom.Li, atom.H1, atom.H2, atom.H4)
+
+var testTableRenderGemtext = []testTable{
+	{"Empty", ``, ``, gemtextConfig},
+	{"Text", `hello <b>world</b>`, `hello world`, gemtextConfig},
+	{"Headings", `<h1>a</h1><h2>b</h2><h4>c</h4>`, "# a\n## b\n### c", gemtextConfig},
+	{"Links", `<p>see <a href="https://a.example/">a</a> and <a href="https://b.example/">https://b.example/</a></p><p>c</p>`, "see a and https://b.example/\n=> https://a.example/ a\n=> https://b.example/\nc", gemtextConfig},
+	{"Image", `<img src="x.png" alt="cat">`, "cat\n=> x.png cat", gemtextConfig},
+	{"List", `<ul><li>a <a href="/x">x</a></li><li>b</li></ul>`, "* a x\n=> /x x\n* b", gemtextConfig},
+	{"Quote", `<blockquote><p>a <a href="/x">x</a></p><p>b</p></blockquote>`, "> a x\n> b\n=> /x x", gemtextConfig},
+	{"Pre", "<pre>a\n  *b*</pre>", "```\na\n  *b*\n```", gemtextConfig},
+	{"TextLink", `<p>=&gt; gemini://evil.example/</p>`, " => gemini://evil.example/", gemtextConfig},
+	{"TextHeading", `<p>#heading</p>`, " #heading", gemtextConfig},
+	{"TextList", `<p>* item</p>`, " * item", gemtextConfig},
+	{"TextQuote", `<p>&gt; quote</p>`, " > quote", gemtextConfig},
+	{"TextToggle", "<p>```</p>", " ```", gemtextConfig},
+	{"TextAfterBlock", `<p>a</p># b`, "a\n # b", gemtextConfig},
+	{"TextSplit", `<b>=</b>&gt; x`, " => x", gemtextConfig},
+	{"TextInline", `a # b`, "a # b", gemtextConfig},
+	{"PreToggle", "<pre>a\n```\n# b</pre>", "```\na\n ```\n# b\n```", gemtextConfig},
+	{"QuoteMarkup", `<blockquote>=&gt; x</blockquote>`, "> => x", gemtextConfig},
+	{"AltNewline", "<img src=\"x.png\" alt=\"cat\n=> gemini://evil.example/\">", "cat => gemini://evil.example/\n=> x.png cat => gemini://evil.example/", gemtextConfig},
+}
+
+func TestRenderGemtext(t *testing.T) {
+	doTableTest(RenderGemtext, t, testTableRenderGemtext)
+}
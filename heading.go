@@ -0,0 +1,37 @@
+package htmlcleaner
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var headings = [...]atom.Atom{atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6}
+
+// offsetHeading changes the level of n by c.HeadingOffset if it is a heading,
+// clamping the result between <h1> and <h6>, and reports whether n changed.
+func (c *Config) offsetHeading(n *html.Node) bool {
+	if c.HeadingOffset == 0 {
+		return false
+	}
+
+	for level, h := range headings {
+		if n.DataAtom != h {
+			continue
+		}
+
+		level += c.HeadingOffset
+		if level < 0 {
+			level = 0
+		}
+		if level >= len(headings) {
+			level = len(headings) - 1
+		}
+
+		changed := n.DataAtom != headings[level]
+		n.DataAtom = headings[level]
+		n.Data = n.DataAtom.String()
+		return changed
+	}
+
+	return false
+}
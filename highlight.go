@@ -0,0 +1,78 @@
+package htmlcleaner
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// highlight calls HighlightCode for a <pre><code> block. It returns the <code>
+// element and the highlighted nodes, or nil if n should be cleaned normally.
+func (c *cleaner) highlight(n *html.Node) (*html.Node, []*html.Node) {
+	if c.HighlightCode == nil || n.DataAtom != atom.Pre {
+		return nil, nil
+	}
+
+	var code *html.Node
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		switch {
+		case child.Type == html.ElementNode && child.DataAtom == atom.Code && code == nil:
+			code = child
+		case child.Type == html.TextNode && strings.TrimFunc(child.Data, isHTMLSpace) == "":
+		default:
+			return nil, nil
+		}
+	}
+	if code == nil {
+		return nil, nil
+	}
+
+	lang := codeLanguage(code)
+	if lang == "" {
+		lang = codeLanguage(n)
+	}
+
+	highlighted := c.HighlightCode(lang, preformattedText(code))
+	if highlighted == nil {
+		return nil, nil
+	}
+
+	for code.FirstChild != nil {
+		code.RemoveChild(code.FirstChild)
+	}
+	if !c.AllowsElem("code") {
+		// insert the highlighted nodes directly into the <pre> element
+		n.RemoveChild(code)
+	}
+
+	return code, highlighted
+}
+
+// codeLanguage returns xyz from the first class of n that looks like
+// language-xyz.
+func codeLanguage(n *html.Node) string {
+	class, _ := getAttr(n, "class")
+	for _, name := range strings.Fields(class) {
+		if strings.HasPrefix(name, "language-") {
+			return name[len("language-"):]
+		}
+	}
+	return ""
+}
+
+// insertHighlighted adds the output of HighlightCode to the cleaned <pre>
+// element, inside the <code> element if it was kept.
+func insertHighlighted(pre, code *html.Node, highlighted []*html.Node) {
+	parent := pre
+	if code.Parent == pre {
+		parent = code
+	}
+
+	for _, h := range highlighted {
+		if h.Parent != nil {
+			h.Parent.RemoveChild(h)
+		}
+		parent.AppendChild(h)
+	}
+}
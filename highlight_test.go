@@ -0,0 +1,51 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var highlightConfig = func() *Config {
+	c := DefaultConfig.Clone().CodeLanguages("go")
+
+	c.HighlightCode = func(lang, code string) []*html.Node {
+		if lang != "go" {
+			return nil
+		}
+
+		var nodes []*html.Node
+		for i, word := range strings.SplitAfter(code, " ") {
+			if i%2 == 0 {
+				nodes = append(nodes, text(word))
+				continue
+			}
+			span := &html.Node{
+				Type:     html.ElementNode,
+				Data:     "span",
+				DataAtom: atom.Span,
+				Attr:     []html.Attribute{{Key: "class", Val: "kw"}},
+			}
+			span.AppendChild(text(word))
+			nodes = append(nodes, span)
+		}
+		return nodes
+	}
+
+	return c
+}()
+
+var testTableHighlight = []testTable{
+	{"Highlighted", `<pre><code class="language-go">func f() {}</code></pre>`, `<pre><code class="language-go">func <span class="kw">f() </span>{}</code></pre>`, highlightConfig},
+	{"PreClass", "<pre class=\"language-go\">\n<code>a b</code>\n</pre>", "<pre class=\"language-go\"><code>a <span class=\"kw\">b</span></code>\n</pre>", highlightConfig},
+	{"Markup", `<pre><code class="language-go">a<b>&lt;</b><script>x</script></code></pre>`, `<pre><code class="language-go">a&lt;x</code></pre>`, highlightConfig},
+	{"OtherLanguage", `<pre><code class="language-rust">a <b>b</b></code></pre>`, `<pre><code>a <b>b</b></code></pre>`, highlightConfig},
+	{"NotOnlyChild", `<pre>a<code class="language-go">a b</code></pre>`, `<pre>a<code class="language-go">a b</code></pre>`, highlightConfig},
+	{"CodeNotAllowed", `<pre><code class="language-go">a b</code></pre>`, `<pre>a <span class="kw">b</span></pre>`, (&Config{HighlightCode: highlightConfig.HighlightCode}).Elem("pre")},
+}
+
+func TestHighlightCode(t *testing.T) {
+	doTableTest(Clean, t, testTableHighlight)
+}
@@ -0,0 +1,107 @@
+package htmlcleaner
+
+import (
+	"net/url"
+	"strings"
+)
+
+// AllowHosts restricts the URLs of elements named elem, such as the href of
+// <a> or the src of <img>, to the specified hosts. A host starting with "*."
+// also matches any subdomain, so "*.example.com" matches "cdn.example.com"
+// but not "example.com". URLs without a host, such as relative URLs, are not
+// restricted, but http and https URLs with no host that browsers would still
+// load from somewhere, such as "http:\\example.org", are rejected. Calling
+// AllowHosts more than once for the same element adds to the list. The
+// receiver is returned to allow call chaining.
+func (c *Config) AllowHosts(elem string, hosts ...string) *Config {
+	c.allowHosts = addHosts(c.allowHosts, elem, hosts)
+	return c
+}
+
+// DenyHosts rejects URLs of elements named elem whose host matches one of the
+// specified hosts, using the same patterns as AllowHosts. DenyHosts takes
+// precedence over AllowHosts. The receiver is returned to allow call
+// chaining.
+func (c *Config) DenyHosts(elem string, hosts ...string) *Config {
+	c.denyHosts = addHosts(c.denyHosts, elem, hosts)
+	return c
+}
+
+func addHosts(m map[string]map[string]struct{}, elem string, hosts []string) map[string]map[string]struct{} {
+	if m == nil {
+		m = make(map[string]map[string]struct{})
+	}
+
+	set := m[elem]
+	if set == nil {
+		set = make(map[string]struct{})
+		m[elem] = set
+	}
+
+	for _, h := range hosts {
+		set[strings.TrimSuffix(strings.ToLower(h), ".")] = struct{}{}
+	}
+
+	return m
+}
+
+// urlHost returns the lowercase host of u, without the port or a trailing
+// dot, so that "evil.com." matches "evil.com".
+func urlHost(u *url.URL) string {
+	host := strings.ToLower(u.Host)
+	if i := strings.LastIndex(host, ":"); i > strings.LastIndex(host, "]") {
+		host = host[:i]
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	return strings.TrimSuffix(host, ".")
+}
+
+// hiddenHost reports whether u is an http or https URL whose host cannot be
+// checked, such as "http:\\evil.com", which Go parses with an empty host
+// but browsers load from evil.com.
+func hiddenHost(u *url.URL) bool {
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return false
+	}
+	return u.Host == "" || u.Opaque != "" || strings.Contains(u.Host, "\\")
+}
+
+func matchHost(hosts map[string]struct{}, host string) bool {
+	if _, ok := hosts[host]; ok {
+		return true
+	}
+	for i := strings.IndexByte(host, '.'); i != -1; i = strings.IndexByte(host, '.') {
+		host = host[i+1:]
+		if _, ok := hosts["*."+host]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedHost reports whether the host of u is allowed on elements named
+// elem.
+func (c *Config) allowedHost(elem string, u *url.URL) bool {
+	deny, denied := c.denyHosts[elem]
+	allow, allowed := c.allowHosts[elem]
+	if !denied && !allowed {
+		return true
+	}
+
+	if hiddenHost(u) {
+		return false
+	}
+	if u.Host == "" {
+		return true
+	}
+
+	host := urlHost(u)
+	if denied && matchHost(deny, host) {
+		return false
+	}
+	if allowed && !matchHost(allow, host) {
+		return false
+	}
+	return true
+}
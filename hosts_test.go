@@ -0,0 +1,32 @@
+package htmlcleaner
+
+import "testing"
+
+var hostsConfig = DefaultConfig.Clone().
+	AllowHosts("img", "example.com", "*.cdn.example.com").
+	DenyHosts("a", "*.evil.example")
+
+var testTableHosts = []testTable{
+	{"AllowedImage", `<img src="https://example.com/a.png">`, `<img src="https://example.com/a.png"/>`, hostsConfig},
+	{"AllowedSubdomain", `<img src="https://a.b.CDN.example.com:8080/a.png">`, `<img src="https://a.b.CDN.example.com:8080/a.png"/>`, hostsConfig},
+	{"WildcardOnlySubdomains", `<img src="https://cdn.example.com/a.png" alt="x">`, ``, hostsConfig},
+	{"OtherImageHost", `<img src="https://example.org/a.png" alt="x">`, ``, hostsConfig},
+	{"ProtocolRelative", `<img src="//example.org/a.png" alt="x">`, ``, hostsConfig},
+	{"RelativeImage", `<img src="/a.png">`, `<img src="/a.png"/>`, hostsConfig},
+	{"AnyLinkHost", `<a href="https://example.org/">x</a>`, `<a href="https://example.org/">x</a>`, hostsConfig},
+	{"DeniedLinkHost", `<a href="https://www.evil.example/">x</a>`, `<a>x</a>`, hostsConfig},
+	{"DeniedTrailingDot", `<a href="https://www.evil.example./">x</a>`, `<a>x</a>`, hostsConfig},
+	{"AllowedTrailingDot", `<img src="https://example.com./a.png">`, `<img src="https://example.com./a.png"/>`, hostsConfig},
+	{"BackslashLink", `<a href="http:\\www.evil.example/">x</a>`, `<a>x</a>`, hostsConfig},
+	{"BackslashImage", `<img src="http:\\example.org/a.png" alt="x">`, ``, hostsConfig},
+	{"OpaqueImage", `<img src="https:example.org/a.png" alt="x">`, ``, hostsConfig},
+	{"DeniedLinkApex", `<a href="https://evil.example/">x</a>`, `<a href="https://evil.example/">x</a>`, hostsConfig},
+}
+
+func TestHosts(t *testing.T) {
+	doTableTest(Clean, t, testTableHosts)
+
+	if DefaultConfig.AllowsAttr("a", "href", "https://www.evil.example/") == hostsConfig.AllowsAttr("a", "href", "https://www.evil.example/") {
+		t.Error("host lists should not affect DefaultConfig")
+	}
+}
@@ -0,0 +1,135 @@
+// Package htmlcleanertest provides utilities for property-based testing of
+// htmlcleaner policies: generators for random and adversarial input, and a
+// checker for the output of htmlcleaner.Clean.
+package htmlcleanertest
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/BenLubar/htmlcleaner"
+)
+
+// textPieces are used to build random text, including characters that must
+// be escaped.
+var textPieces = []string{
+	"a", "hello", "world", " ", "  ", "\n", "\t", "&", "&amp;", "&lt;", "<", ">",
+	"\"", "'", "&#34;", "&nbsp;", "é", "日本", "\u202e", "=", "/", ":",
+}
+
+// attrValues are used to build random attribute values.
+var attrValues = []string{
+	"", "a", "x y", "https://example.com/", "http://example.com/?a=1&b=2",
+	"/relative", "#fragment", "mailto:a@example.com", "javascript:alert(1)",
+	"JaVaScRiPt:alert(1)", " javascript:alert(1)", "vbscript:x", "data:text/html,<script>x</script>",
+	"\"><script>x</script>", "https://%zz",
+}
+
+// mutations are adversarial snippets inserted by Mutate.
+var mutations = []string{
+	"<script>alert(1)</script>", "<img src=x onerror=alert(1)>", "<a href=\"javascript:alert(1)\">x</a>",
+	"<svg onload=alert(1)>", "<iframe src=\"https://example.com/\"></iframe>", "<style>*{}</style>",
+	"<!--", "-->", "<!-- x -->", "<![CDATA[x]]>", "<?xml x?>", "<!DOCTYPE html>", "</", "<", ">",
+	"<b", "\"", "'", "&", "&#x3c;script&#x3e;", "<p onclick=\"x()\">", "<textarea>", "<plaintext>",
+	"<math><mi xlink:href=\"javascript:x\">", "<noscript><p title=\"</noscript><img src=x onerror=alert(1)>\">",
+}
+
+func pick(r *rand.Rand, list []string) string {
+	return list[r.Intn(len(list))]
+}
+
+func sorted(list []string) []string {
+	sort.Strings(list)
+	return list
+}
+
+// RandomFragment returns a random fragment of HTML using only the elements
+// and attributes allowed by c. Attribute values are chosen at random, so
+// they may still be rejected by regular expressions or ValidateURL. size is
+// the approximate number of nodes.
+func RandomFragment(r *rand.Rand, c *htmlcleaner.Config, size int) string {
+	if c == nil {
+		c = htmlcleaner.FallbackConfig()
+	}
+
+	elems := sorted(c.AllowedElems())
+
+	var buf bytes.Buffer
+	var gen func(depth int)
+	gen = func(depth int) {
+		for size > 0 {
+			size--
+			if len(elems) == 0 || depth > 10 || r.Intn(3) == 0 {
+				buf.WriteString(html.EscapeString(pick(r, textPieces)))
+				if r.Intn(4) == 0 {
+					return
+				}
+				continue
+			}
+
+			elem := pick(r, elems)
+			buf.WriteString("<" + elem)
+			if attrs := sorted(c.AllowedAttrs(elem)); len(attrs) != 0 {
+				for i := r.Intn(3); i > 0; i-- {
+					fmt.Fprintf(&buf, " %s=\"%s\"", pick(r, attrs), html.EscapeString(pick(r, attrValues)))
+				}
+			}
+			buf.WriteString(">")
+			gen(depth + 1)
+			buf.WriteString("</" + elem + ">")
+		}
+	}
+	gen(0)
+
+	return buf.String()
+}
+
+// Mutate returns fragment with a few random adversarial changes, such as
+// inserted script tags, event handlers, javascript: URLs, unbalanced tags,
+// and truncation.
+func Mutate(r *rand.Rand, fragment string) string {
+	for i := r.Intn(4) + 1; i > 0; i-- {
+		pos := 0
+		if len(fragment) != 0 {
+			pos = r.Intn(len(fragment) + 1)
+		}
+
+		switch r.Intn(4) {
+		case 0:
+			fragment = fragment[:pos]
+		case 1:
+			end := pos
+			if len(fragment) > pos {
+				end += r.Intn(len(fragment) - pos + 1)
+			}
+			fragment = fragment[:pos] + fragment[end:]
+		default:
+			fragment = fragment[:pos] + pick(r, mutations) + fragment[pos:]
+		}
+	}
+	return fragment
+}
+
+// Check reports an error if the output of htmlcleaner.Clean contains
+// anything that c does not allow. See Config.CheckOutput.
+func Check(c *htmlcleaner.Config, output string) error {
+	return c.CheckOutput(output)
+}
+
+// Run cleans iterations random, mutated fragments using c and fails the test
+// for each output that does not pass Check. The same seed produces the same
+// fragments.
+func Run(t testing.TB, c *htmlcleaner.Config, iterations int, seed int64) {
+	r := rand.New(rand.NewSource(seed))
+
+	for i := 0; i < iterations; i++ {
+		input := Mutate(r, RandomFragment(r, c, 20))
+		if err := Check(c, htmlcleaner.Clean(c, input)); err != nil {
+			t.Errorf("input %q: %v", input, err)
+		}
+	}
+}
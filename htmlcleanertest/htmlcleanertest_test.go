@@ -0,0 +1,53 @@
+package htmlcleanertest_test
+
+import (
+	"math/rand"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/BenLubar/htmlcleaner"
+	"github.com/BenLubar/htmlcleaner/htmlcleanertest"
+)
+
+func TestRun(t *testing.T) {
+	c := htmlcleaner.DefaultConfig.Clone().
+		Elem("x-card").
+		ElemAttrMatch("span", "class", regexp.MustCompile(`\Ahl-\w+\z`)).
+		SetValidateURL(func(u *url.URL) bool { return u.Scheme == "https" })
+
+	htmlcleanertest.Run(t, nil, 500, 1)
+	htmlcleanertest.Run(t, c, 500, 2)
+	htmlcleanertest.Run(t, (&htmlcleaner.Config{}).SetEscapeComments(true), 500, 3)
+}
+
+func TestRandomFragment(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	c := (&htmlcleaner.Config{}).Elem("b", "i")
+
+	for i := 0; i < 100; i++ {
+		input := htmlcleanertest.RandomFragment(r, c, 20)
+		if err := htmlcleanertest.Check(c, input); err != nil {
+			t.Errorf("generated fragment is not allowed: %v", err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	for _, tt := range []struct {
+		output string
+		ok     bool
+	}{
+		{`<b title="x">a</b>`, true},
+		{`<a href="https://example.com/">a</a>`, true},
+		{`&lt;script&gt;`, true},
+		{`<!-- x -->`, true},
+		{`<script>x</script>`, false},
+		{`<b onclick="x()">a</b>`, false},
+		{`<a href="javascript:x()">a</a>`, false},
+	} {
+		if err := htmlcleanertest.Check(nil, tt.output); (err == nil) != tt.ok {
+			t.Errorf("%q: unexpected result %v", tt.output, err)
+		}
+	}
+}
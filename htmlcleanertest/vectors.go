@@ -0,0 +1,102 @@
+package htmlcleanertest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/BenLubar/htmlcleaner"
+)
+
+// Vectors is a corpus of inputs and expected outputs, used to check that
+// other implementations of htmlcleaner produce byte-for-byte identical
+// results. The corpus used by htmlcleaner's own tests is in
+// testdata/vectors.json in the htmlcleaner repository.
+type Vectors struct {
+	// The policies used by the vectors, by name.
+	Policies map[string]*Policy `json:"policies"`
+
+	Vectors []Vector `json:"vectors"`
+}
+
+// Vector is a single input and expected output.
+type Vector struct {
+	Name string `json:"name"`
+
+	// The function being tested: "Clean" or "Preprocess".
+	Func string `json:"func"`
+
+	// The name of the Policy.
+	Policy string `json:"policy"`
+
+	Input  string `json:"input"`
+	Output string `json:"output"`
+}
+
+// Policy describes a Config as data, so that other implementations can
+// construct the same policy.
+type Policy struct {
+	// The allowed elements, and the attributes allowed on each.
+	Elements map[string][]string `json:"elements,omitempty"`
+
+	// The attributes allowed on every allowed element.
+	GlobalAttributes []string `json:"globalAttributes,omitempty"`
+
+	// If true, URLs must pass htmlcleaner.SafeURLScheme.
+	SafeURLSchemes bool `json:"safeURLSchemes,omitempty"`
+
+	MaxURLLength   int  `json:"maxURLLength,omitempty"`
+	MaxAttrLength  int  `json:"maxAttrLength,omitempty"`
+	WrapText       bool `json:"wrapText,omitempty"`
+	EscapeComments bool `json:"escapeComments,omitempty"`
+}
+
+// Config returns a new Config that implements the policy.
+func (p *Policy) Config() *htmlcleaner.Config {
+	c := &htmlcleaner.Config{
+		MaxURLLength:   p.MaxURLLength,
+		MaxAttrLength:  p.MaxAttrLength,
+		WrapText:       p.WrapText,
+		EscapeComments: p.EscapeComments,
+	}
+	if p.SafeURLSchemes {
+		c.ValidateURL = htmlcleaner.SafeURLScheme
+	}
+	for elem, attrs := range p.Elements {
+		c.Elem(elem)
+		if len(attrs) != 0 {
+			c.ElemAttr(elem, attrs...)
+		}
+	}
+	if len(p.GlobalAttributes) != 0 {
+		c.GlobalAttr(p.GlobalAttributes...)
+	}
+	return c
+}
+
+// ReadVectors reads a JSON corpus of test vectors.
+func ReadVectors(r io.Reader) (*Vectors, error) {
+	var vectors Vectors
+	if err := json.NewDecoder(r).Decode(&vectors); err != nil {
+		return nil, err
+	}
+	return &vectors, nil
+}
+
+// Result returns the output of htmlcleaner for the input of v.
+func (vs *Vectors) Result(v Vector) (string, error) {
+	p, ok := vs.Policies[v.Policy]
+	if !ok {
+		return "", errors.New("htmlcleanertest: unknown test vector policy " + v.Policy)
+	}
+	c := p.Config()
+
+	switch v.Func {
+	case "Clean":
+		return htmlcleaner.Clean(c, v.Input), nil
+	case "Preprocess":
+		return htmlcleaner.Preprocess(c, v.Input), nil
+	default:
+		return "", errors.New("htmlcleanertest: unknown test vector func " + v.Func)
+	}
+}
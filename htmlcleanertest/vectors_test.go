@@ -0,0 +1,74 @@
+package htmlcleanertest_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/BenLubar/htmlcleaner"
+	"github.com/BenLubar/htmlcleaner/htmlcleanertest"
+)
+
+func readVectors(t *testing.T) *htmlcleanertest.Vectors {
+	f, err := os.Open("../testdata/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	vectors, err := htmlcleanertest.ReadVectors(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vectors
+}
+
+func TestVectors(t *testing.T) {
+	vectors := readVectors(t)
+
+	for _, v := range vectors.Vectors {
+		v := v
+		t.Run(v.Name, func(t *testing.T) {
+			actual, err := vectors.Result(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != v.Output {
+				t.Logf("expected %q", v.Output)
+				t.Logf("actual   %q", actual)
+				t.Fatal("expected != actual")
+			}
+		})
+	}
+}
+
+func TestVectorsDefaultPolicy(t *testing.T) {
+	vectors := readVectors(t)
+
+	// the data must describe DefaultConfig
+	for _, v := range vectors.Vectors {
+		if v.Policy != "default" {
+			continue
+		}
+
+		var actual string
+		if v.Func == "Clean" {
+			actual = htmlcleaner.Clean(htmlcleaner.DefaultConfig, v.Input)
+		} else {
+			actual = htmlcleaner.Preprocess(htmlcleaner.DefaultConfig, v.Input)
+		}
+		if actual != v.Output {
+			t.Errorf("%s: expected %q, actual %q", v.Name, v.Output, actual)
+		}
+	}
+}
+
+func TestVectorErrors(t *testing.T) {
+	vectors := readVectors(t)
+
+	if _, err := vectors.Result(htmlcleanertest.Vector{Func: "Clean", Policy: "missing"}); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+	if _, err := vectors.Result(htmlcleanertest.Vector{Func: "Render", Policy: "default"}); err == nil {
+		t.Error("expected error for unknown func")
+	}
+}
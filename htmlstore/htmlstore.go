@@ -0,0 +1,134 @@
+// Package htmlstore stores cleaned HTML fragments in a compact form. Each
+// fragment is put in canonical form with htmlcleaner.RenderCanonical and
+// compressed with DEFLATE against a dictionary of common markup, which
+// makes even very short fragments smaller.
+package htmlstore
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"github.com/BenLubar/htmlcleaner"
+)
+
+// The first byte of a Fragment is its format. Fragments that do not get
+// smaller with compression are stored as is.
+const (
+	formatRaw    byte = 0
+	formatFlate1 byte = 1
+)
+
+// dictionary1 holds markup that is common in cleaned fragments. DEFLATE
+// prefers matches near the end of the dictionary, so the most common
+// sequences are last. It must never change; a new dictionary needs a new
+// format.
+const dictionary1 = `<table><thead><tr><th></th></tr></thead><tbody><tr><td></td></tr></tbody></table>` +
+	`<h1></h1><h2></h2><h3></h3><h4></h4><blockquote></blockquote><pre><code></code></pre>` +
+	`<img alt="" src="https://"/><img src="/><br/><hr/><del></del><ins></ins><sub></sub><sup></sup>` +
+	`<span></span><div></div><code></code><u></u><s></s><em></em><strong></strong>` +
+	`<ol><li></li></ol><ul><li></li></ul><i></i><b></b>` +
+	`&#34;&#39;&amp;&lt;&gt; title="<a href="http://<a href="https://www.</a>. </p><p>`
+
+// ErrCorrupt is returned by Decode for data that is not a valid Fragment.
+var ErrCorrupt = errors.New("htmlstore: corrupt fragment")
+
+// Fragment is a compressed HTML fragment, as returned by Compress.
+type Fragment []byte
+
+// Compress returns the compact form of a cleaned HTML fragment. Because
+// the fragment is put in canonical form, Decode may return different but
+// equivalent HTML.
+func Compress(cleaned string) Fragment {
+	canonical := htmlcleaner.RenderCanonical(htmlcleaner.Parse(cleaned)...)
+
+	var buf bytes.Buffer
+	buf.WriteByte(formatFlate1)
+	w, err := flate.NewWriterDict(&buf, flate.BestCompression, []byte(dictionary1))
+	if err == nil {
+		_, err = io.WriteString(w, canonical)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		panic("htmlstore: unexpected error: " + err.Error())
+	}
+
+	if buf.Len() > len(canonical) {
+		return Fragment(append([]byte{formatRaw}, canonical...))
+	}
+	return Fragment(buf.Bytes())
+}
+
+// reader returns a reader for the decompressed HTML.
+func (f Fragment) reader() (io.Reader, error) {
+	if len(f) == 0 {
+		return nil, ErrCorrupt
+	}
+
+	switch f[0] {
+	case formatRaw:
+		return bytes.NewReader(f[1:]), nil
+	case formatFlate1:
+		return flate.NewReaderDict(bytes.NewReader(f[1:]), []byte(dictionary1)), nil
+	default:
+		return nil, ErrCorrupt
+	}
+}
+
+// Decode returns the HTML stored in f.
+func (f Fragment) Decode() (string, error) {
+	r, err := f.reader()
+	if err != nil {
+		return "", err
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", ErrCorrupt
+	}
+	return string(b), nil
+}
+
+// WriteTo writes the HTML stored in f to w without holding all of it in
+// memory, so a Fragment can be rendered directly into a response.
+func (f Fragment) WriteTo(w io.Writer) (int64, error) {
+	r, err := f.reader()
+	if err != nil {
+		return 0, err
+	}
+
+	ew := &errWriter{w: w}
+	n, err := io.Copy(ew, r)
+	if err != nil && err != ew.err {
+		err = ErrCorrupt
+	}
+	return n, err
+}
+
+// errWriter remembers the error returned by its writer, so that WriteTo
+// can tell it apart from errors in the Fragment.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	n, err := ew.w.Write(p)
+	if err != nil {
+		ew.err = err
+	}
+	return n, err
+}
+
+// String returns the HTML stored in f, or an empty string if f is corrupt.
+func (f Fragment) String() string {
+	s, err := f.Decode()
+	if err != nil {
+		return ""
+	}
+	return s
+}
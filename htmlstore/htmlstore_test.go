@@ -0,0 +1,69 @@
+package htmlstore
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		input, expected string
+	}{
+		{``, ``},
+		{`a`, `a`},
+		{`<p>Hello, <b>world</b>!</p>`, `<p>Hello, <b>world</b>!</p>`},
+		{`<a title="t" href="https://example.com/">x</a>`, `<a href="https://example.com/" title="t">x</a>`},
+		{`<img src="/a.png" alt="a">`, `<img alt="a" src="/a.png"/>`},
+	} {
+		f := Compress(tt.input)
+		actual, err := f.Decode()
+		if err != nil {
+			t.Errorf("%q: %v", tt.input, err)
+		} else if actual != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.input, tt.expected, actual)
+		}
+
+		var buf bytes.Buffer
+		if n, err := f.WriteTo(&buf); err != nil || n != int64(len(tt.expected)) || buf.String() != tt.expected {
+			t.Errorf("%q: WriteTo wrote %d bytes %q: %v", tt.input, n, buf.String(), err)
+		}
+		if f.String() != tt.expected {
+			t.Errorf("%q: String returned %q", tt.input, f.String())
+		}
+	}
+}
+
+func TestSmaller(t *testing.T) {
+	input := `<p>See <a href="https://www.example.com/">this</a> and <strong>that</strong>.</p><p>Thanks!</p>`
+	if f := Compress(input); len(f) >= len(input)*3/4 {
+		t.Errorf("expected fewer than %d bytes, got %d", len(input)*3/4, len(f))
+	}
+
+	if f := Compress("x"); len(f) != 2 || f[0] != formatRaw {
+		t.Errorf("expected a raw fragment, got %v", f)
+	}
+}
+
+func TestCorrupt(t *testing.T) {
+	for _, f := range []Fragment{nil, {99}, {formatFlate1, 0xff, 0xff}} {
+		if _, err := f.Decode(); err != ErrCorrupt {
+			t.Errorf("%v: expected ErrCorrupt, got %v", f, err)
+		}
+		if _, err := f.WriteTo(&bytes.Buffer{}); err != ErrCorrupt {
+			t.Errorf("%v: expected ErrCorrupt from WriteTo, got %v", f, err)
+		}
+	}
+}
+
+type failWriter struct{}
+
+var errWrite = errors.New("write failed")
+
+func (failWriter) Write(p []byte) (int, error) { return 0, errWrite }
+
+func TestWriteToError(t *testing.T) {
+	if _, err := Compress("<p>a</p>").WriteTo(failWriter{}); err != errWrite {
+		t.Errorf("expected the writer's error, got %v", err)
+	}
+}
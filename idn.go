@@ -0,0 +1,102 @@
+package htmlcleaner
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/idna"
+)
+
+// IDNAction is what happens to links to internationalized domain names,
+// which can be used to imitate other domains with look-alike characters. See
+// Config.IDNLinks.
+type IDNAction int
+
+const (
+	// IDNAllow leaves links to internationalized domain names alone.
+	IDNAllow IDNAction = iota
+
+	// IDNReject removes the href attribute of the link.
+	IDNReject
+
+	// IDNTitle sets the title attribute of the link to the URL with its
+	// domain name in punycode, such as "https://xn--pple-43d.com/". The
+	// title is set even if the Config does not otherwise allow it.
+	IDNTitle
+
+	// IDNAnnotate appends the punycode domain name to the text of the
+	// link, in square brackets.
+	IDNAnnotate
+)
+
+// isIDN reports whether host is an internationalized domain name, either
+// because it contains non-ASCII characters or because it is already encoded
+// as punycode.
+func isIDN(host string) bool {
+	for _, r := range host {
+		if r >= 0x80 {
+			return true
+		}
+	}
+	for _, label := range strings.Split(host, ".") {
+		if strings.HasPrefix(label, "xn--") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIDN applies IDNLinks to the href of n, which has already been
+// cleaned. It reports whether the href was removed.
+func (c *cleaner) checkIDN(n *html.Node) bool {
+	if c.IDNLinks == IDNAllow || n.DataAtom != atom.A {
+		return false
+	}
+
+	for i, attr := range n.Attr {
+		if attr.Namespace != "" || attr.Key != "href" {
+			continue
+		}
+
+		u, err := url.Parse(attr.Val)
+		if err != nil || u.Host == "" {
+			return false
+		}
+		host := urlHost(u)
+		if !isIDN(host) {
+			return false
+		}
+
+		ascii, err := idna.ToASCII(host)
+		if c.IDNLinks == IDNReject || err != nil {
+			n.Attr = append(n.Attr[:i], n.Attr[i+1:]...)
+			c.removedAttrs++
+			c.rejectedURLs++
+			return true
+		}
+
+		if c.IDNLinks == IDNAnnotate {
+			n.AppendChild(text(" [" + ascii + "]"))
+			return false
+		}
+
+		display := *u
+		display.Host = ascii
+		if i := strings.LastIndex(u.Host, ":"); i != -1 {
+			display.Host += u.Host[i:]
+		}
+		title := html.Attribute{Key: "title", Val: display.String()}
+		for j := range n.Attr {
+			if n.Attr[j].Namespace == "" && n.Attr[j].Key == "title" {
+				n.Attr[j] = title
+				return false
+			}
+		}
+		n.Attr = append(n.Attr, title)
+		return false
+	}
+
+	return false
+}
@@ -0,0 +1,42 @@
+package htmlcleaner
+
+import "testing"
+
+var idnRejectConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.IDNLinks = IDNReject
+
+	return c
+}()
+
+var idnTitleConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.IDNLinks = IDNTitle
+
+	return c
+}()
+
+var idnAnnotateConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.IDNLinks = IDNAnnotate
+
+	return c
+}()
+
+var testTableIDN = []testTable{
+	{"Allow", "<a href=\"https://аpple.com/\">x</a>", `<a href="https://%D0%B0pple.com/">x</a>`, nil},
+	{"Reject", "<a href=\"https://аpple.com/\">x</a>", `<a>x</a>`, idnRejectConfig},
+	{"RejectPunycode", `<a href="https://xn--pple-43d.com/">x</a>`, `<a>x</a>`, idnRejectConfig},
+	{"RejectASCII", `<a href="https://apple.com/">x</a>`, `<a href="https://apple.com/">x</a>`, idnRejectConfig},
+	{"RejectRelative", `<a href="/path">x</a>`, `<a href="/path">x</a>`, idnRejectConfig},
+	{"Title", "<a href=\"https://аpple.com:8080/a\" title=\"t\">x</a>", `<a href="https://%D0%B0pple.com:8080/a" title="https://xn--pple-43d.com:8080/a">x</a>`, idnTitleConfig},
+	{"Annotate", "<a href=\"https://аpple.com/\">x</a>", `<a href="https://%D0%B0pple.com/">x [xn--pple-43d.com]</a>`, idnAnnotateConfig},
+	{"OtherElement", "<img src=\"https://аpple.com/a.png\">", `<img src="https://%D0%B0pple.com/a.png"/>`, idnRejectConfig},
+}
+
+func TestIDNLinks(t *testing.T) {
+	doTableTest(Clean, t, testTableIDN)
+}
@@ -0,0 +1,92 @@
+package htmlcleaner
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Image describes an <img> element found by ExtractImages.
+type Image struct {
+	// The position of the image in the fragment, counting from 0, among
+	// the images that were extracted.
+	Index int
+
+	Src   string
+	Alt   string
+	Title string
+
+	// The dimensions from the width and height attributes, or 0 if they
+	// are missing or not a whole number of pixels.
+	Width, Height int
+}
+
+// ExtractImages cleans a fragment of HTML using the specified Config, or the
+// FallbackConfig if it is nil, and returns the images that remain, in document
+// order.
+func ExtractImages(c *Config, fragment string) []Image {
+	var images []Image
+
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.Img {
+			if src, ok := getAttr(n, "src"); ok {
+				img := Image{Index: len(images), Src: src}
+				img.Alt, _ = getAttr(n, "alt")
+				img.Title, _ = getAttr(n, "title")
+				img.Width = dimension(n, "width")
+				img.Height = dimension(n, "height")
+				images = append(images, img)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	for _, n := range cleanFragment(c, fragment) {
+		visit(n)
+	}
+
+	return images
+}
+
+func dimension(n *html.Node, key string) int {
+	val, _ := getAttr(n, key)
+	d, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(val), "px"))
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// addDimensions applies ImageDimensions to n and reports whether it changed
+// n.
+func (c *Config) addDimensions(n *html.Node) bool {
+	if c.ImageDimensions == nil || n.DataAtom != atom.Img {
+		return false
+	}
+
+	src, ok := getAttr(n, "src")
+	if !ok {
+		return false
+	}
+	if _, ok = getAttr(n, "width"); ok {
+		return false
+	}
+	if _, ok = getAttr(n, "height"); ok {
+		return false
+	}
+
+	w, h, ok := c.ImageDimensions(src)
+	if !ok || w <= 0 || h <= 0 {
+		return false
+	}
+
+	n.Attr = append(n.Attr,
+		html.Attribute{Key: "width", Val: strconv.Itoa(w)},
+		html.Attribute{Key: "height", Val: strconv.Itoa(h)},
+	)
+	return true
+}
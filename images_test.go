@@ -0,0 +1,61 @@
+package htmlcleaner_test
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/html/atom"
+
+	"github.com/BenLubar/htmlcleaner"
+)
+
+func TestExtractImages(t *testing.T) {
+	c := htmlcleaner.DefaultConfig.Clone().ElemAttrAtom(atom.Img, atom.Width, atom.Height)
+
+	input := `<p><img src="a.png" alt="A" width="640" height="480px"></p>` +
+		`<img alt="no src"><img src="javascript:x()">` +
+		`<script><img src="escaped.png"></script>` +
+		`<b><img src="b.png" title="B" width="wide"></b>`
+
+	expected := []htmlcleaner.Image{
+		{Index: 0, Src: "a.png", Alt: "A", Width: 640, Height: 480},
+		{Index: 1, Src: "b.png", Title: "B"},
+	}
+
+	if actual := htmlcleaner.ExtractImages(c, input); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v", expected)
+		t.Errorf("actual   %+v", actual)
+	}
+}
+
+func TestImageDimensions(t *testing.T) {
+	c := htmlcleaner.DefaultConfig.Clone().ElemAttrAtom(atom.Img, atom.Width)
+	c.ImageDimensions = func(src string) (int, int, bool) {
+		switch src {
+		case "/a.png":
+			return 640, 480, true
+		case "/zero.png":
+			return 0, 480, true
+		}
+		return 0, 0, false
+	}
+
+	for _, tt := range []struct {
+		input, expected string
+	}{
+		{`<img src="/a.png">`, `<img src="/a.png" width="640" height="480"/>`},
+		{`<img src="/a.png" width="10">`, `<img src="/a.png" width="10"/>`},
+		{`<img src="/a.png" height="10">`, `<img src="/a.png" width="640" height="480"/>`},
+		{`<img src="/b.png">`, `<img src="/b.png"/>`},
+		{`<img src="/zero.png">`, `<img src="/zero.png"/>`},
+		{`<img alt="/a.png">`, ``},
+	} {
+		actual := htmlcleaner.Clean(c, tt.input)
+		if actual != tt.expected {
+			t.Errorf("%s: expected %q, actual %q", tt.input, tt.expected, actual)
+		}
+		if err := c.CheckOutput(actual); err != nil {
+			t.Error(err)
+		}
+	}
+}
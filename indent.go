@@ -0,0 +1,90 @@
+package htmlcleaner
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// RenderIndent renders nodes as indented HTML, with each block element
+// starting on its own line. Inline content is kept on a single line, and the
+// contents of elements such as <pre> and <code> are rendered unchanged.
+// Whitespace between block elements is not preserved.
+func RenderIndent(nodes []*html.Node, indent string) string {
+	var buf bytes.Buffer
+
+	renderIndentList(&buf, nodes, indent, 0)
+
+	return buf.String()
+}
+
+func renderIndentList(buf *bytes.Buffer, nodes []*html.Node, indent string, depth int) {
+	var inline []*html.Node
+	flush := func() {
+		if line := strings.TrimSpace(Render(inline...)); line != "" {
+			writeIndentLine(buf, indent, depth, line)
+		}
+		inline = inline[:0]
+	}
+
+	for _, n := range nodes {
+		if n.Type != html.ElementNode || !isBlockElement[n.DataAtom] {
+			inline = append(inline, n)
+			continue
+		}
+
+		flush()
+
+		if preservesWhitespace[n.DataAtom] || !hasBlockChild(n) {
+			writeIndentLine(buf, indent, depth, Render(n))
+			continue
+		}
+
+		start, end := splitTag(n)
+		writeIndentLine(buf, indent, depth, start)
+		renderIndentList(buf, childList(n), indent, depth+1)
+		writeIndentLine(buf, indent, depth, end)
+	}
+
+	flush()
+}
+
+func writeIndentLine(buf *bytes.Buffer, indent string, depth int, line string) {
+	for i := 0; i < depth; i++ {
+		buf.WriteString(indent)
+	}
+	buf.WriteString(line)
+	buf.WriteByte('\n')
+}
+
+func hasBlockChild(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && isBlockElement[c.DataAtom] {
+			return true
+		}
+	}
+	return false
+}
+
+func childList(n *html.Node) []*html.Node {
+	var children []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		children = append(children, c)
+	}
+	return children
+}
+
+// splitTag returns the start and end tags of an element.
+func splitTag(n *html.Node) (string, string) {
+	empty := &html.Node{
+		Type:      n.Type,
+		Data:      n.Data,
+		DataAtom:  n.DataAtom,
+		Namespace: n.Namespace,
+		Attr:      n.Attr,
+	}
+	s := Render(empty)
+	end := "</" + n.Data + ">"
+	return strings.TrimSuffix(s, end), end
+}
@@ -0,0 +1,19 @@
+package htmlcleaner
+
+import (
+	"testing"
+)
+
+var testTableRenderIndent = []testTable{
+	{"Empty", ``, ``, nil},
+	{"Inline", `hello <b>world</b>`, "hello <b>world</b>\n", nil},
+	{"Blocks", `<p>a</p> <p title="x">b <i>c</i></p>`, "<p>a</p>\n<p title=\"x\">b <i>c</i></p>\n", nil},
+	{"Nested", `<blockquote>a<blockquote><p>b</p></blockquote></blockquote>`, "<blockquote>\n\ta\n\t<blockquote>\n\t\t<p>b</p>\n\t</blockquote>\n</blockquote>\n", nil},
+	{"Pre", "<blockquote><pre>  a\n  b</pre></blockquote>", "<blockquote>\n\t<pre>  a\n  b</pre>\n</blockquote>\n", nil},
+}
+
+func TestRenderIndent(t *testing.T) {
+	doTableTest(func(c *Config, s string) string {
+		return RenderIndent(Parse(s), "\t")
+	}, t, testTableRenderIndent)
+}
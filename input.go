@@ -0,0 +1,69 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// InputAction is what Preprocess and Clean do when their input is longer
+// than Config.MaxInputBytes.
+type InputAction int
+
+const (
+	// InputTruncate processes as much of the input as fits, ending
+	// before the last tag, comment, or character that does not fit.
+	InputTruncate InputAction = iota
+
+	// InputAbort makes Preprocess and Clean return an empty string.
+	InputAbort
+)
+
+// limitInput applies MaxInputBytes to fragment, and reports whether the
+// fragment was too long.
+func (c *Config) limitInput(fragment string) (string, bool) {
+	if c.MaxInputBytes <= 0 || len(fragment) <= c.MaxInputBytes {
+		return fragment, false
+	}
+
+	if c.InputAction == InputAbort {
+		return "", true
+	}
+	return truncateInput(fragment[:c.MaxInputBytes]), true
+}
+
+// truncateInput removes an incomplete tag or character from the end of a
+// prefix of the input. Text is kept up to the last complete character, but
+// a tag that was cut short is removed entirely.
+func truncateInput(prefix string) string {
+	end, lastText := 0, -1
+	t := html.NewTokenizer(strings.NewReader(prefix))
+	for tok := t.Next(); tok != html.ErrorToken; tok = t.Next() {
+		lastText = -1
+		if tok == html.TextToken {
+			lastText = end
+		}
+		end += len(t.Raw())
+	}
+
+	// An incomplete tag at the end is returned by the tokenizer as an
+	// error, so it is not counted, but the contents of elements such as
+	// <script> are text, which can end partway through an end tag.
+	if lastText >= 0 {
+		if i := strings.LastIndexByte(prefix[lastText:end], '<'); i >= 0 && strings.IndexByte(prefix[lastText+i:end], '>') < 0 {
+			end = lastText + i
+		}
+	}
+
+	// Text can also end partway through a character.
+	start := end - 1
+	for start > 0 && !utf8.RuneStart(prefix[start]) {
+		start--
+	}
+	if start >= 0 && !utf8.FullRuneInString(prefix[start:end]) {
+		end = start
+	}
+
+	return prefix[:end]
+}
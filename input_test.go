@@ -0,0 +1,45 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+)
+
+var inputTruncateConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.MaxInputBytes = 12
+	c.InputAction = InputTruncate
+
+	return c
+}()
+
+var inputAbortConfig = func() *Config {
+	c := inputTruncateConfig.Clone()
+
+	c.InputAction = InputAbort
+
+	return c
+}()
+
+var testTableMaxInputBytes = []testTable{
+	{"Short", `<b>a</b>`, `<b>a</b>`, inputTruncateConfig},
+	{"Text", `<b>abcdefghijkl</b>`, `<b>abcdefghi</b>`, inputTruncateConfig},
+	{"Tag", `<b>a</b><i title="x">b</i>`, `<b>a</b>`, inputTruncateConfig},
+	{"Character", "<b>abcdefghé</b>", `<b>abcdefgh</b>`, inputTruncateConfig},
+	{"Abort", `<b>abcdefghijkl</b>`, ``, inputAbortConfig},
+}
+
+func TestMaxInputBytes(t *testing.T) {
+	doTableTest(Clean, t, testTableMaxInputBytes)
+	doTableTest(Preprocess, t, []testTable{
+		{"Preprocess", `<script>x</script>`, `&lt;script&gt;x`, inputTruncateConfig},
+	})
+
+	if _, err := CleanE(inputTruncateConfig, strings.Repeat("a", 100)); err != ErrTooLarge {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+	if _, err := CleanE(inputTruncateConfig, strings.Repeat("a", 12)); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package htmlcleaner
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// SanitizedHTML is a fragment of HTML that has been cleaned, such as the
+// output of Clean.
+type SanitizedHTML string
+
+// Join combines cleaned fragments into one. Unlike concatenating the
+// strings, each fragment is parsed on its own, so an element left open in
+// one fragment cannot swallow the next, and an id used by an earlier
+// fragment is given a numeric suffix in later ones, along with links to it
+// from the same fragment.
+func Join(fragments ...SanitizedHTML) SanitizedHTML {
+	ids := make(map[string]bool)
+
+	var nodes []*html.Node
+	for _, f := range fragments {
+		parsed := Parse(string(f))
+
+		renamed := make(map[string]string)
+		for _, n := range parsed {
+			renameIDs(n, ids, renamed)
+		}
+		if len(renamed) != 0 {
+			for _, n := range parsed {
+				renameFragmentLinks(n, renamed)
+			}
+		}
+
+		nodes = append(nodes, parsed...)
+	}
+
+	return SanitizedHTML(Render(nodes...))
+}
+
+// renameIDs gives each id in n that is already in ids a unique suffix,
+// recording the new name in renamed, and adds it to ids.
+func renameIDs(n *html.Node, ids map[string]bool, renamed map[string]string) {
+	Walk(n, func(n *html.Node) WalkAction {
+		for i, a := range n.Attr {
+			if a.Namespace != "" || a.Key != "id" {
+				continue
+			}
+			id := a.Val
+			if ids[id] {
+				if _, ok := renamed[id]; !ok {
+					for suffix := 2; ids[id]; suffix++ {
+						id = a.Val + "-" + strconv.Itoa(suffix)
+					}
+					renamed[a.Val] = id
+				} else {
+					id = renamed[a.Val]
+				}
+				n.Attr[i].Val = id
+			}
+			ids[id] = true
+		}
+		return WalkContinue
+	})
+}
+
+// renameFragmentLinks updates links such as href="#id" to renamed ids.
+func renameFragmentLinks(n *html.Node, renamed map[string]string) {
+	Walk(n, func(n *html.Node) WalkAction {
+		for i, a := range n.Attr {
+			if a.Namespace == "" && a.Key == "href" && strings.HasPrefix(a.Val, "#") {
+				if id, ok := renamed[a.Val[1:]]; ok {
+					n.Attr[i].Val = "#" + id
+				}
+			}
+		}
+		return WalkContinue
+	})
+}
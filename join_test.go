@@ -0,0 +1,23 @@
+package htmlcleaner
+
+import "testing"
+
+func TestJoin(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		fragments []SanitizedHTML
+		expected  SanitizedHTML
+	}{
+		{"Empty", nil, ``},
+		{"Simple", []SanitizedHTML{`<p>a</p>`, `b`}, `<p>a</p>b`},
+		{"Unclosed", []SanitizedHTML{`<b>a`, `b`}, `<b>a</b>b`},
+		{"UnclosedTable", []SanitizedHTML{`<table><tr><td>a`, `b`}, `<table><tbody><tr><td>a</td></tr></tbody></table>b`},
+		{"StrayEndTag", []SanitizedHTML{`<i>a`, `</i>b`}, `<i>a</i>b`},
+		{"IDs", []SanitizedHTML{`<h2 id="x">a</h2>`, `<h2 id="x">b</h2><a href="#x">c</a>`, `<h2 id="x">d</h2><h2 id="x-2">e</h2>`}, `<h2 id="x">a</h2><h2 id="x-2">b</h2><a href="#x-2">c</a><h2 id="x-3">d</h2><h2 id="x-2-2">e</h2>`},
+		{"SameFragment", []SanitizedHTML{`<a href="#y">a</a><p id="y">b</p>`}, `<a href="#y">a</a><p id="y">b</p>`},
+	} {
+		if actual := Join(tt.fragments...); actual != tt.expected {
+			t.Errorf("%s: expected %q, actual %q", tt.name, tt.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package htmlcleaner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CleanJSON copies a JSON document from r to w, cleaning string values whose
+// location matches one of paths using the specified Config, or the
+// FallbackConfig if it is nil. The document is processed one token at a time,
+// so it never has to be decoded into application types.
+//
+// Paths are written like "$.post.body" or "$.comments[*].text". A path
+// starts with "$", followed by any number of ".name" segments to select an
+// object member and "[n]" segments to select an array element. The name "*"
+// matches any object member and "[*]" matches any array element. Object
+// members never match array segments, even if their names look like "[0]".
+//
+// Whitespace in the document is not preserved.
+func CleanJSON(c *Config, w io.Writer, r io.Reader, paths ...string) error {
+	selectors := make([][]jsonSegment, len(paths))
+	for i, p := range paths {
+		sel, err := parseJSONPath(p)
+		if err != nil {
+			return err
+		}
+		selectors[i] = sel
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	bw := bufio.NewWriter(w)
+
+	type frame struct {
+		array  bool
+		index  int
+		member bool // next token is a member name
+	}
+	var stack []frame
+	var path []jsonSegment
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			if len(stack) != 0 {
+				return io.ErrUnexpectedEOF
+			}
+			return bw.Flush()
+		}
+		if err != nil {
+			return err
+		}
+
+		if d, ok := tok.(json.Delim); ok && (d == '}' || d == ']') {
+			bw.WriteByte(byte(d))
+			stack = stack[:len(stack)-1]
+			if len(stack) != 0 {
+				path = path[:len(path)-1]
+			}
+			if len(stack) != 0 {
+				top := &stack[len(stack)-1]
+				if top.array {
+					top.index++
+				} else {
+					top.member = true
+				}
+			}
+			continue
+		}
+
+		if len(stack) != 0 {
+			top := &stack[len(stack)-1]
+			if top.member {
+				if top.index != 0 {
+					bw.WriteByte(',')
+				}
+				top.index++
+				top.member = false
+				name := tok.(string)
+				writeJSONString(bw, name)
+				bw.WriteByte(':')
+				path = append(path, jsonSegment{name: name})
+				continue
+			}
+			if top.array {
+				if top.index != 0 {
+					bw.WriteByte(',')
+				}
+				path = append(path, jsonSegment{index: true, name: strconv.Itoa(top.index)})
+			}
+		}
+
+		switch v := tok.(type) {
+		case json.Delim:
+			bw.WriteByte(byte(v))
+			stack = append(stack, frame{array: v == '[', member: v == '{'})
+			continue
+		case string:
+			if matchJSONPath(selectors, path) {
+				v = Clean(c, v)
+			}
+			writeJSONString(bw, v)
+		case json.Number:
+			bw.WriteString(v.String())
+		case bool:
+			bw.WriteString(strconv.FormatBool(v))
+		case nil:
+			bw.WriteString("null")
+		}
+
+		if len(stack) != 0 {
+			top := &stack[len(stack)-1]
+			if top.array {
+				top.index++
+			} else {
+				top.member = true
+			}
+			path = path[:len(path)-1]
+		}
+	}
+}
+
+func writeJSONString(w *bufio.Writer, s string) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	// Encoding a string cannot fail.
+	expectError(enc.Encode(s), nil)
+
+	w.Write(bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}))
+}
+
+// jsonSegment is a step in a JSON path: an object member, or an array
+// element if index is true. In a path passed to CleanJSON, any matches every
+// member or element.
+type jsonSegment struct {
+	index bool
+	any   bool
+	name  string
+}
+
+func parseJSONPath(p string) ([]jsonSegment, error) {
+	if !strings.HasPrefix(p, "$") {
+		return nil, errors.New("htmlcleaner: JSON path must start with $: " + strconv.Quote(p))
+	}
+
+	var sel []jsonSegment
+	rest := p[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			if end == 0 {
+				return nil, errors.New("htmlcleaner: empty name in JSON path: " + strconv.Quote(p))
+			}
+			name := rest[1 : end+1]
+			sel = append(sel, jsonSegment{any: name == "*", name: name})
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, errors.New("htmlcleaner: unterminated [ in JSON path: " + strconv.Quote(p))
+			}
+			index := rest[1:end]
+			if index != "*" {
+				n, err := strconv.Atoi(index)
+				if err != nil || n < 0 {
+					return nil, errors.New("htmlcleaner: bad array index in JSON path: " + strconv.Quote(p))
+				}
+				index = strconv.Itoa(n)
+			}
+			sel = append(sel, jsonSegment{index: true, any: index == "*", name: index})
+			rest = rest[end+1:]
+		default:
+			return nil, errors.New("htmlcleaner: unexpected character in JSON path: " + strconv.Quote(p))
+		}
+	}
+
+	return sel, nil
+}
+
+func matchJSONPath(selectors [][]jsonSegment, path []jsonSegment) bool {
+	for _, sel := range selectors {
+		if len(sel) != len(path) {
+			continue
+		}
+
+		match := true
+		for i, s := range sel {
+			match = s.index == path[i].index && (s.any || s.name == path[i].name)
+			if !match {
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,77 @@
+package htmlcleaner_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/BenLubar/htmlcleaner"
+)
+
+func TestCleanJSON(t *testing.T) {
+	input := `{
+		"title": "<b>not cleaned</b>",
+		"body": "<b>bold</b><script>x()</script>",
+		"count": 1.50,
+		"ok": true,
+		"none": null,
+		"comments": [
+			{"text": "<a href=\"javascript:x()\">a</a>", "author": "<i>b</i>"},
+			{"text": "<i>c</i>", "tags": ["<b>d</b>"]}
+		],
+		"extra": {"x": "<u>e</u>", "y": ["<u>f</u>"]}
+	}`
+
+	expected := `{"title":"<b>not cleaned</b>","body":"<b>bold</b>&lt;script&gt;x()&lt;/script&gt;","count":1.50,"ok":true,"none":null,` +
+		`"comments":[{"text":"<a>a</a>","author":"<i>b</i>"},{"text":"<i>c</i>","tags":["<b>d</b>"]}],` +
+		`"extra":{"x":"<u>e</u>","y":["<u>f</u>"]}}`
+
+	var buf bytes.Buffer
+	err := htmlcleaner.CleanJSON(nil, &buf, strings.NewReader(input), "$.body", "$.comments[*].text", "$.extra.*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := buf.String(); actual != expected {
+		t.Errorf("expected %s", expected)
+		t.Errorf("actual   %s", actual)
+	}
+}
+
+func TestCleanJSONErrors(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		path  string
+	}{
+		{`{}`, `body`},
+		{`{}`, `$.`},
+		{`{}`, `$[x]`},
+		{`{}`, `$[0`},
+		{`{"a": [`, `$.a`},
+		{`{"a" 1}`, `$.a`},
+	} {
+		var buf bytes.Buffer
+		if err := htmlcleaner.CleanJSON(nil, &buf, strings.NewReader(tt.input), tt.path); err == nil {
+			t.Errorf("expected error for %q with path %q", tt.input, tt.path)
+		}
+	}
+}
+
+func TestCleanJSONBracketMember(t *testing.T) {
+	for _, tt := range []struct {
+		input, path, expected string
+	}{
+		{`{"[0]": "<script>x</script>"}`, `$.*`, `{"[0]":"&lt;script&gt;x&lt;/script&gt;"}`},
+		{`{"[0]": "<script>x</script>"}`, `$[*]`, `{"[0]":"<script>x</script>"}`},
+		{`{"[0]": "<script>x</script>"}`, `$[0]`, `{"[0]":"<script>x</script>"}`},
+		{`["<script>x</script>"]`, `$[0]`, `["&lt;script&gt;x&lt;/script&gt;"]`},
+		{`["<script>x</script>"]`, `$.*`, `["<script>x</script>"]`},
+	} {
+		var buf bytes.Buffer
+		if err := htmlcleaner.CleanJSON(nil, &buf, strings.NewReader(tt.input), tt.path); err != nil {
+			t.Errorf("%s with path %s: %v", tt.input, tt.path, err)
+		} else if actual := buf.String(); actual != tt.expected {
+			t.Errorf("%s with path %s: expected %s, actual %s", tt.input, tt.path, tt.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package htmlcleaner
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// legacyRawText is the set of legacy elements whose contents the parser
+// keeps as raw text. A <plaintext> element, or an <xmp> element without an
+// end tag, holds the rest of the fragment.
+var legacyRawText = map[atom.Atom]bool{
+	atom.Plaintext: true,
+	atom.Xmp:       true,
+}
+
+// reparseLegacy replaces a disallowed <plaintext> or <xmp> element with its
+// contents parsed as HTML and cleaned, so that the rest of the fragment is
+// neither lost nor shown as source code. Unless unwrap is set, the tags of
+// the element are kept as text, as Preprocess does. The contents are parsed
+// with the depth that remains below n, and MaxChildren and MaxNodes are
+// applied to them.
+func reparseLegacy(c *cleaner, n *html.Node, unwrap bool) *html.Node {
+	var raw string
+	for child := n.FirstChild; child != nil; child = n.FirstChild {
+		if child.Type == html.TextNode {
+			raw += child.Data
+		}
+		n.RemoveChild(child)
+	}
+
+	if !unwrap {
+		c.escapedElems++
+		if c.counts != nil {
+			count(&c.counts.EscapedElems, n.Data, 1)
+		}
+
+		tag := &html.Node{Type: html.ElementNode, Data: n.Data, DataAtom: n.DataAtom, Attr: n.Attr}
+		start := strings.TrimSuffix(Render(tag), "</"+n.Data+">")
+		n.AppendChild(text(html.UnescapeString(start)))
+	}
+
+	// n is at depth c.depth+1, so its contents start at c.depth+2
+	var nodes []*html.Node
+	if maxDepth := DefaultMaxDepth - c.depth - 1; maxDepth > 0 {
+		var omitted bool
+		nodes, omitted = parseDepth(raw, maxDepth)
+		c.tooDeep = c.tooDeep || omitted
+	} else if raw != "" {
+		nodes, c.tooDeep = []*html.Node{text(omittedMarker)}, true
+	}
+	for _, child := range c.limitBreadth(nodes) {
+		n.AppendChild(child)
+	}
+
+	if !unwrap && n.DataAtom != atom.Plaintext {
+		n.AppendChild(text("</" + n.Data + ">"))
+	}
+
+	return unwrapNode(c, n)
+}
@@ -0,0 +1,54 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html/atom"
+)
+
+var legacyUnwrapConfig = DefaultConfig.Clone().UnwrapAtom(atom.Plaintext, atom.Xmp)
+
+var testTableLegacy = []testTable{
+	{"Plaintext", `a<plaintext>b<b>c</b>`, `a&lt;plaintext&gt;b<b>c</b>`, nil},
+	{"PlaintextScript", `<plaintext><script>x()</script><i>y</i>`, `&lt;plaintext&gt;&lt;script&gt;x()&lt;/script&gt;<i>y</i>`, nil},
+	{"PlaintextAttr", `<plaintext title="&quot;>">a`, `&lt;plaintext title=&#34;&#34;&gt;&#34;&gt;a`, nil},
+	{"Nested", `<plaintext>a<plaintext>b<i>c</i>`, `&lt;plaintext&gt;a&lt;plaintext&gt;b<i>c</i>`, nil},
+	{"Xmp", `a<xmp>b<b>c</b></xmp><i>d</i>`, `a&lt;xmp&gt;b<b>c</b>&lt;/xmp&gt;<i>d</i>`, nil},
+	{"XmpUnclosed", `a<xmp>b<b>c</b><p>d`, `a&lt;xmp&gt;b<b>c</b><p>d</p>&lt;/xmp&gt;`, nil},
+	{"Empty", `a<xmp></xmp>b`, `a&lt;xmp&gt;&lt;/xmp&gt;b`, nil},
+	{"Unwrap", `a<plaintext>b<b>c</b>`, `ab<b>c</b>`, legacyUnwrapConfig},
+	{"UnwrapXmp", `a<xmp>b<b>c</b></xmp><i>d</i>`, `ab<b>c</b><i>d</i>`, legacyUnwrapConfig},
+	{"Allowed", `<xmp><b>c</b></xmp>`, `<xmp><b>c</b></xmp>`, DefaultConfig.Clone().ElemAtom(atom.Xmp)},
+}
+
+func TestLegacyRawText(t *testing.T) {
+	doTableTest(Clean, t, testTableLegacy)
+	doTableTest(func(c *Config, fragment string) string {
+		return Render(CleanNodes(c, Parse(fragment))...)
+	}, t, testTableLegacy)
+}
+
+func TestLegacyRawTextDepth(t *testing.T) {
+	outer := strings.Repeat("<b>", DefaultMaxDepth-3)
+	output, err := CleanE(nil, outer+"<plaintext><i><i><i>x")
+	if err != ErrTooDeep {
+		t.Errorf("expected ErrTooDeep, got %v", err)
+	}
+	if expected := outer[:len(outer)-3] + `<b>&lt;plaintext&gt;<i><i>[omitted]</i></i>`; !strings.HasPrefix(output, expected) {
+		t.Errorf("unexpected output %q", output)
+	}
+	if violations := Verify(nil, output); len(violations) != 0 {
+		t.Errorf("unexpected violations %+v", violations)
+	}
+}
+
+func TestLegacyRawTextBreadth(t *testing.T) {
+	c := DefaultConfig.Clone()
+	c.MaxNodes = 4
+
+	expected := `&lt;plaintext&gt;<b>1</b>[omitted]`
+	if actual := Clean(c, `<plaintext><b>1</b><b>2</b><b>3</b>`); actual != expected {
+		t.Errorf("expected %q, actual %q", expected, actual)
+	}
+}
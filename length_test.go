@@ -0,0 +1,24 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+)
+
+var (
+	longURL  = "https://example.com/" + strings.Repeat("a", DefaultMaxURLLength)
+	longText = strings.Repeat("a", DefaultMaxAttrLength+1)
+)
+
+var testTableLength = []testTable{
+	{"ShortURL", `<a href="https://example.com/">x</a>`, `<a href="https://example.com/">x</a>`, nil},
+	{"LongURL", `<a href="` + longURL + `">x</a>`, `<a>x</a>`, nil},
+	{"LongDataURL", `<img src="data:image/png;base64,` + strings.Repeat("AAAA", DefaultMaxURLLength) + `">`, ``, nil},
+	{"LongTitle", `<b title="` + longText + `">x</b>`, `<b>x</b>`, nil},
+	{"LongTitleURLLimit", `<b title="` + longURL + `">x</b>`, `<b title="` + longURL + `">x</b>`, nil},
+	{"Unlimited", `<a href="` + longURL + `">x</a>`, `<a href="` + longURL + `">x</a>`, (&Config{}).ElemAttr("a", "href")},
+}
+
+func TestAttrLength(t *testing.T) {
+	doTableTest(Clean, t, testTableLength)
+}
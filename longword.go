@@ -0,0 +1,161 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// looksLikeURL reports whether a word should be left alone by
+// BreakLongWords because breaking it would change a URL when it is copied.
+func looksLikeURL(word string) bool {
+	return strings.Contains(word, "://") || strings.HasPrefix(word, "www.")
+}
+
+// breakLongWords applies BreakLongWords and MaxWordLength to the text of
+// nodes, skipping the same elements as text rules.
+func breakLongWords(c *Config, nodes []*html.Node) []*html.Node {
+	broken := make([]*html.Node, 0, len(nodes))
+	for _, n := range nodes {
+		if n.Type == html.TextNode {
+			broken = append(broken, c.breakWords(n.Data)...)
+			continue
+		}
+		breakLongWordsChildren(c, n)
+		broken = append(broken, n)
+	}
+	return broken
+}
+
+func breakLongWordsChildren(c *Config, n *html.Node) {
+	if n.Type != html.ElementNode || skipTextRules[n.DataAtom] || preservesWhitespace[n.DataAtom] {
+		return
+	}
+
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+		if child.Type == html.TextNode {
+			for _, b := range c.breakWords(child.Data) {
+				n.InsertBefore(b, child)
+			}
+			n.RemoveChild(child)
+		} else {
+			breakLongWordsChildren(c, child)
+		}
+		child = next
+	}
+}
+
+// MaxWordAction is what happens to words longer than Config.MaxWordLength.
+type MaxWordAction int
+
+const (
+	// MaxWordSplit splits long words with spaces.
+	MaxWordSplit MaxWordAction = iota
+
+	// MaxWordTruncate cuts long words short, adding an ellipsis, and
+	// keeps the whole word in the title of a <span>.
+	MaxWordTruncate
+)
+
+// wordBreaker builds the nodes for text with long words broken up.
+type wordBreaker struct {
+	*Config
+	nodes []*html.Node
+	buf   []byte
+}
+
+func (b *wordBreaker) flush() {
+	if len(b.buf) != 0 {
+		b.nodes = append(b.nodes, text(string(b.buf)))
+		b.buf = b.buf[:0]
+	}
+}
+
+// word adds a word, or whitespace, with a soft hyphen or <wbr> after every
+// BreakLongWords characters if it is too long.
+func (b *wordBreaker) word(word string) {
+	if b.BreakLongWords <= 0 || utf8.RuneCountInString(word) <= b.BreakLongWords || looksLikeURL(word) {
+		b.buf = append(b.buf, word...)
+		return
+	}
+
+	for count := 0; word != ""; count++ {
+		if count != 0 && count%b.BreakLongWords == 0 {
+			if b.BreakWithWBR {
+				b.flush()
+				b.nodes = append(b.nodes, &html.Node{Type: html.ElementNode, Data: "wbr", DataAtom: atom.Wbr})
+			} else {
+				b.buf = append(b.buf, "\u00ad"...)
+			}
+		}
+		_, size := utf8.DecodeRuneInString(word)
+		b.buf = append(b.buf, word[:size]...)
+		word = word[size:]
+	}
+}
+
+// longWord adds a word that is longer than MaxWordLength.
+func (b *wordBreaker) longWord(word string) {
+	if b.MaxWordAction == MaxWordTruncate {
+		span := &html.Node{
+			Type:     html.ElementNode,
+			Data:     "span",
+			DataAtom: atom.Span,
+			Attr:     []html.Attribute{{Key: "title", Val: word}},
+		}
+		inner := &wordBreaker{Config: b.Config}
+		inner.word(truncateText(word, b.MaxWordLength))
+		inner.flush()
+		for _, n := range inner.nodes {
+			span.AppendChild(n)
+		}
+
+		b.flush()
+		b.nodes = append(b.nodes, span)
+		return
+	}
+
+	// Walk the word once, cutting it every MaxWordLength characters.
+	start, count := 0, 0
+	for i := 0; i < len(word); {
+		_, size := utf8.DecodeRuneInString(word[i:])
+		i += size
+		count++
+		if count == b.MaxWordLength || i == len(word) {
+			if start != 0 {
+				b.buf = append(b.buf, ' ')
+			}
+			b.word(word[start:i])
+			start, count = i, 0
+		}
+	}
+}
+
+// breakWords splits s into nodes according to BreakLongWords and
+// MaxWordLength.
+func (c *Config) breakWords(s string) []*html.Node {
+	b := &wordBreaker{Config: c}
+
+	for s != "" {
+		end := strings.IndexFunc(s, isHTMLSpace)
+		if end == -1 {
+			end = len(s)
+		} else if end == 0 {
+			end = 1
+		}
+		word := s[:end]
+		s = s[end:]
+
+		if c.MaxWordLength > 0 && utf8.RuneCountInString(word) > c.MaxWordLength {
+			b.longWord(word)
+		} else {
+			b.word(word)
+		}
+	}
+	b.flush()
+
+	return b.nodes
+}
@@ -0,0 +1,68 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var longWordConfig = &Config{BreakLongWords: 5}
+
+var longWordWBRConfig = (&Config{BreakLongWords: 5, BreakWithWBR: true}).Elem("a", "b", "code", "pre")
+
+var testTableLongWords = []testTable{
+	{"Short", `aaaaa bbbbb`, `aaaaa bbbbb`, longWordConfig},
+	{"SoftHyphen", `aaaaaaaaaaaa b`, "aaaaa\u00adaaaaa\u00adaa b", longWordConfig},
+	{"Runes", `ééééééé`, "ééééé\u00adéé", longWordConfig},
+	{"InvalidUTF8", "aaaaaaaaaaa\xff", "aaaaa\u00adaaaaa\u00ada\xff", longWordConfig},
+	{"URL", `https://example.com/aaaaaaaaa www.example.com`, `https://example.com/aaaaaaaaa www.example.com`, longWordConfig},
+	{"WBR", `x aaaaaaaaaaa y`, `x aaaaa<wbr/>aaaaa<wbr/>a y`, longWordWBRConfig},
+	{"Inline", `<b>aaaaaaa</b>`, `<b>aaaaa<wbr/>aa</b>`, longWordWBRConfig},
+	{"Skipped", `<a>aaaaaaa</a><code>aaaaaaa</code><pre>aaaaaaa</pre>`, `<a>aaaaaaa</a><code>aaaaaaa</code><pre>aaaaaaa</pre>`, longWordWBRConfig},
+}
+
+func TestBreakLongWords(t *testing.T) {
+	doTableTest(Clean, t, testTableLongWords)
+
+	// The added <wbr> elements are allowed by Verify.
+	c := longWordWBRConfig.Clone()
+	c.Verify = func(err error) { t.Error(err) }
+	if output := Clean(c, `aaaaaaa`); output != `aaaaa<wbr/>aa` {
+		t.Errorf("unexpected output %q", output)
+	}
+}
+
+var maxWordConfig = (&Config{MaxWordLength: 4}).Elem("a")
+
+var maxWordTruncateConfig = &Config{MaxWordLength: 4, MaxWordAction: MaxWordTruncate, BreakLongWords: 2}
+
+var testTableMaxWord = []testTable{
+	{"Short", `aaaa b`, `aaaa b`, maxWordConfig},
+	{"Split", `x aaaaaaaaaa y`, `x aaaa aaaa aa y`, maxWordConfig},
+	{"SplitRunes", `éééééé`, `éééé éé`, maxWordConfig},
+	{"SplitURL", `https://example.com/`, `http s:// exam ple. com/`, maxWordConfig},
+	{"SkipLink", `<a>aaaaaaaaaa</a>`, `<a>aaaaaaaaaa</a>`, maxWordConfig},
+	{"Truncate", `x abcdefgh y`, "x <span title=\"abcdefgh\">ab\u00adc…</span> y", maxWordTruncateConfig},
+}
+
+func TestMaxWordLength(t *testing.T) {
+	doTableTest(Clean, t, testTableMaxWord)
+
+	// A long word must not take quadratic time.
+	word := strings.Repeat("é", 200000)
+	start := time.Now()
+	output := Clean(maxWordConfig, word)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("cleaning a long word took %v", elapsed)
+	}
+	if expected := strings.Repeat("éééé ", 49999) + "éééé"; output != expected {
+		t.Errorf("unexpected output of %d bytes", len(output))
+	}
+
+	// The added <span title> elements are allowed by Verify.
+	c := maxWordTruncateConfig.Clone()
+	c.Verify = func(err error) { t.Error(err) }
+	if output := Clean(c, `abcdefgh`); output != "<span title=\"abcdefgh\">ab\u00adc…</span>" {
+		t.Errorf("unexpected output %q", output)
+	}
+}
@@ -0,0 +1,68 @@
+package htmlcleaner
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Highlight cleans a fragment of HTML like Clean, and wraps each match of
+// one of the search terms in its text with the node returned by wrap, or in
+// a <mark> element if wrap is nil. Terms are matched case-insensitively,
+// longest first. Like the replacements of text rules, text inside <a>,
+// <code>, <pre>, <kbd>, and <samp> elements and attribute values are not
+// searched, and the nodes returned by wrap are not cleaned, so they must be
+// safe.
+func Highlight(c *Config, fragment string, terms []string, wrap func(match string) *html.Node) string {
+	cl := newCleaner(c)
+
+	if re := termsPattern(terms); re != nil {
+		if wrap == nil {
+			wrap = mark
+		}
+
+		cl.rules = append(cl.rules[:len(cl.rules):len(cl.rules)], textRule{
+			re:      re,
+			trusted: true,
+			build: func(m []string) ([]*html.Node, bool) {
+				return []*html.Node{wrap(m[0])}, true
+			},
+		})
+	}
+
+	return cl.clean(fragment, nil)
+}
+
+// mark returns a <mark> element containing s.
+func mark(s string) *html.Node {
+	n := &html.Node{Type: html.ElementNode, Data: "mark", DataAtom: atom.Mark}
+	n.AppendChild(text(s))
+	return n
+}
+
+type byLength []string
+
+func (s byLength) Len() int           { return len(s) }
+func (s byLength) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byLength) Less(i, j int) bool { return len(s[i]) > len(s[j]) }
+
+// termsPattern returns a regular expression matching any of terms, or nil
+// if there are no non-empty terms.
+func termsPattern(terms []string) *regexp.Regexp {
+	var quoted []string
+	for _, term := range terms {
+		if term != "" {
+			quoted = append(quoted, regexp.QuoteMeta(term))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+
+	sort.Stable(byLength(quoted))
+
+	return regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+}
@@ -0,0 +1,39 @@
+package htmlcleaner
+
+import (
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func TestHighlight(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		Input  string
+		Terms  []string
+		Output string
+	}{
+		{"Simple", `find the cat`, []string{"cat"}, `find the <mark>cat</mark>`},
+		{"CaseInsensitive", `Cat and CAT`, []string{"cat"}, `<mark>Cat</mark> and <mark>CAT</mark>`},
+		{"Longest", `category`, []string{"cat", "category"}, `<mark>category</mark>`},
+		{"Special", `a.b axb`, []string{"a.b"}, `<mark>a.b</mark> axb`},
+		{"Nested", `<b>the cat</b>`, []string{"cat"}, `<b>the <mark>cat</mark></b>`},
+		{"Skipped", `<code>cat</code><pre>cat</pre><a href="/cat" title="cat">cat</a>`, []string{"cat"}, `<code>cat</code><pre>cat</pre><a href="/cat" title="cat">cat</a>`},
+		{"Cleaned", `<script>cat</script>`, []string{"cat"}, `&lt;script&gt;<mark>cat</mark>&lt;/script&gt;`},
+		{"NoTerms", `cat`, []string{""}, `cat`},
+	} {
+		if actual := Highlight(nil, test.Input, test.Terms, nil); actual != test.Output {
+			t.Errorf("%s: expected %q, actual %q", test.Name, test.Output, actual)
+		}
+	}
+
+	wrap := func(match string) *html.Node {
+		n := &html.Node{Type: html.ElementNode, Data: "em", DataAtom: atom.Em}
+		n.AppendChild(text(match))
+		return n
+	}
+	if expected, actual := `a <em>cat</em>`, Highlight(nil, `a cat`, []string{"cat"}, wrap); expected != actual {
+		t.Errorf("expected %q, actual %q", expected, actual)
+	}
+}
@@ -0,0 +1,186 @@
+package htmlcleaner
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ToMarkdown cleans a fragment of HTML using the specified Config, or the
+// FallbackConfig if it is nil, and converts it to CommonMark. Elements with no
+// Markdown equivalent, such as <u>, are replaced with their contents.
+func ToMarkdown(c *Config, fragment string) string {
+	nodes := cleanFragment(c, fragment)
+	for _, n := range nodes {
+		escapeMarkdown(n)
+	}
+
+	w := newTextWriter(markdownElement)
+	w.nodes(nodes)
+
+	return w.String()
+}
+
+var (
+	markdownSpecial = strings.NewReplacer(
+		`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+		`<`, `\<`, `>`, `\>`, `#`, `\#`, `!`, `\!`, `|`, `\|`, `~`, `\~`,
+	)
+	markdownEntity   = regexp.MustCompile(`&(#?[0-9A-Za-z]+;)`)
+	markdownBlockish = regexp.MustCompile(`(?m)(^|[ \t])([-+=]|\d+[.)])([ \t]|$)`)
+)
+
+// escapeMarkdown escapes the text inside n that could be mistaken for
+// Markdown syntax, except inside code.
+func escapeMarkdown(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		s := markdownSpecial.Replace(n.Data)
+		s = markdownEntity.ReplaceAllString(s, `\&$1`)
+		s = markdownBlockish.ReplaceAllStringFunc(s, func(m string) string {
+			// escape the last punctuation character of list
+			// markers and thematic breaks
+			trimmed := strings.TrimRight(m, " \t")
+			i := len(trimmed) - 1
+			return m[:i] + `\` + m[i:]
+		})
+		n.Data = s
+	case html.ElementNode:
+		if n.DataAtom == atom.Code || n.DataAtom == atom.Kbd || n.DataAtom == atom.Tt || preservesWhitespace[n.DataAtom] {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			escapeMarkdown(c)
+		}
+	}
+}
+
+// longestRun returns the length of the longest run of ch in s.
+func longestRun(s string, ch rune) int {
+	longest, current := 0, 0
+	for _, r := range s {
+		if r != ch {
+			current = 0
+			continue
+		}
+		current++
+		if current > longest {
+			longest = current
+		}
+	}
+	return longest
+}
+
+// markdownURL returns a link destination, using angle brackets if the URL
+// contains characters that would end it early.
+func markdownURL(u string) string {
+	if strings.ContainsAny(u, " ()<>") {
+		return "<" + strings.NewReplacer("<", "%3C", ">", "%3E").Replace(u) + ">"
+	}
+	return u
+}
+
+func markdownElement(w *textWriter, n *html.Node) {
+	children := func() { w.children(n) }
+
+	switch n.DataAtom {
+	case atom.B, atom.Strong:
+		w.write("**")
+		children()
+		w.write("**")
+	case atom.I, atom.Em, atom.Cite:
+		w.write("*")
+		children()
+		w.write("*")
+	case atom.Code, atom.Kbd, atom.Tt:
+		code := textContent(n)
+		fence := strings.Repeat("`", longestRun(code, '`')+1)
+		if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+			code = " " + code + " "
+		}
+		w.write(fence + code + fence)
+	case atom.A:
+		href, _ := getAttr(n, "href")
+		if href == "" {
+			children()
+			return
+		}
+		w.write("[")
+		children()
+		w.write("](" + markdownURL(href) + ")")
+	case atom.Img:
+		src, _ := getAttr(n, "src")
+		alt, _ := getAttr(n, "alt")
+		w.write("![" + markdownSpecial.Replace(alt) + "](" + markdownURL(src) + ")")
+	case atom.Br:
+		w.write(`\`)
+		w.lineBreak()
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		w.block(2)
+		w.write(strings.Repeat("#", int(n.Data[1]-'0')) + " ")
+		children()
+		w.block(2)
+	case atom.Hr:
+		w.block(2)
+		w.write("* * *")
+		w.block(2)
+	case atom.Blockquote:
+		w.block(2)
+		w.indent("> ", children)
+		w.block(2)
+	case atom.Pre, atom.Listing, atom.Plaintext, atom.Xmp:
+		lang := codeLanguage(n)
+		if code := n.FirstChild; lang == "" && code != nil && code.DataAtom == atom.Code {
+			lang = codeLanguage(code)
+		}
+		text := preformattedText(n)
+		fence := strings.Repeat("`", longestRun(text, '`')+1)
+		if len(fence) < 3 {
+			fence = "```"
+		}
+
+		w.block(2)
+		w.write(fence + lang)
+		w.lineBreak()
+		w.preformatted(func() { w.text(text) })
+		w.lineBreak()
+		w.write(fence)
+		w.block(2)
+	case atom.Ul, atom.Ol:
+		// nested lists do not need a blank line
+		gap := 2
+		if n.Parent != nil && n.Parent.DataAtom == atom.Li {
+			gap = 1
+		}
+
+		w.block(gap)
+		i := 0
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+				w.node(c)
+				continue
+			}
+			i++
+			marker := "- "
+			if n.DataAtom == atom.Ol {
+				marker = strconv.Itoa(i) + ". "
+			}
+			w.block(1)
+			w.write(marker)
+			w.indent(strings.Repeat(" ", len(marker)), func() { w.children(c) })
+			w.block(1)
+		}
+		w.block(gap)
+	default:
+		if n.DataAtom == 0 || !isBlockElement[n.DataAtom] {
+			children()
+			return
+		}
+		w.block(2)
+		children()
+		w.block(2)
+	}
+}
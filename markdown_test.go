@@ -0,0 +1,29 @@
+package htmlcleaner
+
+import "testing"
+
+var markdownConfig = DefaultConfig.Clone().
+	Elem("h1", "h2", "ul", "ol", "li", "br", "hr").
+	CodeLanguages("go")
+
+var testTableMarkdown = []testTable{
+	{"Empty", ``, ``, markdownConfig},
+	{"Paragraphs", `<p>a</p><p>b</p>`, "a\n\nb", markdownConfig},
+	{"Emphasis", `<p><b>bold</b> and <i>italic</i> and <u>plain</u></p>`, "**bold** and *italic* and plain", markdownConfig},
+	{"Heading", `<h1>Title</h1><h2>Sub</h2>text`, "# Title\n\n## Sub\n\ntext", markdownConfig},
+	{"Link", `<a href="https://example.com/a_(b)">a <b>link</b></a>`, "[a **link**](<https://example.com/a_(b)>)", markdownConfig},
+	{"Image", `<img src="/a.png" alt="an [image]">`, `![an \[image\]](/a.png)`, markdownConfig},
+	{"Escape", `*not* _emphasis_ [x](y) # &amp;copy; \`, `\*not\* \_emphasis\_ \[x\](y) \# \&copy; \\`, markdownConfig},
+	{"EscapeList", "<p>- a</p><p>1. b</p><p>x - y</p>", "\\- a\n\n1\\. b\n\nx \\- y", markdownConfig},
+	{"InlineCode", "<code>a*b</code> <code>a`b</code> <code>`</code>", "`a*b` ``a`b`` `` ` ``", markdownConfig},
+	{"CodeBlock", "<pre><code class=\"language-go\">func f() {\n\treturn *x\n}</code></pre>", "```go\nfunc f() {\n\treturn *x\n}\n```", markdownConfig},
+	{"CodeBlockFence", "<pre>```\nx\n```</pre>", "````\n```\nx\n```\n````", markdownConfig},
+	{"Blockquote", `<blockquote><p>a</p><p>b</p></blockquote>c`, "> a\n>\n> b\n\nc", markdownConfig},
+	{"Lists", `<ul><li>a</li><li>b<ol><li>c</li><li>d</li></ol></li></ul>`, "- a\n- b\n  1. c\n  2. d", markdownConfig},
+	{"LineBreak", `a<br>b`, "a\\\nb", markdownConfig},
+	{"Rule", `a<hr>b`, "a\n\n* * *\n\nb", markdownConfig},
+}
+
+func TestToMarkdown(t *testing.T) {
+	doTableTest(ToMarkdown, t, testTableMarkdown)
+}
@@ -0,0 +1,93 @@
+package htmlcleaner
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	// mediaQuery matches media queries such as "(min-width: 40em)" without
+	// the characters needed to escape from a style sheet.
+	mediaQuery = regexp.MustCompile(`\A[a-zA-Z0-9 (),:./-]*\z`)
+
+	// mediaSizes matches sizes attributes such as
+	// "(max-width: 600px) 100vw, 50vw".
+	mediaSizes = regexp.MustCompile(`\A[a-zA-Z0-9 (),:./%+*-]*\z`)
+
+	// mediaType matches MIME types, optionally with a codecs parameter.
+	mediaType = regexp.MustCompile(`\A[a-zA-Z]+/[a-zA-Z0-9.+-]+(?:; ?codecs=(?:"[a-zA-Z0-9., +-]*"|[a-zA-Z0-9.+-]+))?\z`)
+
+	trackKind = regexp.MustCompile(`\A(?:subtitles|captions|descriptions|chapters|metadata)\z`)
+
+	srcsetDescriptor = regexp.MustCompile(`\A(?:[0-9]+w|[0-9]+(?:\.[0-9]+)?x)?\z`)
+)
+
+// ResponsiveMedia allows <picture> and <source> for responsive images, and
+// <source> and <track> in <video> and <audio>. The srcset and src attributes
+// are checked like any other URL, and media, sizes, type, kind, and srclang
+// must be well-formed. It also allows srcset and sizes on <img>. The
+// receiver is returned to allow call chaining.
+func (c *Config) ResponsiveMedia() *Config {
+	return c.ElemAtom(atom.Picture).
+		ElemAttrAtom(atom.Source, atom.Src, atom.Srcset).
+		ElemAttrAtomMatch(atom.Source, atom.Type, mediaType).
+		ElemAttrAtomMatch(atom.Source, atom.Media, mediaQuery).
+		ElemAttrAtomMatch(atom.Source, atom.Sizes, mediaSizes).
+		ElemAttrAtom(atom.Track, atom.Src).
+		ElemAttrAtomMatch(atom.Track, atom.Kind, trackKind).
+		ElemAttrAtomMatch(atom.Track, atom.Srclang, langTag).
+		ElemAttrAtom(atom.Img, atom.Srcset).
+		ElemAttrAtomMatch(atom.Img, atom.Sizes, mediaSizes).
+		RequireInsideAtom(atom.Source, atom.Picture, atom.Video, atom.Audio).
+		RequireInsideAtom(atom.Track, atom.Video, atom.Audio)
+}
+
+// cleanSrcset removes the image candidates in a srcset attribute whose URL
+// is rejected by check or whose descriptor is malformed, and returns the
+// remaining candidates.
+func cleanSrcset(srcset string, check func(*url.URL) bool) string {
+	var kept []string
+	for s := srcset; s != ""; {
+		s = strings.TrimLeft(s, " \t\n\f\r,")
+		if s == "" {
+			break
+		}
+
+		// The URL ends at whitespace, and trailing commas end the
+		// candidate.
+		end := strings.IndexAny(s, " \t\n\f\r")
+		if end == -1 {
+			end = len(s)
+		}
+		rawURL, descriptor := strings.TrimRight(s[:end], ","), ""
+		if len(rawURL) == end {
+			s = s[end:]
+			if comma := strings.IndexByte(s, ','); comma != -1 {
+				descriptor, s = s[:comma], s[comma+1:]
+			} else {
+				descriptor, s = s, ""
+			}
+			descriptor = strings.TrimSpace(descriptor)
+		} else {
+			s = s[end:]
+		}
+
+		if rawURL == "" || !srcsetDescriptor.MatchString(descriptor) {
+			continue
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil || (check != nil && !check(u)) {
+			continue
+		}
+
+		candidate := u.String()
+		if descriptor != "" {
+			candidate += " " + descriptor
+		}
+		kept = append(kept, candidate)
+	}
+	return strings.Join(kept, ", ")
+}
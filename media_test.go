@@ -0,0 +1,80 @@
+package htmlcleaner
+
+import (
+	"net/url"
+	"testing"
+
+	"golang.org/x/net/html/atom"
+)
+
+var mediaConfig = DefaultConfig.Clone().ResponsiveMedia()
+
+var testTableMedia = []testTable{
+	{"Picture", `<picture><source srcset="/a.webp 1x, /a@2x.webp 2x" type="image/webp" media="(min-width: 40em)"><img src="/a.png" alt="a"></picture>`, `<picture><source srcset="/a.webp 1x, /a@2x.webp 2x" type="image/webp" media="(min-width: 40em)"/><img src="/a.png" alt="a"/></picture>`, mediaConfig},
+	{"BadCandidate", `<picture><source srcset="javascript:alert(1) 1x, /a.webp 2x"></picture>`, `<picture><source srcset="/a.webp 2x"/></picture>`, mediaConfig},
+	{"BadSrcset", `<picture><source srcset="javascript:alert(1)"></picture>`, `<picture></picture>`, mediaConfig},
+	{"BadDescriptor", `<img src="/a.png" srcset="/b.png 2x, /c.png huge, /d.png 300w" sizes="(max-width: 600px) 100vw, 50vw">`, `<img src="/a.png" srcset="/b.png 2x, /d.png 300w" sizes="(max-width: 600px) 100vw, 50vw"/>`, mediaConfig},
+	{"CommaInURL", `<img srcset="/a,b.png 1x,/c.png, /d.png 3x">`, `<img srcset="/a,b.png 1x, /c.png, /d.png 3x"/>`, mediaConfig},
+	{"BadMedia", `<picture><source srcset="/a.webp" media="x{}" type="text/html&lt;"></picture>`, `<picture><source srcset="/a.webp"/></picture>`, mediaConfig},
+	{"Video", `<video src="/v.mp4" controls><source src="/v.webm" type='video/webm; codecs="vp8, vorbis"'><track src="/v.vtt" kind="captions" srclang="en-US"></video>`, `<video src="/v.mp4" controls=""><source src="/v.webm" type="video/webm; codecs=&#34;vp8, vorbis&#34;"/><track src="/v.vtt" kind="captions" srclang="en-US"/></video>`, mediaConfig},
+	{"BadTrack", `<video><track src="javascript:x" kind="evil" srclang="en"></video>`, `<video></video>`, mediaConfig},
+	{"TrackKind", `<audio><track src="/a.vtt" kind="evil"></audio>`, `<audio><track src="/a.vtt"/></audio>`, mediaConfig},
+	{"OutOfContext", `<source src="/a.webm"><track src="/a.vtt">`, `&lt;source src=&#34;/a.webm&#34;/&gt;&lt;track src=&#34;/a.vtt&#34;/&gt;`, mediaConfig},
+	{"NotAllowed", `<picture><source srcset="/a.webp"></picture>`, `&lt;picture&gt;&lt;source srcset=&#34;/a.webp&#34;/&gt;&lt;/picture&gt;`, nil},
+}
+
+func TestResponsiveMedia(t *testing.T) {
+	doTableTest(Clean, t, testTableMedia)
+}
+
+func TestSrcsetHosts(t *testing.T) {
+	c := mediaConfig.Clone().AllowHosts("img", "img.example.com").StripQuery("utm_*")
+
+	expected := `<img srcset="https://img.example.com/a.png?w=1 1x"/>`
+	actual := Clean(c, `<img srcset="https://img.example.com/a.png?w=1&amp;utm_source=x 1x, https://evil.example.com/a.png 2x">`)
+	if actual != expected {
+		t.Errorf("expected %q, actual %q", expected, actual)
+	}
+
+	resolved := cleanSrcset("a.png 1x, /b.png 2x", func(u *url.URL) bool {
+		*u = *(&url.URL{Scheme: "https", Host: "example.com", Path: "/x/"}).ResolveReference(u)
+		return true
+	})
+	if expected = "https://example.com/x/a.png 1x, https://example.com/b.png 2x"; resolved != expected {
+		t.Errorf("expected %q, actual %q", expected, resolved)
+	}
+}
+
+var sandboxMediaConfig = func() *Config {
+	c := DefaultConfig.Clone().ElemAttrAtom(atom.Video, atom.Autoplay, atom.Loop, atom.Preload)
+
+	c.SandboxMedia = true
+
+	return c
+}()
+
+var sandboxMediaCORSConfig = func() *Config {
+	c := sandboxMediaConfig.Clone()
+
+	c.MediaCrossOrigin = "anonymous"
+
+	return c
+}()
+
+var testTableSandboxMedia = []testTable{
+	{"Video", `<video src="/v.mp4" autoplay loop preload="auto"></video>`, `<video src="/v.mp4" controls="" preload="none"></video>`, sandboxMediaConfig},
+	{"Audio", `<audio src="/a.mp3" autoplay controls></audio>`, `<audio src="/a.mp3" controls="" preload="none"></audio>`, sandboxMediaConfig},
+	{"CrossOrigin", `<audio src="/a.mp3" crossorigin="use-credentials"></audio>`, `<audio src="/a.mp3" controls="" preload="none" crossorigin="anonymous"></audio>`, sandboxMediaCORSConfig},
+	{"Image", `<img src="/a.png" loop>`, `<img src="/a.png"/>`, sandboxMediaConfig},
+	{"Off", `<video src="/v.mp4" autoplay loop></video>`, `<video src="/v.mp4" autoplay="" loop=""></video>`, DefaultConfig.Clone().ElemAttrAtom(atom.Video, atom.Autoplay, atom.Loop)},
+}
+
+func TestSandboxMedia(t *testing.T) {
+	doTableTest(Clean, t, testTableSandboxMedia)
+
+	for _, tt := range testTableSandboxMedia {
+		if err := tt.Config.CheckOutput(tt.Output); err != nil {
+			t.Errorf("%s: %v", tt.Name, err)
+		}
+	}
+}
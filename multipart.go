@@ -0,0 +1,82 @@
+package htmlcleaner
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"strings"
+)
+
+// DefaultMaxFieldBytes is the default value of MultipartCleaner.MaxFieldBytes.
+const DefaultMaxFieldBytes = 1 << 20
+
+// ErrFieldTooLarge is returned by MultipartCleaner.NextPart when a field to be
+// cleaned is larger than MaxFieldBytes.
+var ErrFieldTooLarge = errors.New("htmlcleaner: multipart field too large")
+
+// MultipartCleaner wraps a multipart.Reader, cleaning the contents of
+// designated form fields as they are read, even if they are sent as file
+// uploads. Other parts are passed through unchanged without being buffered.
+type MultipartCleaner struct {
+	r      *multipart.Reader
+	config *Config
+	fields map[string]bool
+
+	// The maximum size of a field to be cleaned. Fields must be read
+	// fully before they can be cleaned, so this bounds the memory used.
+	MaxFieldBytes int64
+}
+
+// NewMultipartCleaner returns a MultipartCleaner that cleans the form fields
+// with the specified names using the specified Config, or the FallbackConfig if
+// it is nil.
+func NewMultipartCleaner(r *multipart.Reader, c *Config, fields ...string) *MultipartCleaner {
+	m := &MultipartCleaner{
+		r:             r,
+		config:        c,
+		fields:        make(map[string]bool, len(fields)),
+		MaxFieldBytes: DefaultMaxFieldBytes,
+	}
+	for _, f := range fields {
+		m.fields[f] = true
+	}
+	return m
+}
+
+// Part is a part of a multipart body returned by MultipartCleaner. Reading
+// from it returns the cleaned contents if the part is a field to be cleaned.
+type Part struct {
+	*multipart.Part
+
+	r io.Reader
+}
+
+// Read reads the possibly-cleaned contents of the part.
+func (p *Part) Read(b []byte) (int, error) {
+	return p.r.Read(b)
+}
+
+// NextPart returns the next part of the multipart body, or io.EOF if there
+// are no more parts.
+func (m *MultipartCleaner) NextPart() (*Part, error) {
+	p, err := m.r.NextPart()
+	if err != nil {
+		return nil, err
+	}
+
+	// A designated field is cleaned even if the client sent it with a
+	// filename, so that the filename cannot be used to skip cleaning.
+	if !m.fields[p.FormName()] {
+		return &Part{Part: p, r: p}, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(p, m.MaxFieldBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > m.MaxFieldBytes {
+		return nil, ErrFieldTooLarge
+	}
+
+	return &Part{Part: p, r: strings.NewReader(Clean(m.config, string(body)))}, nil
+}
@@ -0,0 +1,85 @@
+package htmlcleaner_test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"testing"
+
+	"github.com/BenLubar/htmlcleaner"
+)
+
+func testMultipartBody(t *testing.T) (*bytes.Buffer, string) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, f := range [][2]string{
+		{"title", "<b>title</b>"},
+		{"description", "<b>bold</b><script>x()</script>"},
+	} {
+		if err := w.WriteField(f[0], f[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range [][2]string{
+		{"description", "upload.html"},
+		{"attachment", "attachment.html"},
+	} {
+		fw, err := w.CreateFormFile(f[0], f[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = io.WriteString(fw, "<script>file contents</script>"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf, w.Boundary()
+}
+
+func TestMultipartCleaner(t *testing.T) {
+	body, boundary := testMultipartBody(t)
+	m := htmlcleaner.NewMultipartCleaner(multipart.NewReader(body, boundary), nil, "description")
+
+	expected := []string{
+		"<b>title</b>",
+		"<b>bold</b>&lt;script&gt;x()&lt;/script&gt;",
+		"&lt;script&gt;file contents&lt;/script&gt;",
+		"<script>file contents</script>",
+	}
+
+	for i := 0; ; i++ {
+		p, err := m.NextPart()
+		if err == io.EOF {
+			if i != len(expected) {
+				t.Errorf("expected %d parts, got %d", len(expected), i)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i < len(expected) && string(b) != expected[i] {
+			t.Errorf("part %d: expected %q, actual %q", i, expected[i], b)
+		}
+	}
+}
+
+func TestMultipartCleanerTooLarge(t *testing.T) {
+	body, boundary := testMultipartBody(t)
+	m := htmlcleaner.NewMultipartCleaner(multipart.NewReader(body, boundary), nil, "description")
+	m.MaxFieldBytes = int64(len("<b>bold</b>"))
+
+	if _, err := m.NextPart(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.NextPart(); err != htmlcleaner.ErrFieldTooLarge {
+		t.Errorf("expected ErrFieldTooLarge, got %v", err)
+	}
+}
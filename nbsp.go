@@ -0,0 +1,115 @@
+package htmlcleaner
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// NBSPAction is what happens to runs of non-breaking spaces. See Config.NBSP.
+type NBSPAction int
+
+const (
+	// NBSPKeep leaves non-breaking spaces alone.
+	NBSPKeep NBSPAction = iota
+
+	// NBSPSpace replaces each non-breaking space in a run with a regular
+	// space, so the text can wrap. With CollapseWhitespace, the run
+	// becomes a single space.
+	NBSPSpace
+
+	// NBSPSpan replaces each run with a <span> of class NBSPClass holding
+	// the same number of regular spaces, so that the indentation can be
+	// kept with CSS such as white-space: pre-wrap. The span is added
+	// even if the Config does not otherwise allow it.
+	NBSPSpan
+)
+
+// nbspRun matches runs of spaces and non-breaking spaces. Runs without a
+// non-breaking space are left alone.
+var nbspRun = regexp.MustCompile("[ \u00a0]{2,}")
+
+// nbspSpaces returns the regular spaces that replace a run, or "" if it has
+// no non-breaking spaces.
+func nbspSpaces(run string) string {
+	if !strings.ContainsRune(run, '\u00a0') {
+		return ""
+	}
+	return strings.Repeat(" ", utf8.RuneCountInString(run))
+}
+
+func normalizeNBSP(c *Config, nodes []*html.Node) []*html.Node {
+	parent := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range nodes {
+		parent.AppendChild(n)
+	}
+
+	normalizeNBSPChildren(c, parent)
+
+	nodes = nodes[:0]
+	for parent.FirstChild != nil {
+		n := parent.FirstChild
+		parent.RemoveChild(n)
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+func normalizeNBSPChildren(c *Config, parent *html.Node) {
+	for n := parent.FirstChild; n != nil; {
+		next := n.NextSibling
+
+		switch n.Type {
+		case html.TextNode:
+			normalizeNBSPText(c, n)
+		case html.ElementNode:
+			if !preservesWhitespace[n.DataAtom] {
+				normalizeNBSPChildren(c, n)
+			}
+		}
+
+		n = next
+	}
+}
+
+func normalizeNBSPText(c *Config, n *html.Node) {
+	if c.NBSP == NBSPSpace {
+		n.Data = nbspRun.ReplaceAllStringFunc(n.Data, func(run string) string {
+			if spaces := nbspSpaces(run); spaces != "" {
+				return spaces
+			}
+			return run
+		})
+		return
+	}
+
+	s, start := n.Data, 0
+	for _, loc := range nbspRun.FindAllStringIndex(s, -1) {
+		spaces := nbspSpaces(s[loc[0]:loc[1]])
+		if spaces == "" {
+			continue
+		}
+
+		if loc[0] != start {
+			n.Parent.InsertBefore(text(s[start:loc[0]]), n)
+		}
+		span := &html.Node{
+			Type:     html.ElementNode,
+			Data:     "span",
+			DataAtom: atom.Span,
+			Attr:     []html.Attribute{{Key: "class", Val: c.NBSPClass}},
+		}
+		span.AppendChild(text(spaces))
+		n.Parent.InsertBefore(span, n)
+		start = loc[1]
+	}
+
+	if start != 0 && start == len(s) {
+		n.Parent.RemoveChild(n)
+		return
+	}
+	n.Data = s[start:]
+}
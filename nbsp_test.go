@@ -0,0 +1,35 @@
+package htmlcleaner
+
+import "testing"
+
+var nbspSpaceConfig = &Config{NBSP: NBSPSpace}
+
+var nbspCollapseConfig = &Config{NBSP: NBSPSpace, CollapseWhitespace: true}
+
+var nbspSpanConfig = (&Config{NBSP: NBSPSpan, NBSPClass: "indent", CollapseWhitespace: true, RemoveEmpty: true}).Elem("p", "pre")
+
+var testTableNBSP = []testTable{
+	{"Keep", "a&nbsp;&nbsp;b", "a\u00a0\u00a0b", nil},
+	{"Single", "10&nbsp;km", "10\u00a0km", nbspSpaceConfig},
+	{"Space", "&nbsp;&nbsp;&nbsp;a&nbsp; b  c", "   a  b  c", nbspSpaceConfig},
+	{"Collapse", "a&nbsp;&nbsp;&nbsp; b", "a b", nbspCollapseConfig},
+	{"Pre", "<pre>a&nbsp;&nbsp;b</pre>", "<pre>a\u00a0\u00a0b</pre>", nbspCollapseConfig.Clone().Elem("pre")},
+	{"Span", "<p>&nbsp;&nbsp;a&nbsp;&nbsp;&nbsp;b&nbsp;c</p>", `<p><span class="indent">  </span>a<span class="indent">   </span>b` + "\u00a0c</p>", nbspSpanConfig},
+	{"SpanEmpty", "<p>&nbsp;&nbsp;</p>", "", nbspSpanConfig},
+	{"SpanOnly", "<p>a&nbsp;&nbsp;</p>", `<p>a<span class="indent">  </span></p>`, nbspSpanConfig},
+	{"SpanPre", "<pre>&nbsp;&nbsp;a</pre>", "<pre>\u00a0\u00a0a</pre>", nbspSpanConfig},
+}
+
+func TestNBSP(t *testing.T) {
+	doTableTest(Clean, t, testTableNBSP)
+
+	// The added spans are allowed by Verify.
+	c := nbspSpanConfig.Clone()
+	c.Verify = func(err error) { t.Error(err) }
+	if output := Clean(c, "<p>a&nbsp;&nbsp;b</p>"); output != `<p>a<span class="indent">  </span>b</p>` {
+		t.Errorf("unexpected output %q", output)
+	}
+	if err := c.CheckOutput(`<span class="other">x</span>`); err == nil {
+		t.Error("expected other classes to be rejected")
+	}
+}
@@ -0,0 +1,161 @@
+package htmlcleaner
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// NestingAction is what happens to an element that is allowed, but not inside
+// one of its ancestors or not inside its required context. See ForbidInside
+// and RequireInside.
+type NestingAction int
+
+const (
+	// NestingEscape replaces the element with a textual version of its
+	// source code, like a disallowed element.
+	NestingEscape NestingAction = iota
+
+	// NestingUnwrap replaces the element with its cleaned contents.
+	NestingUnwrap
+)
+
+// ForbidInside disallows elements named by descendants from appearing
+// anywhere inside an element named ancestor. The receiver is returned to
+// allow call chaining.
+func (c *Config) ForbidInside(ancestor string, descendants ...string) *Config {
+	if c.forbid == nil {
+		c.forbid = make(map[string]map[string]struct{})
+	}
+
+	forbid := c.forbid[ancestor]
+	if forbid == nil {
+		forbid = make(map[string]struct{})
+		c.forbid[ancestor] = forbid
+	}
+
+	for _, d := range descendants {
+		forbid[d] = struct{}{}
+	}
+
+	return c
+}
+
+// ForbidInsideAtom disallows elements named by descendants from appearing
+// anywhere inside an element named ancestor. The receiver is returned to
+// allow call chaining.
+func (c *Config) ForbidInsideAtom(ancestor atom.Atom, descendants ...atom.Atom) *Config {
+	names := make([]string, len(descendants))
+	for i, d := range descendants {
+		names[i] = d.String()
+	}
+	return c.ForbidInside(ancestor.String(), names...)
+}
+
+// RequireInside allows an element named elem only inside an element named by
+// one of ancestors, such as <source> inside <video>, <audio>, or <picture>.
+// Elsewhere, it is handled according to NestingAction. Only enclosing
+// elements that are kept count. Calling RequireInside again for the same
+// element adds to the list. The receiver is returned to allow call
+// chaining.
+func (c *Config) RequireInside(elem string, ancestors ...string) *Config {
+	if c.requireInside == nil {
+		c.requireInside = make(map[string]map[string]struct{})
+	}
+
+	require := c.requireInside[elem]
+	if require == nil {
+		require = make(map[string]struct{})
+		c.requireInside[elem] = require
+	}
+
+	for _, a := range ancestors {
+		require[a] = struct{}{}
+	}
+
+	return c
+}
+
+// RequireInsideAtom allows an element named elem only inside an element
+// named by one of ancestors. The receiver is returned to allow call
+// chaining.
+func (c *Config) RequireInsideAtom(elem atom.Atom, ancestors ...atom.Atom) *Config {
+	names := make([]string, len(ancestors))
+	for i, a := range ancestors {
+		names[i] = a.String()
+	}
+	return c.RequireInside(elem.String(), names...)
+}
+
+var (
+	interactiveElements = []atom.Atom{atom.A, atom.Button, atom.Details, atom.Label, atom.Select, atom.Textarea, atom.Input, atom.Iframe}
+	headingElements     = []atom.Atom{atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6}
+	inlineContainers    = []atom.Atom{atom.A, atom.Abbr, atom.B, atom.Big, atom.Cite, atom.Code, atom.Del, atom.Em, atom.I, atom.Ins, atom.Kbd, atom.P, atom.Q, atom.S, atom.Small, atom.Span, atom.Strike, atom.Strong, atom.Sub, atom.Sup, atom.Tt, atom.U}
+)
+
+// StrictNesting forbids interactive elements such as <a> and <button> inside
+// other interactive elements, headings inside headings, and block elements
+// such as <div> and <blockquote> inside inline elements and paragraphs. The
+// receiver is returned to allow call chaining.
+func (c *Config) StrictNesting() *Config {
+	for _, a := range interactiveElements {
+		c.ForbidInsideAtom(a, interactiveElements...)
+	}
+	for _, a := range headingElements {
+		c.ForbidInsideAtom(a, headingElements...)
+	}
+
+	var blocks []atom.Atom
+	for a := range isBlockElement {
+		if a != 0 {
+			blocks = append(blocks, a)
+		}
+	}
+	for _, a := range inlineContainers {
+		c.ForbidInsideAtom(a, blocks...)
+	}
+
+	return c
+}
+
+// forbiddenHere reports whether n is forbidden inside any of its ancestors.
+func (c *cleaner) forbiddenHere(n *html.Node) bool {
+	if len(c.forbid) == 0 {
+		return false
+	}
+
+	for _, a := range c.stack {
+		if _, ok := c.forbid[a.Data][n.Data]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// outOfContext reports whether n is outside all of the elements required by
+// RequireInside.
+func (c *cleaner) outOfContext(n *html.Node) bool {
+	require, ok := c.requireInside[n.Data]
+	if !ok {
+		return false
+	}
+
+	for _, a := range c.stack {
+		if _, ok := require[a.Data]; ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nestingViolation returns why n may not appear here, or "" if it may.
+func (c *cleaner) nestingViolation(n *html.Node) string {
+	if c.forbiddenHere(n) {
+		return "forbidden inside an enclosing element"
+	}
+	if c.outOfContext(n) {
+		return "not inside an element required by RequireInside"
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package htmlcleaner
+
+import (
+	"testing"
+
+	"golang.org/x/net/html/atom"
+)
+
+var nestingConfig = DefaultConfig.Clone().
+	ElemAtom(atom.Div, atom.H1, atom.H2, atom.Span).
+	Elem("x-card").
+	StrictNesting().
+	ForbidInside("x-card", "x-card")
+
+var nestingUnwrapConfig = func() *Config {
+	c := nestingConfig.Clone()
+
+	c.NestingAction = NestingUnwrap
+
+	return c
+}()
+
+var testTableNesting = []testTable{
+	{"Allowed", `<div><b>a</b></div><h1>b</h1>`, `<div><b>a</b></div><h1>b</h1>`, nestingConfig},
+	{"BlockInInline", `<b>a<div>b</div></b>`, `<b>a&lt;div&gt;b&lt;/div&gt;</b>`, nestingConfig},
+	{"BlockInInlineUnwrap", `<b>a<div>b</div></b>`, `<b>ab</b>`, nestingUnwrapConfig},
+	{"Deep", `<span><i><blockquote>x</blockquote></i></span>`, `<span><i>x</i></span>`, nestingUnwrapConfig},
+	{"Custom", `<x-card><x-card>a</x-card></x-card>`, `<x-card>a</x-card>`, nestingUnwrapConfig},
+	{"Heading", `<h1>a<span><h2>b</h2></span></h1>`, `<h1>a<span>b</span></h1>`, nestingUnwrapConfig},
+}
+
+func TestNesting(t *testing.T) {
+	doTableTest(Clean, t, testTableNesting)
+}
+
+var contextConfig = DefaultConfig.Clone().
+	ElemAtom(atom.Source, atom.Ul, atom.Li).
+	ElemAttrAtom(atom.Source, atom.Src).
+	RequireInsideAtom(atom.Source, atom.Video, atom.Audio).
+	RequireInside("li", "ul", "ol")
+
+var testTableRequireInside = []testTable{
+	{"Inside", `<video controls><source src="/a.mp4"></video>`, `<video controls=""><source src="/a.mp4"/></video>`, contextConfig},
+	{"Outside", `<p><source src="/a.mp4"></p>`, `<p>&lt;source src=&#34;/a.mp4&#34;/&gt;</p>`, contextConfig},
+	{"Deep", `<ul><li><b><ul><li>a</li></ul></b></li></ul>`, `<ul><li><b><ul><li>a</li></ul></b></li></ul>`, contextConfig},
+	{"EscapedAncestor", `<ol><li>a</li></ol>`, `&lt;ol&gt;&lt;li&gt;a&lt;/li&gt;&lt;/ol&gt;`, contextConfig},
+	{"Unwrap", `<b><li>a</li></b>`, `<b>a</b>`, func() *Config {
+		c := contextConfig.Clone()
+		c.NestingAction = NestingUnwrap
+		return c
+	}()},
+}
+
+func TestRequireInside(t *testing.T) {
+	doTableTest(Clean, t, testTableRequireInside)
+}
@@ -0,0 +1,22 @@
+package htmlcleaner
+
+import (
+	"strings"
+)
+
+// newlineReplacer converts CRLF and CR line endings to LF and removes form
+// feeds and vertical tabs.
+var newlineReplacer = strings.NewReplacer(
+	"\r\n", "\n",
+	"\r", "\n",
+	"\f", "",
+	"\v", "",
+)
+
+// normalizeNewlines applies NormalizeNewlines to a fragment.
+func (c *Config) normalizeNewlines(fragment string) string {
+	if !c.NormalizeNewlines || !strings.ContainsAny(fragment, "\r\f\v") {
+		return fragment
+	}
+	return newlineReplacer.Replace(fragment)
+}
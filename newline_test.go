@@ -0,0 +1,30 @@
+package htmlcleaner
+
+import (
+	"testing"
+)
+
+var newlineConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.NormalizeNewlines = true
+
+	return c
+}()
+
+var testTableNewlines = []testTable{
+	{"CRLF", "a\r\nb\r\n", "a\nb\n", newlineConfig},
+	{"CR", "a\rb\r", "a\nb\n", newlineConfig},
+	{"Mixed", "a\r\n\rb\n\r\nc", "a\n\nb\n\nc", newlineConfig},
+	{"FormFeed", "<pre>a\f\vb</pre>", "<pre>ab</pre>", newlineConfig},
+	{"Attribute", "<b title=\"a\r\nb\">c</b>", "<b title=\"a\nb\">c</b>", newlineConfig},
+	{"Off", "a\r\nb\fc", "a\nb\fc", nil},
+}
+
+func TestNormalizeNewlines(t *testing.T) {
+	doTableTest(Clean, t, testTableNewlines)
+
+	if expected, actual := "a\n&lt;x&gt;\nb", Preprocess(newlineConfig, "a\r\n<x>\rb"); expected != actual {
+		t.Errorf("expected %q, actual %q", expected, actual)
+	}
+}
@@ -0,0 +1,74 @@
+package htmlcleaner
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	"golang.org/x/net/html"
+)
+
+// nodeIDAttr is the attribute set by NodeIDPrefix.
+const nodeIDAttr = "data-node-id"
+
+// hasNodeID reports whether NodeIDPrefix may add an id to elements of this
+// type.
+func hasNodeID(n *html.Node) bool {
+	return n.Type == html.ElementNode && n.DataAtom != 0 && isBlockElement[n.DataAtom]
+}
+
+// removeNodeIDs removes the data-node-id attribute from the elements in
+// nodes and the elements inside them.
+func removeNodeIDs(nodes []*html.Node) {
+	for _, n := range nodes {
+		Walk(n, func(n *html.Node) WalkAction {
+			if n.Type != html.ElementNode {
+				return WalkContinue
+			}
+
+			attrs := n.Attr[:0]
+			for _, a := range n.Attr {
+				if a.Namespace == "" && a.Key == nodeIDAttr {
+					continue
+				}
+				attrs = append(attrs, a)
+			}
+			n.Attr = attrs
+
+			return WalkContinue
+		})
+	}
+}
+
+// addNodeIDs sets data-node-id on each block element in nodes to a hash of
+// its canonical form, so that the id only changes when the element does.
+// Identical elements are numbered in document order.
+func addNodeIDs(prefix string, nodes []*html.Node) {
+	// Existing ids are removed first so that they are not part of the
+	// hash and cannot be used to claim another element's id.
+	removeNodeIDs(nodes)
+
+	var blocks []*html.Node
+	var ids []string
+	for _, n := range nodes {
+		Walk(n, func(n *html.Node) WalkAction {
+			if hasNodeID(n) {
+				h := fnv.New64a()
+				_, _ = h.Write([]byte(RenderCanonical(n)))
+				blocks = append(blocks, n)
+				ids = append(ids, fmt.Sprintf("%s%016x", prefix, h.Sum64()))
+			}
+			return WalkContinue
+		})
+	}
+
+	seen := make(map[string]int, len(ids))
+	for i, n := range blocks {
+		id := ids[i]
+		seen[id]++
+		if count := seen[id]; count > 1 {
+			id += "-" + strconv.Itoa(count)
+		}
+		n.Attr = append(n.Attr, html.Attribute{Key: nodeIDAttr, Val: id})
+	}
+}
@@ -0,0 +1,64 @@
+package htmlcleaner
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var nodeIDConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.WrapText = true
+	c.NodeIDPrefix = "n-"
+
+	return c
+}()
+
+var nodeIDPattern = regexp.MustCompile(`data-node-id="n-[0-9a-f]{16}(-[0-9]+)?"`)
+
+func nodeIDs(output string) []string {
+	return nodeIDPattern.FindAllString(output, -1)
+}
+
+func TestNodeIDs(t *testing.T) {
+	c := nodeIDConfig
+
+	before := Clean(c, `<p>a</p><blockquote><p>b</p></blockquote><p>a</p><b>c</b>`)
+	ids := nodeIDs(before)
+	if len(ids) != 5 {
+		t.Fatalf("expected 5 node ids in %q", before)
+	}
+	if expected := strings.TrimSuffix(ids[0], `"`) + `-2"`; ids[3] != expected {
+		t.Errorf("expected the second identical paragraph to be numbered: %q", before)
+	}
+
+	// Changing one element only changes the ids of it and its ancestors.
+	after := Clean(c, `<p>a</p><blockquote><p>B</p></blockquote><p>a</p><b>c</b>`)
+	changed := nodeIDs(after)
+	for i, same := range []bool{true, false, false, true, true} {
+		if (ids[i] == changed[i]) != same {
+			t.Errorf("node id %d: before %s, after %s", i, ids[i], changed[i])
+		}
+	}
+
+	if err := c.CheckOutput(before); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNodeIDsReplaced(t *testing.T) {
+	c := nodeIDConfig.Clone().GlobalAttr("data-node-id")
+
+	a := Clean(c, `<p data-node-id="n-forged">a</p>`)
+	b := Clean(c, `<p>a</p>`)
+	if a != b {
+		t.Errorf("expected existing ids to be replaced: %q != %q", a, b)
+	}
+}
+
+func TestNodeIDsCanonical(t *testing.T) {
+	if expected, actual := Canonical(nil, `<p>a</p>`), Canonical(nodeIDConfig, `<p>a</p>`); expected != actual {
+		t.Errorf("expected %q, actual %q", expected, actual)
+	}
+}
@@ -0,0 +1,67 @@
+package htmlcleaner
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// RenderNotification cleans a fragment of HTML using the specified Config, or
+// the FallbackConfig if it is nil, and converts it to plain text suitable for
+// chat messages and push notifications. Links are rendered as "text (url)" and
+// images as "[image: alt]". If maxLen is positive, the result is truncated to
+// at most maxLen characters, ending with an ellipsis if it was shortened.
+func RenderNotification(c *Config, fragment string, maxLen int) string {
+	w := newTextWriter(notificationElement)
+	w.nodes(cleanFragment(c, fragment))
+
+	return truncateText(w.String(), maxLen)
+}
+
+func notificationElement(w *textWriter, n *html.Node) {
+	switch n.DataAtom {
+	case atom.A:
+		href, _ := getAttr(n, "href")
+		text := textContent(n)
+		switch {
+		case href == "" || href == text:
+			w.children(n)
+		case text == "":
+			w.text(href)
+		default:
+			w.children(n)
+			w.text(" (" + href + ")")
+		}
+	case atom.Img:
+		if alt, _ := getAttr(n, "alt"); alt != "" {
+			w.text("[image: " + alt + "]")
+		} else {
+			w.text("[image]")
+		}
+	case atom.Li:
+		w.block(1)
+		w.write("• ")
+		w.children(n)
+		w.block(1)
+	case atom.Blockquote:
+		w.block(1)
+		w.indent("> ", func() { w.children(n) })
+		w.block(1)
+	default:
+		w.defaultElement(n)
+	}
+}
+
+// truncateText shortens s to at most maxLen runes, replacing the end with an
+// ellipsis if anything was removed. A non-positive maxLen means no limit.
+func truncateText(s string, maxLen int) string {
+	if maxLen <= 0 {
+		return s
+	}
+
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+
+	return string(runes[:maxLen-1]) + "…"
+}
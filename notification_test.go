@@ -0,0 +1,39 @@
+package htmlcleaner
+
+import (
+	"testing"
+)
+
+var testTableRenderNotification = []testTable{
+	{"Empty", ``, ``, nil},
+	{"Text", "  hello\n  world  ", `hello world`, nil},
+	{"Link", `see <a href="https://example.com/">this</a>!`, `see this (https://example.com/)!`, nil},
+	{"LinkSameText", `<a href="https://example.com/">https://example.com/</a>`, `https://example.com/`, nil},
+	{"LinkJavaScript", `<a href="javascript:x()">click</a>`, `click`, nil},
+	{"Image", `<img src="a.png" alt="a cat"> <img src="b.png">`, `[image: a cat] [image]`, nil},
+	{"Paragraphs", `<p>a</p><p>b</p>`, "a\nb", nil},
+	{"Quote", `<blockquote><p>a</p><p>b</p></blockquote>c`, "> a\n> b\nc", nil},
+	{"Escaped", `<script>x</script>`, `<script>x</script>`, nil},
+}
+
+func TestRenderNotification(t *testing.T) {
+	doTableTest(func(c *Config, s string) string {
+		return RenderNotification(c, s, 0)
+	}, t, testTableRenderNotification)
+}
+
+func TestRenderNotificationTruncate(t *testing.T) {
+	for _, tt := range []struct {
+		maxLen   int
+		expected string
+	}{
+		{0, "héllo wörld"},
+		{11, "héllo wörld"},
+		{10, "héllo wör…"},
+		{1, "…"},
+	} {
+		if actual := RenderNotification(nil, "<b>héllo</b> wörld", tt.maxLen); actual != tt.expected {
+			t.Errorf("%d: expected %q, actual %q", tt.maxLen, tt.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package htmlcleaner
+
+import (
+	"regexp"
+	"sync"
+
+	"golang.org/x/net/html/atom"
+)
+
+// Pipeline runs user input through Preprocess, an external renderer such as
+// a Markdown processor, and Clean, in that order.
+type Pipeline struct {
+	// The Config used for both Preprocess and Clean. If it is nil,
+	// FallbackConfig is used.
+	Config *Config
+
+	// Render converts the preprocessed input to HTML. If it is nil, the
+	// preprocessed input is passed directly to Clean.
+	Render func(string) string
+
+	// Generated holds additional elements and attributes that Render may
+	// produce on its own, such as the <span class="..."> elements of a
+	// syntax highlighter. They are allowed by Clean but not by Preprocess,
+	// so users cannot write them directly.
+	Generated *Config
+
+	// the Config used by Clean when Generated is set, which is built on
+	// first use and rebuilt only if Config or Generated is replaced
+	mu                    sync.Mutex
+	base, generated, both *Config
+}
+
+// Run processes a fragment of user input and returns clean HTML. It is safe
+// to call from multiple goroutines, but the Configs must not be modified
+// after the first call.
+func (p *Pipeline) Run(fragment string) string {
+	c := p.Config
+	if c == nil {
+		c = FallbackConfig()
+	}
+
+	fragment = Preprocess(c, fragment)
+	if p.Render != nil {
+		fragment = p.Render(fragment)
+	}
+
+	if p.Generated != nil {
+		c = p.union(c)
+	}
+
+	return Clean(c, fragment)
+}
+
+// union returns the union of c and Generated, reusing the one built by an
+// earlier call if neither has been replaced since.
+func (p *Pipeline) union(c *Config) *Config {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.both == nil || p.base != c || p.generated != p.Generated {
+		p.base, p.generated, p.both = c, p.Generated, union(c, p.Generated)
+	}
+
+	return p.both
+}
+
+// union returns a Config allowing everything either Config allows. The
+// exported fields are taken from base.
+func union(base, extra *Config) *Config {
+	c := base.Clone()
+
+	for e, attrs := range extra.elem {
+		c.ElemAtom(e)
+		for a, re := range attrs {
+			c.ElemAttrAtomMatch(e, a, unionMatch(c.elem[e], a, re))
+		}
+	}
+	for e, attrs := range extra.elemCustom {
+		c.Elem(e)
+		for a, re := range attrs {
+			c.ElemAttrMatch(e, a, unionCustomMatch(c.elemCustom[e], a, re))
+		}
+	}
+	for a := range extra.attr {
+		c.GlobalAttrAtom(a)
+	}
+	for a := range extra.attrCustom {
+		c.GlobalAttr(a)
+	}
+	for e := range extra.wrap {
+		c.WrapTextInsideAtom(e)
+	}
+	for e := range extra.wrapCustom {
+		c.WrapTextInside(e)
+	}
+
+	return c
+}
+
+func unionMatch(existing map[atom.Atom]*regexp.Regexp, a atom.Atom, re *regexp.Regexp) *regexp.Regexp {
+	old, ok := existing[a]
+	if !ok {
+		return re
+	}
+	return eitherMatch(old, re)
+}
+
+func unionCustomMatch(existing map[string]*regexp.Regexp, a string, re *regexp.Regexp) *regexp.Regexp {
+	old, ok := existing[a]
+	if !ok {
+		return re
+	}
+	return eitherMatch(old, re)
+}
+
+// eitherMatch returns a regular expression matching anything either one
+// matches. A nil regular expression matches everything.
+func eitherMatch(re1, re2 *regexp.Regexp) *regexp.Regexp {
+	if re1 == nil || re2 == nil {
+		return nil
+	}
+	if re1.String() == re2.String() {
+		return re1
+	}
+	return regexp.MustCompile(`(?:` + re1.String() + `)|(?:` + re2.String() + `)`)
+}
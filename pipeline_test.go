@@ -0,0 +1,42 @@
+package htmlcleaner_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/BenLubar/htmlcleaner"
+)
+
+func TestPipeline(t *testing.T) {
+	highlight := func(s string) string {
+		return strings.Replace(s, "<code>func</code>", `<code><span class="kw">func</span></code>`, -1)
+	}
+
+	p := &htmlcleaner.Pipeline{
+		Config: (&htmlcleaner.Config{}).Elem("code"),
+		Render: highlight,
+	}
+
+	input := `<code>func</code><span class="kw">x</span>`
+
+	if expected, actual := `<code>&lt;span class=&#34;kw&#34;&gt;func&lt;/span&gt;</code>&lt;span class=&#34;kw&#34;&gt;x&lt;/span&gt;`, p.Run(input); expected != actual {
+		t.Errorf("without Generated: expected %q, actual %q", expected, actual)
+	}
+
+	p.Generated = (&htmlcleaner.Config{}).ElemAttrMatch("span", "class", regexp.MustCompile(`\Akw\z`))
+
+	if expected, actual := `<code><span class="kw">func</span></code>&lt;span class=&#34;kw&#34;&gt;x&lt;/span&gt;`, p.Run(input); expected != actual {
+		t.Errorf("with Generated: expected %q, actual %q", expected, actual)
+	}
+}
+
+func BenchmarkPipelineGenerated(b *testing.B) {
+	p := &htmlcleaner.Pipeline{
+		Generated: (&htmlcleaner.Config{}).ElemAttrMatch("span", "class", regexp.MustCompile(`\Akw\z`)),
+	}
+
+	for i := 0; i < b.N; i++ {
+		p.Run(`<p><span class="kw">func</span></p>`)
+	}
+}
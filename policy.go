@@ -0,0 +1,34 @@
+package htmlcleaner
+
+import "context"
+
+// policyKey is the context key for the Config stored by WithPolicy.
+type policyKey struct{}
+
+// WithPolicy returns a copy of ctx that carries c, so that code deep in a
+// call stack, such as a template function, can clean content with the
+// policy of the current request without being passed the Config.
+func WithPolicy(ctx context.Context, c *Config) context.Context {
+	return context.WithValue(ctx, policyKey{}, c)
+}
+
+// PolicyFrom returns the Config carried by ctx, or nil if there is none.
+func PolicyFrom(ctx context.Context) *Config {
+	c, _ := ctx.Value(policyKey{}).(*Config)
+	return c
+}
+
+// policyOr returns c if it is not nil, and otherwise the Config carried by
+// ctx. Like a nil Config, a nil result means the fallback Config.
+func policyOr(ctx context.Context, c *Config) *Config {
+	if c != nil {
+		return c
+	}
+	return PolicyFrom(ctx)
+}
+
+// CleanContext is like Clean, but if c is nil, it uses the Config carried
+// by ctx (see WithPolicy) before using the FallbackConfig.
+func CleanContext(ctx context.Context, c *Config, fragment string) string {
+	return Clean(policyOr(ctx, c), fragment)
+}
@@ -0,0 +1,33 @@
+package htmlcleaner
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPolicyContext(t *testing.T) {
+	strict := (&Config{}).Elem("p")
+	ctx := WithPolicy(context.Background(), strict)
+
+	if PolicyFrom(ctx) != strict {
+		t.Error("PolicyFrom did not return the policy")
+	}
+	if PolicyFrom(context.Background()) != nil {
+		t.Error("expected no policy")
+	}
+
+	for _, tt := range []struct {
+		name     string
+		ctx      context.Context
+		c        *Config
+		expected string
+	}{
+		{"Context", ctx, nil, `<p>&lt;b&gt;a&lt;/b&gt;</p>`},
+		{"Explicit", ctx, DefaultConfig, `<p><b>a</b></p>`},
+		{"None", context.Background(), nil, `<p><b>a</b></p>`},
+	} {
+		if actual := CleanContext(tt.ctx, tt.c, `<p><b>a</b></p>`); actual != tt.expected {
+			t.Errorf("%s: expected %q, actual %q", tt.name, tt.expected, actual)
+		}
+	}
+}
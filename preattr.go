@@ -0,0 +1,47 @@
+package htmlcleaner
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// PreprocessAttrAction is what Preprocess does with allowed start tags that
+// have disallowed attributes. See Config.PreprocessAttrs.
+type PreprocessAttrAction int
+
+const (
+	// PreprocessAttrKeep leaves the tag as it is, as Preprocess only
+	// checks tag names.
+	PreprocessAttrKeep PreprocessAttrAction = iota
+
+	// PreprocessAttrEscape escapes the whole tag, like a disallowed tag.
+	PreprocessAttrEscape
+
+	// PreprocessAttrRemove removes the disallowed attributes from the
+	// tag.
+	PreprocessAttrRemove
+)
+
+// preprocessAttrs applies PreprocessAttrs to the raw HTML of an allowed
+// start tag, and reports whether the tag was escaped.
+func (c *Config) preprocessAttrs(token html.Token, raw string) (string, bool) {
+	elem := atom.Lookup([]byte(token.Data))
+
+	var kept []html.Attribute
+	for _, attr := range token.Attr {
+		check := attr
+		if attr.Namespace == "" && c.checkAttr(elem, token.Data, &check) == attrOK {
+			kept = append(kept, attr)
+		}
+	}
+	if len(kept) == len(token.Attr) {
+		return raw, false
+	}
+
+	if c.PreprocessAttrs == PreprocessAttrEscape {
+		return html.EscapeString(raw), true
+	}
+
+	token.Attr = kept
+	return token.String(), false
+}
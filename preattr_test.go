@@ -0,0 +1,41 @@
+package htmlcleaner
+
+import "testing"
+
+var preprocessAttrKeepConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.PreprocessAttrs = PreprocessAttrKeep
+
+	return c
+}()
+
+var preprocessAttrEscapeConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.PreprocessAttrs = PreprocessAttrEscape
+
+	return c
+}()
+
+var preprocessAttrRemoveConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.PreprocessAttrs = PreprocessAttrRemove
+
+	return c
+}()
+
+var testTablePreprocessAttrs = []testTable{
+	{"Keep", `<b onclick="x()">a</b>`, `<b onclick="x()">a</b>`, preprocessAttrKeepConfig},
+	{"Escape", `<b onclick="x()">a</b>`, `&lt;b onclick=&#34;x()&#34;&gt;a</b>`, preprocessAttrEscapeConfig},
+	{"Remove", `<b onclick="x()" title="t">a</b>`, `<b title="t">a</b>`, preprocessAttrRemoveConfig},
+	{"RemoveURL", `<a href="javascript:x()">a</a>`, `<a>a</a>`, preprocessAttrRemoveConfig},
+	{"Allowed", `<a href="/x" title='t'>a</a>`, `<a href="/x" title='t'>a</a>`, preprocessAttrEscapeConfig},
+	{"SelfClosing", `<br onclick="x()"/>`, `<br/>`, preprocessAttrRemoveConfig.Clone().Elem("br")},
+	{"Disallowed", `<marquee onclick="x()">a</marquee>`, `&lt;marquee onclick=&#34;x()&#34;&gt;a&lt;/marquee&gt;`, preprocessAttrRemoveConfig},
+}
+
+func TestPreprocessAttrs(t *testing.T) {
+	doTableTest(Preprocess, t, testTablePreprocessAttrs)
+}
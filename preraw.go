@@ -0,0 +1,38 @@
+package htmlcleaner
+
+import "golang.org/x/net/html"
+
+// RawTextAction is what Preprocess does with the contents of disallowed
+// raw text elements. See Config.RawTextContent.
+type RawTextAction int
+
+const (
+	// RawTextKeep keeps the contents as they are, so that they are
+	// parsed as HTML after the escaped start tag.
+	RawTextKeep RawTextAction = iota
+
+	// RawTextEscape escapes the contents, so that they are shown as
+	// they were written.
+	RawTextEscape
+
+	// RawTextDrop removes the contents.
+	RawTextDrop
+)
+
+// tokenizerRawText reports whether the tokenizer reads the contents of the
+// named element as a single text token.
+func tokenizerRawText(name string) bool {
+	return rawTextElements[name] || name == "textarea" || name == "title"
+}
+
+// rawTextContent applies RawTextContent to the contents of a disallowed raw
+// text element.
+func (c *Config) rawTextContent(raw string) string {
+	switch c.RawTextContent {
+	case RawTextEscape:
+		return html.EscapeString(raw)
+	case RawTextDrop:
+		return ""
+	}
+	return raw
+}
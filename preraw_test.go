@@ -0,0 +1,41 @@
+package htmlcleaner
+
+import "testing"
+
+var rawTextKeepConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.RawTextContent = RawTextKeep
+
+	return c
+}()
+
+var rawTextEscapeConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.RawTextContent = RawTextEscape
+
+	return c
+}()
+
+var rawTextDropConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.RawTextContent = RawTextDrop
+
+	return c
+}()
+
+var testTableRawTextContent = []testTable{
+	{"Keep", `<script><b>x</b></script>`, `&lt;script&gt;<b>x</b>&lt;/script&gt;`, rawTextKeepConfig},
+	{"Escape", `<script><b>x</b></script>`, `&lt;script&gt;&lt;b&gt;x&lt;/b&gt;&lt;/script&gt;`, rawTextEscapeConfig},
+	{"Drop", `<style>*{}</style>a`, `&lt;style&gt;&lt;/style&gt;a`, rawTextDropConfig},
+	{"Textarea", `<textarea># *x*</textarea>`, `&lt;textarea&gt;&lt;/textarea&gt;`, rawTextDropConfig},
+	{"Unwrapped", `<script>x</script>y`, `y`, rawTextDropConfig.Clone().Unwrap("script")},
+	{"Allowed", `<style><b></style>`, `<style><b></style>`, rawTextEscapeConfig.Clone().Elem("style")},
+	{"NotRawText", `<marquee><b>x</b></marquee>`, `&lt;marquee&gt;<b>x</b>&lt;/marquee&gt;`, rawTextDropConfig},
+}
+
+func TestRawTextContent(t *testing.T) {
+	doTableTest(Preprocess, t, testTableRawTextContent)
+}
@@ -0,0 +1,81 @@
+package htmlcleaner
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// PrescanVerdict is the result of Prescan.
+type PrescanVerdict int
+
+const (
+	// PrescanPlainText means the fragment has no tags, comments, or
+	// character references. See IsPlainText.
+	PrescanPlainText PrescanVerdict = iota
+
+	// PrescanAllowed means every tag, attribute, and comment in the
+	// fragment is allowed by the Config. Clean may still fix nesting and
+	// apply options such as WrapText.
+	PrescanAllowed
+
+	// PrescanDisallowed means the fragment contains something that Clean
+	// may escape or remove, such as an element that is not allowed, an
+	// attribute that does not match its rule, or a URL rejected by
+	// ValidateURL.
+	PrescanDisallowed
+)
+
+// IsPlainText reports whether a fragment contains no markup at all: no
+// tags, comments, or character references. Such a fragment means the same
+// thing as HTML and as text, so escaping it with html.EscapeString is
+// enough to display it safely, and it can skip a sanitization queue.
+func IsPlainText(fragment string) bool {
+	return !strings.ContainsAny(fragment, "<&")
+}
+
+// Prescan tokenizes a fragment without building a tree and reports whether
+// Clean, using the specified Config or the FallbackConfig if it is nil, may
+// need to escape or remove anything. It is much cheaper than Clean, so it
+// can be used to route content, such as by cleaning PrescanDisallowed
+// fragments in a separate queue. Elements allowed only by AllowSelector
+// are reported as PrescanDisallowed, since their ancestors are not known.
+func Prescan(c *Config, fragment string) PrescanVerdict {
+	if c == nil {
+		c = FallbackConfig()
+	}
+
+	if IsPlainText(fragment) {
+		return PrescanPlainText
+	}
+
+	t := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		switch tok := t.Next(); tok {
+		case html.ErrorToken:
+			// As in Preprocess, the only possible error is io.EOF.
+			expectError(t.Err(), io.EOF)
+			return PrescanAllowed
+		case html.TextToken:
+			// Text is always allowed.
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			token := t.Token()
+			if !c.AllowsElem(token.Data) {
+				return PrescanDisallowed
+			}
+			for _, a := range token.Attr {
+				if a.Namespace != "" || !c.AllowsAttr(token.Data, a.Key, a.Val) {
+					return PrescanDisallowed
+				}
+			}
+		case html.CommentToken:
+			raw := string(t.Raw())
+			if c.EscapeComments || !strings.HasPrefix(raw, "<!--") || !strings.HasSuffix(raw, "-->") {
+				return PrescanDisallowed
+			}
+		default:
+			return PrescanDisallowed
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrescan(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Input    string
+		Expected PrescanVerdict
+		Config   *Config
+	}{
+		{"Empty", ``, PrescanPlainText, nil},
+		{"Text", `hello, "world" > 1`, PrescanPlainText, nil},
+		{"CharRef", `a &amp; b`, PrescanAllowed, nil},
+		{"Allowed", `<b title="x">a</b><a href="https://example.com/">b</a>`, PrescanAllowed, nil},
+		{"Comment", `a<!-- b -->c`, PrescanAllowed, nil},
+		{"EscapedComment", `a<!-- b -->c`, PrescanDisallowed, escapeCommentsConfig},
+		{"Element", `<b>a</b><script>b</script>`, PrescanDisallowed, nil},
+		{"Attribute", `<b onclick="x()">a</b>`, PrescanDisallowed, nil},
+		{"URL", `<a href="javascript:x()">a</a>`, PrescanDisallowed, nil},
+		{"EndTag", `a</div>`, PrescanDisallowed, nil},
+		{"Doctype", `<!DOCTYPE html>a`, PrescanDisallowed, nil},
+		{"LessThan", `a < b`, PrescanAllowed, nil},
+	}
+
+	for _, tt := range tests {
+		if actual := Prescan(tt.Config, tt.Input); actual != tt.Expected {
+			t.Errorf("%s: expected %d, actual %d", tt.Name, tt.Expected, actual)
+		}
+	}
+}
+
+var escapeCommentsConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.EscapeComments = true
+
+	return c
+}()
+
+// The benchmarks show the cost of each check compared to Clean, for
+// deciding which fragments to route around the cleaner.
+
+var benchmarkFragments = map[string]string{
+	"PlainText":  strings.Repeat("Hello, world! This is a plain text comment. ", 50),
+	"Allowed":    strings.Repeat(`<p>Hello, <b>world</b>! <a href="https://example.com/">link</a></p>`, 30),
+	"Disallowed": strings.Repeat(`<p>Hello, <b>world</b>!</p>`, 30) + `<script>x()</script>`,
+}
+
+func BenchmarkIsPlainText(b *testing.B) {
+	for name, fragment := range benchmarkFragments {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(fragment)))
+			for i := 0; i < b.N; i++ {
+				IsPlainText(fragment)
+			}
+		})
+	}
+}
+
+func BenchmarkPrescan(b *testing.B) {
+	for name, fragment := range benchmarkFragments {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(fragment)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				Prescan(nil, fragment)
+			}
+		})
+	}
+}
+
+func BenchmarkClean(b *testing.B) {
+	for name, fragment := range benchmarkFragments {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(fragment)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				Clean(nil, fragment)
+			}
+		})
+	}
+}
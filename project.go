@@ -0,0 +1,83 @@
+package htmlcleaner
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Project reduces a fragment that was already cleaned with c to the subset
+// of it allowed by subset, such as inline elements only for a notification
+// preview. It is cheaper than cleaning the fragment again: only the element
+// and attribute rules are applied. An element or attribute is kept only if
+// both c and subset allow it, so the output never allows more than either.
+// Other elements are replaced by their contents, with block elements
+// separated by spaces, and comments are removed. A nil Config means the
+// FallbackConfig.
+func Project(c *Config, fragment string, subset *Config) string {
+	if c == nil {
+		c = FallbackConfig()
+	}
+	if subset == nil {
+		subset = FallbackConfig()
+	}
+
+	// the input limits still apply, even though the fragment should
+	// already be clean
+	cl := newCleaner(c)
+	fragment, _ = cl.limitInput(fragment)
+	limited, ok := cl.limitCharRefs(cl.normalizeNewlines(fragment))
+	if !ok {
+		return html.EscapeString(fragment)
+	}
+
+	parent := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range cl.parse(limited) {
+		parent.AppendChild(n)
+	}
+
+	projectChildren(c, subset, parent)
+
+	var nodes []*html.Node
+	for n := parent.FirstChild; n != nil; n = n.NextSibling {
+		nodes = append(nodes, n)
+	}
+	return Render(nodes...)
+}
+
+func projectChildren(c, subset *Config, parent *html.Node) {
+	for n := parent.FirstChild; n != nil; {
+		next := n.NextSibling
+
+		switch n.Type {
+		case html.TextNode:
+		case html.ElementNode:
+			projectChildren(c, subset, n)
+
+			if c.AllowsElem(n.Data) && subset.AllowsElem(n.Data) {
+				attrs := n.Attr[:0]
+				for _, a := range n.Attr {
+					a1, a2 := a, a
+					if c.checkAttr(n.DataAtom, n.Data, &a1) == attrOK && subset.checkAttr(n.DataAtom, n.Data, &a2) == attrOK && a1 == a && a2 == a {
+						attrs = append(attrs, a)
+					}
+				}
+				n.Attr = attrs
+				break
+			}
+
+			for n.FirstChild != nil {
+				child := n.FirstChild
+				n.RemoveChild(child)
+				parent.InsertBefore(child, n)
+			}
+			if n.DataAtom == atom.Br || (n.DataAtom != 0 && isBlockElement[n.DataAtom]) {
+				parent.InsertBefore(text(" "), n)
+			}
+			parent.RemoveChild(n)
+		default:
+			parent.RemoveChild(n)
+		}
+
+		n = next
+	}
+}
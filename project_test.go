@@ -0,0 +1,24 @@
+package htmlcleaner
+
+import "testing"
+
+var previewConfig = (&Config{}).ElemAttr("a", "href").Elem("b", "i", "em", "strong")
+
+var testTableProject = []testTable{
+	{"Inline", `<p>Hello, <b>world</b>!</p><p>Second <a href="https://example.com/" title="t">link</a>.</p>`, `Hello, <b>world</b>! Second <a href="https://example.com/">link</a>. `, previewConfig},
+	{"Image", `<p>a<img src="/a.png" alt="x">b</p>`, `ab `, previewConfig},
+	{"Comment", `<b>a<!-- x --></b>`, `<b>a</b>`, previewConfig},
+	{"NotWidened", `<code>a</code><a href="javascript:x()">b</a><a onclick="x()">c</a>`, `<code>a</code><a>b</a><a>c</a>`, (&Config{}).ElemAttr("a", "href", "onclick").Elem("code")},
+	{"Escaped", `&lt;b&gt;a&lt;/b&gt;`, `&lt;b&gt;a&lt;/b&gt;`, previewConfig},
+}
+
+func TestProject(t *testing.T) {
+	doTableTest(func(subset *Config, fragment string) string {
+		return Project(DefaultConfig, Clean(DefaultConfig, fragment), subset)
+	}, t, testTableProject)
+
+	// an element allowed by the subset but not by the original Config
+	if actual := Project(previewConfig, `<code>a</code>`, DefaultConfig); actual != `a` {
+		t.Errorf("expected %q, actual %q", `a`, actual)
+	}
+}
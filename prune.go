@@ -0,0 +1,106 @@
+package htmlcleaner
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var defaultKeepEmpty = map[atom.Atom]struct{}{
+	atom.Area:     {},
+	atom.Audio:    {},
+	atom.Br:       {},
+	atom.Col:      {},
+	atom.Embed:    {},
+	atom.Hr:       {},
+	atom.Iframe:   {},
+	atom.Img:      {},
+	atom.Input:    {},
+	atom.Source:   {},
+	atom.Td:       {},
+	atom.Textarea: {},
+	atom.Th:       {},
+	atom.Track:    {},
+	atom.Video:    {},
+	atom.Wbr:      {},
+}
+
+// KeepEmpty marks elements as meaningful even when they have no content, so
+// RemoveEmpty does not remove them. Void elements such as <br> and <img>,
+// table cells, and media elements are always kept. The receiver is returned
+// to allow call chaining.
+func (c *Config) KeepEmpty(names ...string) *Config {
+	if c.keepEmptyCustom == nil {
+		c.keepEmptyCustom = make(map[string]struct{})
+	}
+
+	for _, name := range names {
+		if a := atom.Lookup([]byte(name)); a != 0 {
+			c.KeepEmptyAtom(a)
+			continue
+		}
+
+		c.keepEmptyCustom[name] = struct{}{}
+	}
+
+	return c
+}
+
+// KeepEmptyAtom marks elements as meaningful even when they have no content,
+// so RemoveEmpty does not remove them. The receiver is returned to allow call
+// chaining.
+func (c *Config) KeepEmptyAtom(elem ...atom.Atom) *Config {
+	if c.keepEmpty == nil {
+		c.keepEmpty = make(map[atom.Atom]struct{})
+	}
+
+	for _, a := range elem {
+		c.keepEmpty[a] = struct{}{}
+	}
+
+	return c
+}
+
+func (c *Config) keptEmpty(n *html.Node) bool {
+	if n.DataAtom != 0 {
+		_, ok := defaultKeepEmpty[n.DataAtom]
+		if !ok {
+			_, ok = c.keepEmpty[n.DataAtom]
+		}
+		return ok
+	}
+	_, ok := c.keepEmptyCustom[n.Data]
+	return ok
+}
+
+func removeEmpty(c *Config, nodes []*html.Node) []*html.Node {
+	kept := nodes[:0]
+	for _, n := range nodes {
+		if !removeEmptyNode(c, n) {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// removeEmptyNode removes empty descendants of n and reports whether n itself
+// is empty and should be removed.
+func removeEmptyNode(c *Config, n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+
+	empty := true
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+		if removeEmptyNode(c, child) {
+			n.RemoveChild(child)
+		} else if child.Type != html.TextNode || strings.TrimSpace(child.Data) != "" {
+			empty = false
+		}
+		child = next
+	}
+
+	return empty && !c.keptEmpty(n)
+}
@@ -0,0 +1,110 @@
+package htmlcleaner
+
+import (
+	"net/url"
+	"strings"
+)
+
+// TrackingParams is a list of common query parameters used to track clicks,
+// for use with StripQuery.
+var TrackingParams = []string{"utm_*", "fbclid", "gclid", "dclid", "msclkid", "mc_cid", "mc_eid", "igshid", "yclid", "_hsenc", "_hsmi"}
+
+// StripQuery removes the specified query parameters from the URLs in
+// allowed attributes. A parameter ending in "*", such as "utm_*", matches
+// any parameter with that prefix. The receiver is returned to allow call
+// chaining.
+func (c *Config) StripQuery(params ...string) *Config {
+	if c.stripQuery == nil {
+		c.stripQuery = make(map[string]struct{})
+	}
+
+	for _, p := range params {
+		c.stripQuery[p] = struct{}{}
+	}
+
+	return c
+}
+
+// StripQueryHost overrides StripQuery for URLs whose host matches host,
+// using the same patterns as AllowHosts. The specified parameters are
+// removed from those URLs instead of the ones given to StripQuery; if there
+// are none, their queries are left alone. The receiver is returned to allow
+// call chaining.
+func (c *Config) StripQueryHost(host string, params ...string) *Config {
+	if c.stripQueryHosts == nil {
+		c.stripQueryHosts = make(map[string]map[string]struct{})
+	}
+
+	host = strings.ToLower(host)
+	set := c.stripQueryHosts[host]
+	if set == nil {
+		set = make(map[string]struct{})
+		c.stripQueryHosts[host] = set
+	}
+
+	for _, p := range params {
+		set[p] = struct{}{}
+	}
+
+	return c
+}
+
+// queryParamsFor returns the query parameters to remove from u.
+func (c *Config) queryParamsFor(u *url.URL) map[string]struct{} {
+	if len(c.stripQueryHosts) == 0 || u.Host == "" {
+		return c.stripQuery
+	}
+
+	host := urlHost(u)
+	if params, ok := c.stripQueryHosts[host]; ok {
+		return params
+	}
+	for i := strings.IndexByte(host, '.'); i != -1; i = strings.IndexByte(host, '.') {
+		host = host[i+1:]
+		if params, ok := c.stripQueryHosts["*."+host]; ok {
+			return params
+		}
+	}
+	return c.stripQuery
+}
+
+func matchParam(params map[string]struct{}, key string) bool {
+	if _, ok := params[key]; ok {
+		return true
+	}
+	for p := range params {
+		if strings.HasSuffix(p, "*") && strings.HasPrefix(key, p[:len(p)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// stripQueryParams removes the parameters configured by StripQuery from u,
+// keeping the order and encoding of the rest.
+func (c *Config) stripQueryParams(u *url.URL) {
+	if u.RawQuery == "" || (len(c.stripQuery) == 0 && len(c.stripQueryHosts) == 0) {
+		return
+	}
+
+	params := c.queryParamsFor(u)
+	if len(params) == 0 {
+		return
+	}
+
+	parts := strings.Split(u.RawQuery, "&")
+	kept := parts[:0]
+	for _, part := range parts {
+		key := part
+		if i := strings.IndexByte(key, '='); i != -1 {
+			key = key[:i]
+		}
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		if !matchParam(params, key) {
+			kept = append(kept, part)
+		}
+	}
+	u.RawQuery = strings.Join(kept, "&")
+}
@@ -0,0 +1,23 @@
+package htmlcleaner
+
+import "testing"
+
+var queryConfig = DefaultConfig.Clone().
+	StripQuery(TrackingParams...).
+	StripQueryHost("*.youtube.com").
+	StripQueryHost("shop.example", "ref")
+
+var testTableStripQuery = []testTable{
+	{"Tracking", `<a href="https://example.com/?utm_source=x&amp;id=1&amp;fbclid=y">x</a>`, `<a href="https://example.com/?id=1">x</a>`, queryConfig},
+	{"OnlyTracking", `<a href="https://example.com/?utm_source=x&amp;utm_medium=y">x</a>`, `<a href="https://example.com/">x</a>`, queryConfig},
+	{"Escaped", `<a href="https://example.com/?%75tm_source=x&amp;q=a%20b">x</a>`, `<a href="https://example.com/?q=a%20b">x</a>`, queryConfig},
+	{"Image", `<img src="https://example.com/a.png?gclid=1&amp;v=2">`, `<img src="https://example.com/a.png?v=2"/>`, queryConfig},
+	{"HostKeepsAll", `<a href="https://www.youtube.com/watch?v=x&amp;utm_source=y">x</a>`, `<a href="https://www.youtube.com/watch?v=x&amp;utm_source=y">x</a>`, queryConfig},
+	{"HostOverride", `<a href="https://shop.example/?ref=a&amp;utm_source=y">x</a>`, `<a href="https://shop.example/?utm_source=y">x</a>`, queryConfig},
+	{"Relative", `<a href="/a?fbclid=1&amp;b=2">x</a>`, `<a href="/a?b=2">x</a>`, queryConfig},
+	{"Unconfigured", `<a href="https://example.com/?utm_source=x">x</a>`, `<a href="https://example.com/?utm_source=x">x</a>`, nil},
+}
+
+func TestStripQuery(t *testing.T) {
+	doTableTest(Clean, t, testTableStripQuery)
+}
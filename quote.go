@@ -0,0 +1,49 @@
+package htmlcleaner
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// quoteTooDeep reports whether n is a <blockquote> nested inside at least
+// MaxQuoteDepth other <blockquote> elements, or exactly MaxQuoteDepth if
+// QuoteSummary is set, since the quotes inside a summarized quote are kept.
+func (c *cleaner) quoteTooDeep(n *html.Node) bool {
+	if c.MaxQuoteDepth <= 0 || n.DataAtom != atom.Blockquote {
+		return false
+	}
+
+	depth := 0
+	for _, a := range c.stack {
+		if a.DataAtom == atom.Blockquote {
+			depth++
+		}
+	}
+
+	if c.QuoteSummary != "" {
+		return depth == c.MaxQuoteDepth
+	}
+	return depth >= c.MaxQuoteDepth
+}
+
+// collapseQuote replaces a <blockquote> that is nested too deeply with
+// QuotePlaceholder, or with its contents if there is no placeholder.
+func collapseQuote(c *cleaner, n *html.Node) *html.Node {
+	if c.QuotePlaceholder != "" {
+		return text(c.QuotePlaceholder)
+	}
+	return unwrapNode(c, n)
+}
+
+// summarizeQuote wraps a cleaned <blockquote> that is nested too deeply in a
+// closed <details> element with QuoteSummary as its <summary>.
+func summarizeQuote(c *cleaner, n *html.Node) *html.Node {
+	summary := &html.Node{Type: html.ElementNode, Data: "summary", DataAtom: atom.Summary}
+	summary.AppendChild(text(c.QuoteSummary))
+
+	details := &html.Node{Type: html.ElementNode, Data: "details", DataAtom: atom.Details}
+	details.AppendChild(summary)
+	details.AppendChild(n)
+
+	return details
+}
@@ -0,0 +1,212 @@
+package htmlcleaner
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// StoredFragment is a fragment of HTML stored by an application, identified
+// by an application-defined ID.
+type StoredFragment struct {
+	ID   string
+	HTML string
+}
+
+// FragmentSource iterates over stored fragments for a Resanitizer.
+type FragmentSource interface {
+	// Next returns the next fragment, or io.EOF if there are no more.
+	Next() (StoredFragment, error)
+}
+
+// ResanitizeProgress describes how much work a Resanitizer has done.
+type ResanitizeProgress struct {
+	// The number of fragments that have been cleaned and, if they
+	// changed, stored.
+	Processed int
+
+	// The number of fragments whose cleaned HTML was different.
+	Changed int
+
+	// The ID of the last fragment such that it and every fragment before
+	// it have been processed. Passing a source that starts after this ID
+	// to a new Resanitizer resumes the work.
+	LastID string
+}
+
+// Resanitizer cleans a large number of stored fragments again, for example
+// after a change to the Config. Fragments are cleaned concurrently, but
+// progress is reported in the order the source returned them.
+type Resanitizer struct {
+	// The Config used to clean fragments. If it is nil, the Config
+	// carried by the context passed to Run is used (see WithPolicy), or
+	// the FallbackConfig if there is none.
+	Config *Config
+
+	// Source returns the fragments to clean. It is only called from one
+	// goroutine at a time.
+	Source FragmentSource
+
+	// Store is called for each fragment whose cleaned HTML differs from
+	// the stored HTML. It may be called from multiple goroutines at once.
+	Store func(id, cleaned string) error
+
+	// The number of fragments cleaned at the same time. If it is not
+	// positive, 1 is used.
+	Workers int
+
+	// The maximum number of fragments read from Source per second. If it
+	// is not positive, there is no limit. Rates over one per nanosecond
+	// are treated as one per nanosecond.
+	Rate int
+
+	// If set, Checkpoint is called from the goroutine running Run after
+	// every CheckpointEvery fragments and once more at the end.
+	Checkpoint      func(ResanitizeProgress) error
+	CheckpointEvery int
+}
+
+type resanitizeResult struct {
+	seq     int
+	id      string
+	changed bool
+	err     error
+}
+
+// Run processes every fragment from Source, stopping early if ctx is
+// canceled or an error occurs. It returns the progress made so far.
+func (r *Resanitizer) Run(ctx context.Context) (ResanitizeProgress, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := r.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	type job struct {
+		seq  int
+		frag StoredFragment
+	}
+	jobs := make(chan job)
+	results := make(chan resanitizeResult)
+
+	var sourceErr error
+	go func() {
+		defer close(jobs)
+
+		var tick <-chan time.Time
+		if r.Rate > 0 {
+			interval := time.Second / time.Duration(r.Rate)
+			if interval <= 0 {
+				// a rate over one per nanosecond
+				interval = 1
+			}
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		for seq := 0; ; seq++ {
+			if tick != nil {
+				select {
+				case <-tick:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			frag, err := r.Source.Next()
+			if err != nil {
+				if err != io.EOF {
+					sourceErr = err
+				}
+				return
+			}
+
+			select {
+			case jobs <- job{seq, frag}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				res := resanitizeResult{seq: j.seq, id: j.frag.ID}
+				if cleaned := CleanContext(ctx, r.Config, j.frag.HTML); cleaned != j.frag.HTML {
+					res.changed = true
+					if r.Store != nil {
+						res.err = r.Store(j.frag.ID, cleaned)
+					}
+				}
+				results <- res
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var progress ResanitizeProgress
+	var firstErr error
+	pending := make(map[int]resanitizeResult)
+	sinceCheckpoint := 0
+
+	for res := range results {
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = res.err
+				cancel()
+			}
+			continue
+		}
+		if firstErr != nil {
+			continue
+		}
+
+		pending[res.seq] = res
+		for {
+			next, ok := pending[progress.Processed]
+			if !ok {
+				break
+			}
+			delete(pending, progress.Processed)
+
+			progress.Processed++
+			progress.LastID = next.id
+			if next.changed {
+				progress.Changed++
+			}
+
+			sinceCheckpoint++
+			if r.Checkpoint != nil && r.CheckpointEvery > 0 && sinceCheckpoint >= r.CheckpointEvery {
+				sinceCheckpoint = 0
+				if err := r.Checkpoint(progress); err != nil {
+					firstErr = err
+					cancel()
+					break
+				}
+			}
+		}
+	}
+
+	if firstErr == nil {
+		firstErr = sourceErr
+	}
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr == nil && r.Checkpoint != nil {
+		firstErr = r.Checkpoint(progress)
+	}
+
+	return progress, firstErr
+}
@@ -0,0 +1,122 @@
+package htmlcleaner_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/BenLubar/htmlcleaner"
+)
+
+type sliceSource struct {
+	frags []htmlcleaner.StoredFragment
+	err   error
+}
+
+func (s *sliceSource) Next() (htmlcleaner.StoredFragment, error) {
+	if len(s.frags) == 0 {
+		if s.err != nil {
+			return htmlcleaner.StoredFragment{}, s.err
+		}
+		return htmlcleaner.StoredFragment{}, io.EOF
+	}
+	f := s.frags[0]
+	s.frags = s.frags[1:]
+	return f, nil
+}
+
+func testFragments(n int) []htmlcleaner.StoredFragment {
+	frags := make([]htmlcleaner.StoredFragment, n)
+	for i := range frags {
+		frags[i].ID = strconv.Itoa(i)
+		if i%3 == 0 {
+			frags[i].HTML = "<script>" + frags[i].ID + "</script>"
+		} else {
+			frags[i].HTML = "<b>" + frags[i].ID + "</b>"
+		}
+	}
+	return frags
+}
+
+func TestResanitizer(t *testing.T) {
+	var mu sync.Mutex
+	stored := make(map[string]string)
+	var checkpoints []htmlcleaner.ResanitizeProgress
+
+	r := &htmlcleaner.Resanitizer{
+		Source: &sliceSource{frags: testFragments(100)},
+		Store: func(id, cleaned string) error {
+			mu.Lock()
+			stored[id] = cleaned
+			mu.Unlock()
+			return nil
+		},
+		Workers:         4,
+		CheckpointEvery: 25,
+		Checkpoint: func(p htmlcleaner.ResanitizeProgress) error {
+			checkpoints = append(checkpoints, p)
+			return nil
+		},
+	}
+
+	progress, err := r.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (htmlcleaner.ResanitizeProgress{Processed: 100, Changed: 34, LastID: "99"}); progress != expected {
+		t.Errorf("expected progress %+v, actual %+v", expected, progress)
+	}
+	if len(stored) != 34 || stored["3"] != "&lt;script&gt;3&lt;/script&gt;" {
+		t.Errorf("unexpected stored fragments: %v", stored)
+	}
+	if len(checkpoints) != 5 {
+		t.Fatalf("expected 5 checkpoints, got %d", len(checkpoints))
+	}
+	for i, p := range checkpoints[:4] {
+		if p.Processed != 25*(i+1) || p.LastID != strconv.Itoa(25*(i+1)-1) {
+			t.Errorf("unexpected checkpoint %d: %+v", i, p)
+		}
+	}
+}
+
+func TestResanitizerErrors(t *testing.T) {
+	sourceErr := errors.New("source failed")
+	r := &htmlcleaner.Resanitizer{
+		Source: &sliceSource{frags: testFragments(10), err: sourceErr},
+	}
+	if progress, err := r.Run(context.Background()); err != sourceErr || progress.Processed != 10 {
+		t.Errorf("expected source error after 10 fragments, got %v after %d", err, progress.Processed)
+	}
+
+	storeErr := errors.New("store failed")
+	r = &htmlcleaner.Resanitizer{
+		Source:  &sliceSource{frags: testFragments(10)},
+		Store:   func(string, string) error { return storeErr },
+		Workers: 2,
+	}
+	if _, err := r.Run(context.Background()); err != storeErr {
+		t.Errorf("expected store error, got %v", err)
+	}
+
+	r = &htmlcleaner.Resanitizer{
+		Source: &sliceSource{frags: testFragments(10)},
+		Rate:   2000000000,
+	}
+	if progress, err := r.Run(context.Background()); err != nil || progress.Processed != 10 {
+		t.Errorf("expected 10 fragments with a high rate, got %v after %d", err, progress.Processed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r = &htmlcleaner.Resanitizer{
+		Source: &sliceSource{frags: testFragments(10)},
+		Rate:   1,
+	}
+	if _, err := r.Run(ctx); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
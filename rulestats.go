@@ -0,0 +1,175 @@
+package htmlcleaner
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// RuleStats counts how often the rules of a Config are applied, so that
+// unused rules can be pruned and commonly rejected content can be found. Set
+// Config.RuleStats to start counting. RuleStats is safe for concurrent use,
+// and its zero value is ready to use.
+type RuleStats struct {
+	mu     sync.Mutex
+	counts RuleCounts
+}
+
+// ElemAttr identifies an attribute on a specific element.
+type ElemAttr struct {
+	Elem string
+	Attr string
+}
+
+// RuleCounts is a snapshot of the counts in a RuleStats.
+type RuleCounts struct {
+	// Allowed elements and attributes that were kept.
+	Elems map[string]int
+	Attrs map[ElemAttr]int
+
+	// Elements that were escaped, either because they are not allowed or
+	// because of a nesting rule.
+	EscapedElems map[string]int
+
+	// Attributes that were removed because they are not allowed or are
+	// unsafe (see AllowUnsafe), or because their value did not match the
+	// regular expression given to ElemAttrMatch.
+	RemovedAttrs map[ElemAttr]int
+	NoMatchAttrs map[ElemAttr]int
+
+	// Attributes that were removed because they were longer than
+	// MaxAttrLength or MaxURLLength.
+	TooLongAttrs map[ElemAttr]int
+
+	// The number of times the regular expression given to ElemAttrMatch
+	// was evaluated for each attribute, whether or not it matched. The
+	// largest counts show which expressions are worth simplifying.
+	RegexpEvals map[ElemAttr]int
+
+	// The scheme of each URL in an allowed attribute, and of each URL
+	// that was rejected by ValidateURL or CheckURL. Relative URLs have an
+	// empty scheme.
+	Schemes      map[string]int
+	RejectedURLs map[string]int
+}
+
+// Counts returns a copy of the current counts.
+func (s *RuleStats) Counts() RuleCounts {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var counts RuleCounts
+	counts.add(&s.counts)
+	return counts
+}
+
+// Reset sets all counts to zero.
+func (s *RuleStats) Reset() {
+	s.mu.Lock()
+	s.counts = RuleCounts{}
+	s.mu.Unlock()
+}
+
+func (s *RuleStats) add(counts *RuleCounts) {
+	if s == nil || counts == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.counts.add(counts)
+	s.mu.Unlock()
+}
+
+func (c *RuleCounts) add(other *RuleCounts) {
+	addCounts(&c.Elems, other.Elems)
+	addAttrCounts(&c.Attrs, other.Attrs)
+	addCounts(&c.EscapedElems, other.EscapedElems)
+	addAttrCounts(&c.RemovedAttrs, other.RemovedAttrs)
+	addAttrCounts(&c.NoMatchAttrs, other.NoMatchAttrs)
+	addAttrCounts(&c.TooLongAttrs, other.TooLongAttrs)
+	addAttrCounts(&c.RegexpEvals, other.RegexpEvals)
+	addCounts(&c.Schemes, other.Schemes)
+	addCounts(&c.RejectedURLs, other.RejectedURLs)
+}
+
+func addCounts(dst *map[string]int, src map[string]int) {
+	for k, n := range src {
+		count(dst, k, n)
+	}
+}
+
+func addAttrCounts(dst *map[ElemAttr]int, src map[ElemAttr]int) {
+	for k, n := range src {
+		countAttr(dst, k, n)
+	}
+}
+
+func count(m *map[string]int, key string, n int) {
+	if *m == nil {
+		*m = make(map[string]int)
+	}
+	(*m)[key] += n
+}
+
+func countAttr(m *map[ElemAttr]int, key ElemAttr, n int) {
+	if *m == nil {
+		*m = make(map[ElemAttr]int)
+	}
+	(*m)[key] += n
+}
+
+// urlScheme returns the lowercase scheme of a URL, even if it cannot
+// otherwise be parsed.
+func urlScheme(val string) string {
+	if u, err := url.Parse(val); err == nil {
+		return strings.ToLower(u.Scheme)
+	}
+	if i := strings.IndexByte(val, ':'); i > 0 {
+		return strings.ToLower(strings.TrimSpace(val[:i]))
+	}
+	return ""
+}
+
+// recordElem counts an allowed element that was kept.
+func (c *cleaner) recordElem(n *html.Node) {
+	if c.counts != nil {
+		count(&c.counts.Elems, n.Data, 1)
+	}
+}
+
+// recordAttr counts the verdict for an attribute on an allowed element.
+func (c *cleaner) recordAttr(n *html.Node, attr *html.Attribute, v attrVerdict) {
+	evaluated := (v == attrOK || v == attrNoMatch) && c.attrMatch(n.DataAtom, n.Data, attr.Key) != nil
+	if evaluated {
+		c.regexpEvals++
+	}
+
+	if c.counts == nil {
+		return
+	}
+
+	key := ElemAttr{Elem: n.Data, Attr: attr.Key}
+	if evaluated {
+		countAttr(&c.counts.RegexpEvals, key, 1)
+	}
+	switch v {
+	case attrOK:
+		countAttr(&c.counts.Attrs, key, 1)
+	case attrNotAllowed, attrUnsafe:
+		countAttr(&c.counts.RemovedAttrs, key, 1)
+		return
+	case attrNoMatch:
+		countAttr(&c.counts.NoMatchAttrs, key, 1)
+	case attrTooLong:
+		countAttr(&c.counts.TooLongAttrs, key, 1)
+	case attrBadURL:
+		count(&c.counts.RejectedURLs, urlScheme(attr.Val), 1)
+	}
+
+	if isURLAttr(atom.Lookup([]byte(attr.Key))) {
+		count(&c.counts.Schemes, urlScheme(attr.Val), 1)
+	}
+}
@@ -0,0 +1,39 @@
+package htmlcleaner
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestRuleStats(t *testing.T) {
+	c := DefaultConfig.Clone().ElemAttrMatch("span", "class", regexp.MustCompile(`\Ax\z`))
+	c.RuleStats = &RuleStats{}
+
+	Clean(c, `<a href="https://example.com/" onclick="x()">a</a><a href="javascript:x()">b</a><script>c</script>`)
+	Clean(c, `<span class="y">d</span><img src="/e.png" title="e">`)
+	CleanNode(c, Parse(`<a href="HTTP://example.com/">f</a>`)[0])
+
+	expected := RuleCounts{
+		Elems: map[string]int{"a": 3, "span": 1, "img": 1},
+		Attrs: map[ElemAttr]int{
+			{"a", "href"}:    2,
+			{"img", "src"}:   1,
+			{"img", "title"}: 1,
+		},
+		EscapedElems: map[string]int{"script": 1},
+		RemovedAttrs: map[ElemAttr]int{{"a", "onclick"}: 1},
+		NoMatchAttrs: map[ElemAttr]int{{"span", "class"}: 1},
+		RegexpEvals:  map[ElemAttr]int{{"span", "class"}: 1},
+		Schemes:      map[string]int{"https": 1, "javascript": 1, "": 1, "http": 1},
+		RejectedURLs: map[string]int{"javascript": 1},
+	}
+	if counts := c.RuleStats.Counts(); !reflect.DeepEqual(counts, expected) {
+		t.Errorf("expected %+v\nactual   %+v", expected, counts)
+	}
+
+	c.RuleStats.Reset()
+	if counts := c.RuleStats.Counts(); !reflect.DeepEqual(counts, RuleCounts{}) {
+		t.Errorf("expected no counts after Reset, got %+v", counts)
+	}
+}
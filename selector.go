@@ -0,0 +1,118 @@
+package htmlcleaner
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// selector is a parsed selector, as described by FindAll.
+type selector struct {
+	// the element name matched by the last compound selector, or "" for
+	// any element
+	name string
+
+	// the compound selectors, outermost first
+	compounds []func(*html.Node) bool
+}
+
+func parseSelector(s string) selector {
+	var sel selector
+	for _, part := range strings.Fields(s) {
+		name, match := compileCompound(part)
+		sel.name = name
+		sel.compounds = append(sel.compounds, match)
+	}
+	return sel
+}
+
+// match reports whether n matches the selector, given its ancestors,
+// outermost first.
+func (sel selector) match(n *html.Node, ancestors []*html.Node) bool {
+	if len(sel.compounds) == 0 || !sel.compounds[len(sel.compounds)-1](n) {
+		return false
+	}
+
+	i := len(ancestors) - 1
+	for j := len(sel.compounds) - 2; j >= 0; j-- {
+		for i >= 0 && !sel.compounds[j](ancestors[i]) {
+			i--
+		}
+		if i < 0 {
+			return false
+		}
+		i--
+	}
+	return true
+}
+
+type selectorRule struct {
+	sel   selector
+	attrs []string
+}
+
+// AllowSelector allows the elements matching a selector, as described by
+// FindAll, with the specified attributes, such as <span class="math"> with
+// AllowSelector("span.math", "class") when other <span> elements are not
+// allowed. Ancestors in the selector are matched against the enclosing
+// elements that were kept. The attributes are still subject to ValidateURL
+// and the other attribute checks. The receiver is returned to allow call
+// chaining.
+func (c *Config) AllowSelector(selector string, attrs ...string) *Config {
+	c.allowSelectors = append(c.allowSelectors, selectorRule{sel: parseSelector(selector), attrs: attrs})
+	return c
+}
+
+// DenySelector disallows the elements matching a selector, as described by
+// FindAll, such as "a img" for images inside links, even if they are
+// otherwise allowed. They are escaped or unwrapped like other disallowed
+// elements. DenySelector takes precedence over AllowSelector. The receiver
+// is returned to allow call chaining.
+func (c *Config) DenySelector(selector string) *Config {
+	c.denySelectors = append(c.denySelectors, parseSelector(selector))
+	return c
+}
+
+// selectorAllows applies AllowSelector and DenySelector to n, which is
+// allowed by the element rules if allowed is true. It returns whether n is
+// allowed and the extra attributes allowed on it.
+func (c *cleaner) selectorAllows(n *html.Node, allowed bool) (bool, []string) {
+	if len(c.allowSelectors) == 0 && len(c.denySelectors) == 0 {
+		return allowed, nil
+	}
+
+	for _, sel := range c.denySelectors {
+		if sel.match(n, c.stack) {
+			return false, nil
+		}
+	}
+
+	var attrs []string
+	for _, rule := range c.allowSelectors {
+		if rule.sel.match(n, c.stack) {
+			allowed = true
+			attrs = append(attrs, rule.attrs...)
+		}
+	}
+	return allowed, attrs
+}
+
+// mayAllowSelector reports whether an AllowSelector rule could match an
+// element with the specified name, for Preprocess.
+func (c *Config) mayAllowSelector(name string) bool {
+	for _, rule := range c.allowSelectors {
+		if rule.sel.name == "" || rule.sel.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package htmlcleaner
+
+import "testing"
+
+var selectorConfig = DefaultConfig.Clone().
+	AllowSelector("span.math", "class").
+	AllowSelector("blockquote cite[title]", "title", "href").
+	DenySelector("a img").
+	DenySelector("b.hidden").
+	Unwrap("b")
+
+var testTableSelectors = []testTable{
+	{"AllowClass", `<span class="math">x</span>`, `<span class="math">x</span>`, selectorConfig},
+	{"AllowClassAmongOthers", `<span class="big math">x</span>`, `<span class="big math">x</span>`, selectorConfig},
+	{"OtherSpan", `<span class="other">x</span>`, `&lt;span class=&#34;other&#34;&gt;x&lt;/span&gt;`, selectorConfig},
+	{"Descendant", `<blockquote><p><cite title="t" href="/" lang="en">x</cite></p></blockquote>`, `<blockquote><p><cite title="t" href="/">x</cite></p></blockquote>`, selectorConfig},
+	{"URLChecked", `<blockquote><cite title="t" href="javascript:x()">x</cite></blockquote>`, `<blockquote><cite title="t">x</cite></blockquote>`, selectorConfig},
+	{"NotDescendant", `<p><cite title="t" href="/">x</cite></p>`, `<p><cite title="t">x</cite></p>`, selectorConfig},
+	{"Deny", `<a href="/"><img src="/a.png"></a><img src="/b.png">`, `<a href="/">&lt;img src=&#34;/a.png&#34;/&gt;</a><img src="/b.png"/>`, selectorConfig},
+	{"DenyUnwrap", `<b class="hidden">x</b><b>y</b>`, `x<b>y</b>`, selectorConfig},
+	{"Preprocess", `<span class="math">x</span><span>y</span>`, `<span class="math">x</span>&lt;span&gt;y&lt;/span&gt;`, selectorConfig},
+}
+
+func TestSelectors(t *testing.T) {
+	doTableTest(func(c *Config, s string) string {
+		return Clean(c, Preprocess(c, s))
+	}, t, testTableSelectors)
+}
@@ -0,0 +1,148 @@
+package htmlcleaner
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// selfCheckCanaries are the inputs cleaned by SelfCheck. Each contains the
+// word "canary", which must survive cleaning in some form.
+var selfCheckCanaries = []struct {
+	name, input string
+}{
+	{"script", `<script>alert(1)</script>canary`},
+	{"event-handler", `<img src="x" onerror="alert(1)">canary`},
+	{"javascript-url", `<a href="javascript:alert(1)">canary</a>`},
+	{"obfuscated-url", "<a href=\" jav&#x09;ascript:alert(1)\">canary</a>"},
+	{"svg", `<svg><script>alert(1)</script></svg>canary`},
+	{"style", `<style>*{display:none}</style>canary`},
+	{"iframe", `<iframe src="https://example.com/"></iframe>canary`},
+	{"attribute-breakout", `<a title="&quot;><script>alert(1)</script>">canary</a>`},
+	{"comment", `<!--><script>alert(1)</script>-->canary`},
+	{"noscript", `<noscript><p title="</noscript><img src=x onerror=alert(1)>">canary`},
+	{"unclosed", `<b><i><u>canary`},
+	{"plain-text", `canary & <friends>`},
+}
+
+// SelfCheckResult is the outcome of one canary input in a SelfCheckReport.
+type SelfCheckResult struct {
+	Name     string
+	Passed   bool
+	Problem  string
+	Duration time.Duration
+}
+
+// SelfCheckReport is the result of SelfCheck.
+type SelfCheckReport struct {
+	Passed   bool
+	Results  []SelfCheckResult
+	Duration time.Duration
+}
+
+// SelfCheck cleans a set of known attack and canary inputs with c and checks
+// that each output is allowed by c (see CheckOutput), has no scripts or
+// event handlers unless AllowUnsafe is set, and still contains the canary
+// text. It is meant to be run by a health check, so that a broken Config
+// is noticed as soon as it is deployed. Tracer, RuleStats, Verify, and
+// Shadow are not used.
+func SelfCheck(c *Config) SelfCheckReport {
+	if c == nil {
+		c = FallbackConfig()
+	}
+	c = c.Clone()
+	c.Tracer, c.RuleStats, c.Verify, c.Shadow = nil, nil, nil, nil
+
+	report := SelfCheckReport{Passed: true}
+	start := time.Now()
+	for _, canary := range selfCheckCanaries {
+		result := selfCheckOne(c, canary.name, canary.input)
+		report.Passed = report.Passed && result.Passed
+		report.Results = append(report.Results, result)
+	}
+	report.Duration = time.Since(start)
+
+	return report
+}
+
+func selfCheckOne(c *Config, name, input string) (result SelfCheckResult) {
+	result.Name = name
+
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+		if r := recover(); r != nil {
+			result.Passed, result.Problem = false, fmt.Sprint("panic: ", r)
+		}
+	}()
+
+	output := Clean(c, input)
+
+	if err := CheckInvariants(output, c); err != nil {
+		result.Problem = err.Error()
+		return
+	}
+	if !strings.Contains(output, "canary") {
+		result.Problem = fmt.Sprintf("canary text lost: %q", output)
+		return
+	}
+
+	result.Passed = true
+	return
+}
+
+// unsafeContent describes the first script element or unsafe attribute in
+// n, or returns "" if there is none.
+func unsafeContent(n *html.Node) string {
+	var problem string
+	Walk(n, func(n *html.Node) WalkAction {
+		if n.Type != html.ElementNode {
+			return WalkContinue
+		}
+		if n.Data == "script" {
+			problem = "script element in output"
+			return WalkStop
+		}
+		for i := range n.Attr {
+			if unsafeAttr(&n.Attr[i]) {
+				problem = fmt.Sprintf("unsafe attribute %s=%q in output", n.Attr[i].Key, n.Attr[i].Val)
+				return WalkStop
+			}
+		}
+		return WalkContinue
+	})
+	return problem
+}
+
+// SelfCheckHandler returns an http.Handler that runs SelfCheck with c for
+// each request and writes the results as plain text, one line per canary,
+// with status 200 if they all passed and 503 otherwise.
+func SelfCheckHandler(c *Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := SelfCheck(c)
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Passed {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		for _, result := range report.Results {
+			status := "ok"
+			if !result.Passed {
+				status = "FAIL"
+			}
+			fmt.Fprintf(w, "%s %s %v", status, result.Name, result.Duration)
+			if result.Problem != "" {
+				fmt.Fprintf(w, " %s", result.Problem)
+			}
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "total %v\n", report.Duration)
+	})
+}
@@ -0,0 +1,57 @@
+package htmlcleaner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// unsafeSelfCheckConfig allows <script> without setting AllowUnsafe.
+var unsafeSelfCheckConfig = func() *Config {
+	c := DefaultConfig.Clone().Trusted()
+
+	c.AllowUnsafe = false
+
+	return c
+}()
+
+func TestSelfCheck(t *testing.T) {
+	for _, c := range []*Config{nil, DefaultConfig.Clone().SetWrapText(true), {}, EmailConfig, FeedConfig} {
+		report := SelfCheck(c)
+		if !report.Passed {
+			for _, result := range report.Results {
+				if !result.Passed {
+					t.Errorf("%s: %s", result.Name, result.Problem)
+				}
+			}
+		}
+		if len(report.Results) != len(selfCheckCanaries) {
+			t.Errorf("expected %d results, got %d", len(selfCheckCanaries), len(report.Results))
+		}
+	}
+
+	if report := SelfCheck(unsafeSelfCheckConfig); report.Passed {
+		t.Error("expected a failure")
+	}
+}
+
+func TestSelfCheckHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	SelfCheckHandler(nil).ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "ok script ") {
+		t.Errorf("unexpected response %d %q", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	SelfCheckHandler(DefaultConfig.Clone().Trusted()).ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("unexpected status %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	SelfCheckHandler(unsafeSelfCheckConfig).ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "FAIL script") {
+		t.Errorf("unexpected response %d %q", w.Code, w.Body.String())
+	}
+}
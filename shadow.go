@@ -0,0 +1,33 @@
+package htmlcleaner
+
+// shadow cleans fragment with the Shadow Config and passes both outputs to
+// ShadowReport if they differ. Configs that are already being cleaned with,
+// such as two Configs that are each other's Shadow, are not used again.
+func (cl *cleaner) shadow(fragment, output string) {
+	c := cl.Config
+	if c.Shadow == nil || c.ShadowReport == nil || c.Shadow == c || cl.shadowed[c.Shadow] || cl.explain != nil {
+		return
+	}
+
+	if candidate, ok := cl.shadowClean(fragment); ok && candidate != output {
+		c.ShadowReport(fragment, output, candidate)
+	}
+}
+
+// shadowClean cleans fragment with the Shadow Config. It reports false if
+// cleaning panicked, so that a broken candidate cannot affect the output.
+func (cl *cleaner) shadowClean(fragment string) (candidate string, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			candidate, ok = "", false
+		}
+	}()
+
+	sc := newCleaner(cl.Shadow)
+	sc.shadowed = map[*Config]bool{cl.Config: true}
+	for shadowed := range cl.shadowed {
+		sc.shadowed[shadowed] = true
+	}
+
+	return sc.clean(fragment, nil), true
+}
@@ -0,0 +1,65 @@
+package htmlcleaner
+
+import (
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestShadow(t *testing.T) {
+	type report struct{ fragment, output, candidate string }
+	var reports []report
+
+	c := DefaultConfig.Clone()
+	c.Shadow = (&Config{}).Elem("b", "p")
+	c.ShadowReport = func(fragment, output, candidate string) {
+		reports = append(reports, report{fragment, output, candidate})
+	}
+
+	if out := Clean(c, `<b>a</b>`); out != `<b>a</b>` {
+		t.Errorf("unexpected output %q", out)
+	}
+	if out := Clean(c, `<i>a</i>`); out != `<i>a</i>` {
+		t.Errorf("unexpected output %q", out)
+	}
+
+	expected := []report{{`<i>a</i>`, `<i>a</i>`, `&lt;i&gt;a&lt;/i&gt;`}}
+	if len(reports) != len(expected) || reports[0] != expected[0] {
+		t.Errorf("expected %q, got %q", expected, reports)
+	}
+}
+
+func TestShadowCycle(t *testing.T) {
+	var reports int
+	report := func(fragment, output, candidate string) {
+		reports++
+	}
+
+	a := (&Config{}).Elem("b")
+	b := (&Config{}).Elem("i")
+	a.Shadow, a.ShadowReport = b, report
+	b.Shadow, b.ShadowReport = a, report
+
+	if out := Clean(a, `<b>x</b>`); out != `<b>x</b>` {
+		t.Errorf("unexpected output %q", out)
+	}
+	if reports != 1 {
+		t.Errorf("expected 1 report, got %d", reports)
+	}
+}
+
+func TestShadowPanic(t *testing.T) {
+	c := DefaultConfig.Clone()
+	c.FailureAction = FailureEscape
+	c.Shadow = DefaultConfig.Clone()
+	c.Shadow.TextFilter = func(parent *html.Node, text string) string {
+		panic("broken candidate")
+	}
+	c.ShadowReport = func(fragment, output, candidate string) {
+		t.Errorf("unexpected report %q", candidate)
+	}
+
+	if out := Clean(c, `<b>a</b>`); out != `<b>a</b>` {
+		t.Errorf("unexpected output %q", out)
+	}
+}
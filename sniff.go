@@ -0,0 +1,64 @@
+package htmlcleaner
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ContentTypeBase64Markup is returned by SniffContent for input that is
+// base64-encoded HTML or XML.
+const ContentTypeBase64Markup = "application/x-base64-markup"
+
+var (
+	sniffSVG    = regexp.MustCompile(`(?i)<!doctype\s+svg|<svg[^>]*\sxmlns\s*=\s*["']?http://www\.w3\.org/2000/svg`)
+	sniffBase64 = regexp.MustCompile(`\A[A-Za-z0-9+/]+={0,2}\z`)
+)
+
+// SniffContent reports whether a fragment that is supposed to be HTML looks
+// like some other kind of document. It returns an empty string for HTML and
+// plain text, and otherwise a media type such as "application/pdf",
+// "image/svg+xml", "image/png", or ContentTypeBase64Markup.
+func SniffContent(fragment string) string {
+	trimmed := strings.TrimSpace(fragment)
+
+	if strings.HasPrefix(trimmed, "%PDF-") {
+		return "application/pdf"
+	}
+
+	if sniffSVG.MatchString(fragment) {
+		return "image/svg+xml"
+	}
+
+	if ct := http.DetectContentType([]byte(fragment)); !strings.HasPrefix(ct, "text/") {
+		return ct
+	}
+
+	if compact := strings.Join(strings.Fields(trimmed), ""); len(compact) >= 16 && len(compact)%4 == 0 && sniffBase64.MatchString(compact) {
+		if b, err := base64.StdEncoding.DecodeString(compact); err == nil {
+			b = bytes.TrimSpace(b)
+			if bytes.HasPrefix(b, []byte("<")) && bytes.Contains(b, []byte(">")) {
+				return ContentTypeBase64Markup
+			}
+		}
+	}
+
+	return ""
+}
+
+// sniff calls SniffHandler if it is set and the fragment does not look like
+// HTML.
+func (c *Config) sniff(fragment string) (string, bool) {
+	if c.SniffHandler == nil {
+		return "", false
+	}
+
+	contentType := SniffContent(fragment)
+	if contentType == "" {
+		return "", false
+	}
+
+	return c.SniffHandler(contentType, fragment), true
+}
@@ -0,0 +1,53 @@
+package htmlcleaner
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestSniffContent(t *testing.T) {
+	for _, tt := range []struct {
+		name, input, expected string
+	}{
+		{"Empty", ``, ``},
+		{"Text", `hello world`, ``},
+		{"HTML", `<p>hello <b>world</b></p>`, ``},
+		{"InlineSVG", `<svg><circle r="1"/></svg>`, ``},
+		{"PDF", "\n%PDF-1.7\n%\xe2\xe3\xcf\xd3", `application/pdf`},
+		{"SVG", `<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"><script>x()</script></svg>`, `image/svg+xml`},
+		{"PNG", "\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR", `image/png`},
+		{"Base64", base64.StdEncoding.EncodeToString([]byte(`<script>alert(1)</script>`)), ContentTypeBase64Markup},
+		{"Base64Text", base64.StdEncoding.EncodeToString([]byte(`just some words here`)), ``},
+		{"Word", `Supercalifragilisticexpialidocious`, ``},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := SniffContent(tt.input); actual != tt.expected {
+				t.Errorf("expected %q, actual %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+var sniffConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.SniffHandler = func(contentType, fragment string) string {
+		return "[" + contentType + " removed]"
+	}
+
+	return c
+}()
+
+var testTableSniff = []testTable{
+	{"HTML", `<b>a</b>`, `<b>a</b>`, sniffConfig},
+	{"PDF", `%PDF-1.4 <b>a</b>`, `[application/pdf removed]`, sniffConfig},
+	{"NoHandler", `%PDF-1.4 <b>a</b>`, `%PDF-1.4 <b>a</b>`, nil},
+}
+
+func TestSniffClean(t *testing.T) {
+	doTableTest(Clean, t, testTableSniff)
+}
+
+func TestSniffPreprocess(t *testing.T) {
+	doTableTest(Preprocess, t, testTableSniff)
+}
@@ -0,0 +1,62 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// WordsPerMinute is the reading speed used to estimate ContentStats.ReadingTime.
+const WordsPerMinute = 200
+
+// ContentStats holds statistics about a cleaned fragment of HTML.
+type ContentStats struct {
+	// The number of whitespace-separated words of text.
+	Words int
+
+	// The number of characters of text, after collapsing whitespace.
+	Characters int
+
+	// The number of images with a src attribute.
+	Images int
+
+	// The number of links with an href attribute.
+	Links int
+
+	// The estimated time to read the text, at WordsPerMinute, truncated to
+	// a whole number of seconds.
+	ReadingTime time.Duration
+}
+
+// Stats cleans a fragment of HTML using the specified Config, or the
+// FallbackConfig if it is nil, and returns statistics about the result.
+func Stats(c *Config, fragment string) ContentStats {
+	var stats ContentStats
+
+	nodes := cleanFragment(c, fragment)
+
+	w := newTextWriter(func(w *textWriter, n *html.Node) {
+		switch n.DataAtom {
+		case atom.Img:
+			if _, ok := getAttr(n, "src"); ok {
+				stats.Images++
+			}
+		case atom.A:
+			if _, ok := getAttr(n, "href"); ok {
+				stats.Links++
+			}
+		}
+		w.defaultElement(n)
+	})
+	w.nodes(nodes)
+
+	text := w.String()
+	stats.Words = len(strings.Fields(text))
+	stats.Characters = utf8.RuneCountInString(text)
+	stats.ReadingTime = time.Duration(stats.Words*60/WordsPerMinute) * time.Second
+
+	return stats
+}
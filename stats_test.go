@@ -0,0 +1,30 @@
+package htmlcleaner_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BenLubar/htmlcleaner"
+)
+
+func TestStats(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		input    string
+		expected htmlcleaner.ContentStats
+	}{
+		{"Empty", ``, htmlcleaner.ContentStats{}},
+		{"Text", "<p>héllo  world</p>\n<p>again</p>", htmlcleaner.ContentStats{Words: 3, Characters: 17}},
+		{"Media", `<a href="/x">x</a> <a>y</a> <img src="a.png"> <img>`, htmlcleaner.ContentStats{Words: 2, Characters: 3, Links: 1, Images: 1}},
+		{"Escaped", `<script>a b</script>`, htmlcleaner.ContentStats{Words: 2, Characters: 20}},
+		{"Long", strings.Repeat("word ", 500), htmlcleaner.ContentStats{Words: 500, Characters: 2499, ReadingTime: 150 * time.Second}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := htmlcleaner.Stats(nil, tt.input); actual != tt.expected {
+				t.Errorf("expected %+v", tt.expected)
+				t.Errorf("actual   %+v", actual)
+			}
+		})
+	}
+}
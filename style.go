@@ -0,0 +1,73 @@
+package htmlcleaner
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var cssURL = regexp.MustCompile(`(?i)url\(\s*(?:"([^"]*)"|'([^']*)'|([^)"'\s]*))\s*\)`)
+
+// splitStyle splits an inline style into declarations at semicolons that are
+// not inside quotes or parentheses.
+func splitStyle(style string) []string {
+	var decls []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(style); i++ {
+		switch ch := style[i]; {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')' && depth > 0:
+			depth--
+		case ch == ';' && depth == 0:
+			decls = append(decls, style[start:i])
+			start = i + 1
+		}
+	}
+	return append(decls, style[start:])
+}
+
+// cleanStyleURLs passes each url() in an inline style, such as the value of
+// background-image, to check, and removes the declarations containing URLs
+// that cannot be parsed or are rejected. Declarations with backslashes are
+// removed as well, as CSS escapes could hide a URL. Accepted URLs are written
+// in a normalized form. It returns the cleaned style, which may be empty.
+func cleanStyleURLs(style string, check func(*url.URL) bool) string {
+	var kept []string
+	for _, decl := range splitStyle(style) {
+		if strings.TrimSpace(decl) == "" {
+			continue
+		}
+		if strings.Contains(decl, `\`) {
+			continue
+		}
+
+		ok := true
+		decl = cssURL.ReplaceAllStringFunc(decl, func(m string) string {
+			sub := cssURL.FindStringSubmatch(m)
+			u, err := url.Parse(sub[1] + sub[2] + sub[3])
+			if err != nil || (check != nil && !check(u)) {
+				ok = false
+				return m
+			}
+			return `url("` + strings.NewReplacer(`"`, "%22", "\n", "%0A").Replace(u.String()) + `")`
+		})
+		if !ok {
+			continue
+		}
+		if lower := strings.ToLower(decl); strings.Contains(lower, "url(") && !cssURL.MatchString(decl) {
+			// a url() that cssURL could not parse
+			continue
+		}
+
+		kept = append(kept, strings.TrimSpace(decl))
+	}
+	return strings.Join(kept, "; ")
+}
@@ -0,0 +1,45 @@
+package htmlcleaner
+
+import (
+	"net/url"
+	"testing"
+)
+
+var styleConfig = DefaultConfig.Clone().ElemAttr("div", "style").SetValidateURL(func(u *url.URL) bool {
+	if !SafeURLScheme(u) || u.Scheme == "data" {
+		return false
+	}
+	if u.Host == "example.com" {
+		u.Host = "proxy.example.com"
+	}
+	return true
+})
+
+var testTableStyle = []testTable{
+	{"NoURL", `<div style="color: red; margin: 0">a</div>`, `<div style="color: red; margin: 0">a</div>`, styleConfig},
+	{"Rewritten", `<div style="background-image: url(https://example.com/a.png)">a</div>`, `<div style="background-image: url(&#34;https://proxy.example.com/a.png&#34;)">a</div>`, styleConfig},
+	{"Quoted", `<div style='background: url("/a b.png") no-repeat'>a</div>`, `<div style="background: url(&#34;/a%20b.png&#34;) no-repeat">a</div>`, styleConfig},
+	{"Rejected", `<div style="color: red; background-image: url('javascript:alert(1)')">a</div>`, `<div style="color: red">a</div>`, styleConfig},
+	{"RejectedData", `<div style="background: url(data:image/png;base64,AAAA); color: red">a</div>`, `<div style="color: red">a</div>`, styleConfig},
+	{"AllRejected", `<div style="background: url(javascript:x)">a</div>`, `<div>a</div>`, styleConfig},
+	{"Escape", `<div style="background: \75 rl(javascript:x); color: red">a</div>`, `<div style="color: red">a</div>`, styleConfig},
+	{"Unparsed", `<div style="background: url(a b)">a</div>`, `<div>a</div>`, styleConfig},
+}
+
+func TestStyleURLs(t *testing.T) {
+	doTableTest(Clean, t, testTableStyle)
+}
+
+var styleHostsConfig = DefaultConfig.Clone().ElemAttr("div", "style").
+	DenyHosts("div", "evil.example").
+	StripQuery("utm_source")
+
+var testTableStyleHosts = []testTable{
+	{"DeniedHost", `<div style="background-image: url(https://evil.example/a.png); color: red">a</div>`, `<div style="color: red">a</div>`, styleHostsConfig},
+	{"OtherHost", `<div style="background-image: url(https://example.com/a.png)">a</div>`, `<div style="background-image: url(&#34;https://example.com/a.png&#34;)">a</div>`, styleHostsConfig},
+	{"StripQuery", `<div style="background-image: url(https://example.com/a.png?utm_source=x)">a</div>`, `<div style="background-image: url(&#34;https://example.com/a.png&#34;)">a</div>`, styleHostsConfig},
+}
+
+func TestStyleURLHosts(t *testing.T) {
+	doTableTest(Clean, t, testTableStyleHosts)
+}
@@ -0,0 +1,119 @@
+package htmlcleaner
+
+import (
+	"fmt"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Template is trusted HTML with named slots for cleaned content. A slot is a
+// <slot name="..."> element, which Fill replaces with the content for it.
+// A Template is safe for concurrent use.
+type Template struct {
+	nodes []*html.Node
+}
+
+// SlotError is returned by Fill for content that does not fit a slot.
+type SlotError struct {
+	Slot    string
+	Problem string
+}
+
+func (err *SlotError) Error() string {
+	return fmt.Sprintf("htmlcleaner: slot %q: %s", err.Slot, err.Problem)
+}
+
+// phrasingOnly are block elements that may only contain inline content.
+var phrasingOnly = map[atom.Atom]bool{
+	atom.P:       true,
+	atom.H1:      true,
+	atom.H2:      true,
+	atom.H3:      true,
+	atom.H4:      true,
+	atom.H5:      true,
+	atom.H6:      true,
+	atom.Pre:     true,
+	atom.Dt:      true,
+	atom.Summary: true,
+}
+
+// ParseTemplate parses a trusted HTML template. The template is not
+// cleaned, so it must never come from users.
+func ParseTemplate(trusted string) *Template {
+	return &Template{nodes: ParseDepth(trusted, 0)}
+}
+
+// Fill returns the template with each slot replaced by the cleaned content
+// with the same name. Slots without content are removed. It returns a
+// *SlotError if content is given for a slot that does not exist, or if
+// content with block elements is given for a slot inside an inline element
+// or a paragraph, where the browser would move the blocks out of place.
+func (t *Template) Fill(content map[string]SanitizedHTML) (SanitizedHTML, error) {
+	nodes := deepCopyAll(t.nodes)
+
+	used := make(map[string]bool)
+	var err error
+	var fill func(n *html.Node, inline bool)
+	fill = func(n *html.Node, inline bool) {
+		for c := n.FirstChild; c != nil && err == nil; {
+			next := c.NextSibling
+			if c.Type != html.ElementNode {
+				c = next
+				continue
+			}
+
+			if c.Data != "slot" || c.Namespace != "" {
+				fill(c, inline || phrasingOnly[c.DataAtom] || (c.DataAtom != 0 && !isBlockElement[c.DataAtom]))
+				c = next
+				continue
+			}
+
+			name, _ := getAttr(c, "name")
+			used[name] = true
+			for _, r := range Parse(string(content[name])) {
+				if inline && hasBlockElement(r) {
+					err = &SlotError{Slot: name, Problem: "block content in an inline slot"}
+					return
+				}
+				n.InsertBefore(r, c)
+			}
+			n.RemoveChild(c)
+			c = next
+		}
+	}
+
+	parent := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range nodes {
+		parent.AppendChild(n)
+	}
+	fill(parent, false)
+	if err != nil {
+		return "", err
+	}
+
+	for name := range content {
+		if !used[name] {
+			return "", &SlotError{Slot: name, Problem: "no such slot"}
+		}
+	}
+
+	nodes = nodes[:0]
+	for n := parent.FirstChild; n != nil; n = n.NextSibling {
+		nodes = append(nodes, n)
+	}
+	return SanitizedHTML(Render(nodes...)), nil
+}
+
+// hasBlockElement reports whether n is or contains a known block element.
+func hasBlockElement(n *html.Node) bool {
+	found := false
+	Walk(n, func(n *html.Node) WalkAction {
+		if n.Type == html.ElementNode && n.DataAtom != 0 && isBlockElement[n.DataAtom] {
+			found = true
+			return WalkStop
+		}
+		return WalkContinue
+	})
+	return found
+}
@@ -0,0 +1,41 @@
+package htmlcleaner
+
+import "testing"
+
+var testTemplate = ParseTemplate(`<article><h2><a href="/post/1"><slot name="title"></slot></a></h2>` +
+	`<div class="body"><slot name="body"></slot></div><footer>by <slot name="author"></slot></footer></article>`)
+
+func TestTemplate(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		content  map[string]SanitizedHTML
+		expected SanitizedHTML
+		err      string
+	}{
+		{"Filled", map[string]SanitizedHTML{
+			"title":  `Hello <em>world</em>`,
+			"body":   `<p>a</p><p>b &lt;c&gt;</p>`,
+			"author": `<b>me</b>`,
+		}, `<article><h2><a href="/post/1">Hello <em>world</em></a></h2><div class="body"><p>a</p><p>b &lt;c&gt;</p></div><footer>by <b>me</b></footer></article>`, ""},
+		{"Missing", map[string]SanitizedHTML{
+			"title": `x`,
+		}, `<article><h2><a href="/post/1">x</a></h2><div class="body"></div><footer>by </footer></article>`, ""},
+		{"BlockInInline", map[string]SanitizedHTML{
+			"title": `<p>x</p>`,
+		}, ``, `htmlcleaner: slot "title": block content in an inline slot`},
+		{"BlockInFooter", map[string]SanitizedHTML{
+			"author": `<p>x</p>`,
+		}, `<article><h2><a href="/post/1"></a></h2><div class="body"></div><footer>by <p>x</p></footer></article>`, ""},
+		{"UnknownSlot", map[string]SanitizedHTML{
+			"tilte": `x`,
+		}, ``, `htmlcleaner: slot "tilte": no such slot`},
+	} {
+		actual, err := testTemplate.Fill(tt.content)
+		if (err == nil) != (tt.err == "") || (err != nil && err.Error() != tt.err) {
+			t.Errorf("%s: expected error %q, got %v", tt.name, tt.err, err)
+		}
+		if actual != tt.expected {
+			t.Errorf("%s: expected %q\nactual   %q", tt.name, tt.expected, actual)
+		}
+	}
+}
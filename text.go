@@ -0,0 +1,234 @@
+package htmlcleaner
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// textWriter is the shared infrastructure for the renderers that convert a
+// cleaned node tree to some form of text. It collapses whitespace the way a
+// browser would, keeps track of block boundaries, and prefixes each line (for
+// example with "> " inside quotations).
+type textWriter struct {
+	buf bytes.Buffer
+
+	// element is called for each element node. It is responsible for
+	// calling children to render the element's contents.
+	element func(w *textWriter, n *html.Node)
+
+	// escape, if set, is called for text at the start of a line and
+	// returns the text to write instead.
+	escape func(w *textWriter, s string) string
+
+	prefix   []string // line prefixes, outermost first
+	newlines int      // newlines to write before the next text
+	space    bool     // whether a space is pending
+	pre      int      // depth of whitespace-preserving elements
+	lineLen  int      // length of the current line, in runes
+	started  bool     // whether the current line has content after its prefix
+	width    int      // wrap text at this many runes, if non-zero
+}
+
+func newTextWriter(element func(w *textWriter, n *html.Node)) *textWriter {
+	w := &textWriter{element: element}
+	if element == nil {
+		w.element = (*textWriter).defaultElement
+	}
+	return w
+}
+
+// String returns the text written so far, without trailing whitespace.
+func (w *textWriter) String() string {
+	return strings.TrimRight(w.buf.String(), " \n")
+}
+
+// nodes renders a list of sibling nodes.
+func (w *textWriter) nodes(nodes []*html.Node) {
+	for _, n := range nodes {
+		w.node(n)
+	}
+}
+
+// children renders the children of n.
+func (w *textWriter) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.node(c)
+	}
+}
+
+func (w *textWriter) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.text(n.Data)
+	case html.ElementNode:
+		w.element(w, n)
+	}
+}
+
+// defaultElement renders the contents of n, separating blocks by line
+// breaks and preserving the whitespace of <pre> elements.
+func (w *textWriter) defaultElement(n *html.Node) {
+	switch {
+	case n.DataAtom == atom.Br:
+		w.lineBreak()
+	case preservesWhitespace[n.DataAtom] && isBlockElement[n.DataAtom]:
+		w.block(1)
+		w.preformatted(func() { w.children(n) })
+		w.block(1)
+	case n.DataAtom != 0 && isBlockElement[n.DataAtom]:
+		w.block(1)
+		w.children(n)
+		w.block(1)
+	default:
+		w.children(n)
+	}
+}
+
+// preformatted calls f with whitespace collapsing turned off.
+func (w *textWriter) preformatted(f func()) {
+	w.pre++
+	f()
+	w.pre--
+}
+
+// indent calls f with prefix added to the start of each line.
+func (w *textWriter) indent(prefix string, f func()) {
+	w.prefix = append(w.prefix, prefix)
+	f()
+	w.prefix = w.prefix[:len(w.prefix)-1]
+}
+
+// block ensures that the next text starts after at least n line breaks,
+// unless nothing has been written yet.
+func (w *textWriter) block(n int) {
+	if w.buf.Len() == 0 {
+		return
+	}
+	if w.newlines < n {
+		w.newlines = n
+	}
+	w.space = false
+}
+
+// lineBreak starts a new line, even if the current line is empty.
+func (w *textWriter) lineBreak() {
+	w.newlines++
+	w.space = false
+}
+
+// text writes text, collapsing whitespace unless inside a preformatted
+// element.
+func (w *textWriter) text(s string) {
+	if w.pre != 0 {
+		lines := strings.Split(s, "\n")
+		for i, line := range lines {
+			if i != 0 {
+				w.lineBreak()
+			}
+			w.writeString(line, true)
+		}
+		return
+	}
+
+	for i, word := range strings.FieldsFunc(s, isHTMLSpace) {
+		if i == 0 && len(s) != 0 && isHTMLSpace(rune(s[0])) {
+			w.space = true
+		}
+		if i != 0 {
+			w.space = true
+		}
+		w.writeString(word, true)
+	}
+	if len(s) != 0 && isHTMLSpace(rune(s[len(s)-1])) {
+		w.space = true
+	}
+}
+
+// write writes s verbatim, after any pending line breaks or space.
+func (w *textWriter) write(s string) {
+	w.writeString(s, false)
+}
+
+// writeString writes s after any pending line breaks or space, escaping it
+// if it is text at the start of a line.
+func (w *textWriter) writeString(s string, isText bool) {
+	if s == "" && w.newlines == 0 {
+		return
+	}
+
+	if w.buf.Len() == 0 {
+		w.newlines = 0
+		w.space = false
+		w.writePrefix(true)
+	}
+
+	for ; w.newlines > 0; w.newlines-- {
+		w.buf.WriteByte('\n')
+		w.lineLen = 0
+		w.writePrefix(w.newlines == 1)
+		w.space = false
+		w.started = false
+	}
+
+	if w.space && w.lineLen != 0 && w.wraps(s) {
+		w.buf.WriteByte('\n')
+		w.lineLen = 0
+		w.writePrefix(true)
+		w.started = false
+	} else if w.space && w.lineLen != 0 {
+		w.buf.WriteByte(' ')
+		w.lineLen++
+	}
+	w.space = false
+
+	if isText && !w.started && w.escape != nil {
+		s = w.escape(w, s)
+	}
+	if s != "" {
+		w.started = true
+	}
+
+	w.buf.WriteString(s)
+	w.lineLen += len([]rune(s))
+}
+
+// wraps reports whether s should start a new line instead of following a
+// space.
+func (w *textWriter) wraps(s string) bool {
+	return w.width != 0 && w.pre == 0 && w.lineLen+1+len([]rune(s)) > w.width
+}
+
+func (w *textWriter) writePrefix(content bool) {
+	prefix := strings.Join(w.prefix, "")
+	if !content {
+		prefix = strings.TrimRight(prefix, " ")
+	}
+	w.buf.WriteString(prefix)
+	w.lineLen += len([]rune(prefix))
+}
+
+// textContent returns the collapsed text inside n.
+func textContent(n *html.Node) string {
+	w := newTextWriter(nil)
+	w.children(n)
+	return w.String()
+}
+
+// preformattedText returns the text inside n with its whitespace preserved.
+func preformattedText(n *html.Node) string {
+	w := newTextWriter(nil)
+	w.preformatted(func() { w.children(n) })
+	return w.String()
+}
+
+func getAttr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,22 @@
+package htmlcleaner
+
+import "golang.org/x/net/html"
+
+// filterText applies TextFilter to each text node in nodes and the elements
+// inside them.
+func filterText(c *Config, nodes []*html.Node) {
+	var visit func(parent, n *html.Node)
+	visit = func(parent, n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			n.Data = c.TextFilter(parent, n.Data)
+		case html.ElementNode:
+			for child := n.FirstChild; child != nil; child = child.NextSibling {
+				visit(n, child)
+			}
+		}
+	}
+	for _, n := range nodes {
+		visit(nil, n)
+	}
+}
@@ -0,0 +1,36 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func maskWords(parent *html.Node, text string) string {
+	for p := parent; p != nil; p = p.Parent {
+		if p.DataAtom == atom.Code || p.DataAtom == atom.Pre {
+			return text
+		}
+	}
+	return strings.Replace(text, "darn", "****", -1)
+}
+
+var textFilterConfig = func() *Config {
+	c := DefaultConfig.Clone()
+	c.TextFilter = maskWords
+	return c
+}()
+
+var testTableTextFilter = []testTable{
+	{"TopLevel", `darn it`, `**** it`, textFilterConfig},
+	{"Nested", `<b>darn <i>darn</i></b>`, `<b>**** <i>****</i></b>`, textFilterConfig},
+	{"Code", `<code>darn</code> <pre><b>darn</b></pre>`, `<code>darn</code> <pre><b>darn</b></pre>`, textFilterConfig},
+	{"Attribute", `<a title="darn">x</a>`, `<a title="darn">x</a>`, textFilterConfig},
+	{"Escaped", `<script>darn</script>`, `&lt;script&gt;****&lt;/script&gt;`, textFilterConfig},
+}
+
+func TestTextFilter(t *testing.T) {
+	doTableTest(Clean, t, testTableTextFilter)
+}
@@ -0,0 +1,112 @@
+package htmlcleaner
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// MaxTextLength limits the text inside each element with the specified name,
+// such as <pre>, to n characters. The rest of the element's contents,
+// including any elements after the cut, is removed, and TruncationMarker is
+// added. The receiver is returned to allow call chaining.
+func (c *Config) MaxTextLength(elem string, n int) *Config {
+	if c.maxText == nil {
+		c.maxText = make(map[string]int)
+	}
+
+	c.maxText[elem] = n
+
+	return c
+}
+
+// MaxAttrTextLength limits the value of an attribute, such as alt or title,
+// to n characters on elements with the specified name, or on all elements
+// if elem is "*". Longer values are cut short and TruncationMarker is added,
+// except for URLs, which are removed instead. A limit for a specific
+// element takes precedence over a limit for "*". The receiver is returned to
+// allow call chaining.
+func (c *Config) MaxAttrTextLength(elem, attr string, n int) *Config {
+	if c.maxAttrText == nil {
+		c.maxAttrText = make(map[ElemAttr]int)
+	}
+
+	c.maxAttrText[ElemAttr{Elem: elem, Attr: attr}] = n
+
+	return c
+}
+
+// maxAttrTextFor returns the MaxAttrTextLength limit for an attribute.
+func (c *Config) maxAttrTextFor(elem, attr string) (int, bool) {
+	if n, ok := c.maxAttrText[ElemAttr{Elem: elem, Attr: attr}]; ok {
+		return n, true
+	}
+	n, ok := c.maxAttrText[ElemAttr{Elem: "*", Attr: attr}]
+	return n, ok
+}
+
+// truncateAttrText applies MaxAttrTextLength to an allowed attribute, and
+// reports whether it may be kept.
+func (c *Config) truncateAttrText(elem string, attr *html.Attribute) bool {
+	max, ok := c.maxAttrTextFor(elem, attr.Key)
+	if !ok || utf8.RuneCountInString(attr.Val) <= max {
+		return true
+	}
+
+	if isURLAttr(atom.Lookup([]byte(attr.Key))) {
+		return false
+	}
+
+	attr.Val = truncateRunes(attr.Val, max) + c.TruncationMarker
+	return true
+}
+
+// truncateText applies MaxTextLength to the contents of n, and reports
+// whether anything was removed.
+func (c *Config) truncateText(n *html.Node) bool {
+	max, ok := c.maxText[n.Data]
+	if !ok {
+		return false
+	}
+
+	return c.truncateChildren(n, &max)
+}
+
+// truncateChildren keeps the first *budget characters of text inside n.
+func (c *Config) truncateChildren(n *html.Node, budget *int) bool {
+	truncated := false
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+
+		switch {
+		case *budget < 0:
+			n.RemoveChild(child)
+		case child.Type == html.TextNode:
+			length := utf8.RuneCountInString(child.Data)
+			if length <= *budget {
+				*budget -= length
+				break
+			}
+			child.Data = truncateRunes(child.Data, *budget) + c.TruncationMarker
+			*budget = -1
+			truncated = true
+		case child.Type == html.ElementNode:
+			truncated = c.truncateChildren(child, budget) || truncated
+		}
+
+		child = next
+	}
+	return truncated
+}
+
+// truncateRunes returns the first n runes of s.
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
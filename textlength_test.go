@@ -0,0 +1,39 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html/atom"
+)
+
+var textLengthConfig = func() *Config {
+	c := DefaultConfig.Clone().
+		ElemAtom(atom.Ul, atom.Li).
+		MaxTextLength("pre", 10).
+		MaxTextLength("li", 3).
+		MaxAttrTextLength("img", "alt", 5).
+		MaxAttrTextLength("*", "title", 4).
+		MaxAttrTextLength("a", "title", 8).
+		MaxAttrTextLength("*", "href", 20)
+
+	c.TruncationMarker = "…"
+
+	return c
+}()
+
+var testTableTextLength = []testTable{
+	{"Short", `<pre>0123456789</pre>`, `<pre>0123456789</pre>`, textLengthConfig},
+	{"Text", `<pre>0123456789abc</pre>`, `<pre>0123456789…</pre>`, textLengthConfig},
+	{"Nested", `<pre>01234<b>56789</b>abc<i>def</i></pre>after`, `<pre>01234<b>56789</b>…</pre>after`, textLengthConfig},
+	{"Cut", `<pre>012<b>3456789abc</b><i>def</i></pre>`, `<pre>012<b>3456789…</b></pre>`, textLengthConfig},
+	{"Runes", `<ul><li>日本語です</li><li>abc</li></ul>`, `<ul><li>日本語…</li><li>abc</li></ul>`, textLengthConfig},
+	{"Alt", `<img src="/a.png" alt="abcdefgh" title="abcdefgh">`, `<img src="/a.png" alt="abcde…" title="abcd…"/>`, textLengthConfig},
+	{"ElemOverGlobal", `<a title="abcdefghij">x</a>`, `<a title="abcdefgh…">x</a>`, textLengthConfig},
+	{"URL", `<a href="https://example.com/long/path">x</a><a href="/short">y</a>`, `<a>x</a><a href="/short">y</a>`, textLengthConfig},
+	{"Unlimited", `<p title="abcdefgh">` + strings.Repeat("x", 20) + `</p>`, `<p title="abcd…">` + strings.Repeat("x", 20) + `</p>`, textLengthConfig},
+}
+
+func TestTextLength(t *testing.T) {
+	doTableTest(Clean, t, testTableTextLength)
+}
@@ -0,0 +1,126 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/text/unicode/norm"
+)
+
+// BidiAction is what happens to bidirectional override, embedding, and
+// isolate characters, such as U+202E RIGHT-TO-LEFT OVERRIDE, which can make
+// text display differently from its logical order, such as a file name
+// ending in "exe.txt" that displays as "txt.exe". See Config.BidiControls.
+type BidiAction int
+
+const (
+	// BidiKeep leaves bidirectional control characters alone.
+	BidiKeep BidiAction = iota
+
+	// BidiStrip removes bidirectional control characters.
+	BidiStrip
+
+	// BidiReplace replaces bidirectional control characters with U+FFFD
+	// REPLACEMENT CHARACTER, so that their presence is visible.
+	BidiReplace
+)
+
+// ZeroWidthAction is what happens to invisible zero-width characters. See
+// Config.ZeroWidth.
+type ZeroWidthAction int
+
+const (
+	// ZeroWidthKeep leaves zero-width characters alone.
+	ZeroWidthKeep ZeroWidthAction = iota
+
+	// ZeroWidthStrip removes zero-width spaces, word joiners, and
+	// byte order marks. Zero-width joiners and non-joiners are kept, as
+	// they are needed by emoji sequences and some scripts.
+	ZeroWidthStrip
+)
+
+func isBidiControl(r rune) bool {
+	return (r >= '\u202a' && r <= '\u202e') || (r >= '\u2066' && r <= '\u2069')
+}
+
+func isZeroWidth(r rune) bool {
+	return r == '\u200b' || r == '\u2060' || r == '\ufeff'
+}
+
+// confusableScripts are the scripts whose letters look alike. A word that
+// mixes them, such as "pаypal" with a Cyrillic "а", is likely to be an
+// imitation.
+var confusableScripts = []*unicode.RangeTable{
+	unicode.Latin,
+	unicode.Greek,
+	unicode.Cyrillic,
+}
+
+// mixesScripts reports whether word contains letters from more than one of
+// the confusableScripts.
+func mixesScripts(word string) bool {
+	found := -1
+	for _, r := range word {
+		for i, script := range confusableScripts {
+			if unicode.Is(script, r) {
+				if found != -1 && found != i {
+					return true
+				}
+				found = i
+			}
+		}
+	}
+	return false
+}
+
+// needsTextNormalization reports whether any of the options applied by
+// normalizeText are set.
+func (c *Config) needsTextNormalization() bool {
+	return c.NormalizeUnicode || c.BidiControls != BidiKeep || c.ZeroWidth != ZeroWidthKeep || c.OnConfusable != nil
+}
+
+// normalizeText applies NormalizeUnicode, BidiControls, ZeroWidth, and
+// OnConfusable to the text nodes in nodes.
+func normalizeText(c *Config, nodes []*html.Node) {
+	for _, n := range nodes {
+		Walk(n, func(n *html.Node) WalkAction {
+			if n.Type == html.TextNode {
+				n.Data = c.normalizeString(n.Data)
+			}
+			return WalkContinue
+		})
+	}
+}
+
+func (c *Config) normalizeString(s string) string {
+	if c.NormalizeUnicode {
+		s = norm.NFC.String(s)
+	}
+
+	if c.BidiControls != BidiKeep || c.ZeroWidth != ZeroWidthKeep {
+		s = strings.Map(func(r rune) rune {
+			switch {
+			case isBidiControl(r) && c.BidiControls == BidiStrip:
+				return -1
+			case isBidiControl(r) && c.BidiControls == BidiReplace:
+				return unicode.ReplacementChar
+			case isZeroWidth(r) && c.ZeroWidth == ZeroWidthStrip:
+				return -1
+			}
+			return r
+		}, s)
+	}
+
+	if c.OnConfusable != nil {
+		for _, word := range strings.FieldsFunc(s, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsMark(r)
+		}) {
+			if mixesScripts(word) {
+				c.OnConfusable(word)
+			}
+		}
+	}
+
+	return s
+}
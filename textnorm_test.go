@@ -0,0 +1,69 @@
+package htmlcleaner
+
+import (
+	"reflect"
+	"testing"
+)
+
+var normalizeUnicodeConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.NormalizeUnicode = true
+
+	return c
+}()
+
+var bidiStripConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.BidiControls = BidiStrip
+
+	return c
+}()
+
+var bidiReplaceConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.BidiControls = BidiReplace
+
+	return c
+}()
+
+var zeroWidthStripConfig = func() *Config {
+	c := DefaultConfig.Clone()
+
+	c.ZeroWidth = ZeroWidthStrip
+
+	return c
+}()
+
+var testTableTextNorm = []testTable{
+	{"Off", "e\u0301 a\u202eb\u200bc", "e\u0301 a\u202eb\u200bc", nil},
+	{"NFC", "<b title=\"e\u0301\">e\u0301</b>", "<b title=\"e\u0301\">\u00e9</b>", normalizeUnicodeConfig},
+	{"BidiStrip", "photo\u202egpj.exe <code>a\u2066b\u2069</code>", "photogpj.exe <code>ab</code>", bidiStripConfig},
+	{"BidiReplace", "photo\u202egpj.exe", "photo\ufffdgpj.exe", bidiReplaceConfig},
+	{"BidiMarks", "a\u200fb\u200ec", "a\u200fb\u200ec", bidiStripConfig},
+	{"ZeroWidth", "a\u200bb\u2060c\ufeffd\U0001F468\u200d\U0001F469", "abcd\U0001F468\u200d\U0001F469", zeroWidthStripConfig},
+	{"Escaped", "<script>\u202ex</script>", "&lt;script&gt;x&lt;/script&gt;", bidiStripConfig},
+}
+
+func TestTextNormalization(t *testing.T) {
+	doTableTest(Clean, t, testTableTextNorm)
+}
+
+func TestOnConfusable(t *testing.T) {
+	var words []string
+	c := DefaultConfig.Clone()
+	c.OnConfusable = func(word string) {
+		words = append(words, word)
+	}
+
+	input := "Log in to p\u0430ypal.com, not paypal.com, Москва, or αβγ. <b>\u0391pple</b>"
+	if output := Clean(c, input); output != input {
+		t.Errorf("unexpected output %q", output)
+	}
+
+	if expected := []string{"p\u0430ypal", "\u0391pple"}; !reflect.DeepEqual(words, expected) {
+		t.Errorf("expected %q, actual %q", expected, words)
+	}
+}
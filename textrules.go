@@ -0,0 +1,177 @@
+package htmlcleaner
+
+import (
+	"regexp"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+type textRule struct {
+	re *regexp.Regexp
+
+	// build returns the replacement for a match, or false to leave the
+	// match alone and search again after its first character
+	build func(match []string) ([]*html.Node, bool)
+
+	// if true, the nodes returned by build are not cleaned
+	trusted bool
+
+	// the elements that the nodes returned by build may have, and their
+	// attributes, for a trusted rule
+	adds map[atom.Atom][]string
+}
+
+// ReplaceText adds a rule that replaces each match of re in text nodes with
+// the nodes returned by build, which is passed the match and its submatches
+// as returned by FindStringSubmatch. It can be used to turn @mentions and
+// #hashtags into links. The nodes returned by build are cleaned using the
+// Config, so they must be allowed by it.
+//
+// Text inside <a>, <code>, <pre>, <kbd>, and <samp> elements is not
+// replaced. If more than one rule matches at the same position, the rule that
+// was added first is used. Replacements are not themselves searched for
+// further matches. The receiver is returned to allow call chaining.
+func (c *Config) ReplaceText(re *regexp.Regexp, build func(match []string) []*html.Node) *Config {
+	c.textRules = append(c.textRules, textRule{re: re, build: func(m []string) ([]*html.Node, bool) {
+		return build(m), true
+	}})
+	return c
+}
+
+// Link returns an <a> element with the specified href and text.
+func Link(href, text string) *html.Node {
+	a := &html.Node{
+		Type:     html.ElementNode,
+		Data:     "a",
+		DataAtom: atom.A,
+		Attr:     []html.Attribute{{Key: "href", Val: href}},
+	}
+	a.AppendChild(&html.Node{Type: html.TextNode, Data: text})
+	return a
+}
+
+var skipTextRules = map[atom.Atom]bool{
+	atom.A:        true,
+	atom.Code:     true,
+	atom.Kbd:      true,
+	atom.Pre:      true,
+	atom.Samp:     true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Textarea: true,
+}
+
+func applyTextRules(c *cleaner, nodes []*html.Node) []*html.Node {
+	replaced := make([]*html.Node, 0, len(nodes))
+	for _, n := range nodes {
+		if n.Type == html.TextNode {
+			replaced = append(replaced, replaceText(c, n.Data)...)
+			continue
+		}
+		applyTextRulesChildren(c, n)
+		replaced = append(replaced, n)
+	}
+	return replaced
+}
+
+func applyTextRulesChildren(c *cleaner, n *html.Node) {
+	if n.Type != html.ElementNode || skipTextRules[n.DataAtom] {
+		return
+	}
+
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+		if child.Type == html.TextNode {
+			for _, r := range replaceText(c, child.Data) {
+				n.InsertBefore(r, child)
+			}
+			n.RemoveChild(child)
+		} else {
+			applyTextRulesChildren(c, child)
+		}
+		child = next
+	}
+}
+
+// replaceText splits s into text nodes and replacement nodes.
+func replaceText(c *cleaner, s string) []*html.Node {
+	var nodes []*html.Node
+
+	// the text from start to pos has not been replaced, and searching
+	// continues at pos
+	start, pos := 0, 0
+
+	// the next match of each rule at or after pos, with offsets into s,
+	// so that each rule only searches again once its match is passed
+	next := make([][]int, len(c.rules))
+	done := make([]bool, len(c.rules))
+
+	for pos < len(s) {
+		var rule *textRule
+		var loc []int
+		for i := range c.rules {
+			if done[i] {
+				continue
+			}
+			if next[i] == nil || next[i][0] < pos {
+				l := c.rules[i].re.FindStringSubmatchIndex(s[pos:])
+				if l == nil {
+					done[i] = true
+					continue
+				}
+				for j := range l {
+					if l[j] >= 0 {
+						l[j] += pos
+					}
+				}
+				next[i] = l
+			}
+			l := next[i]
+			if l[0] == l[1] {
+				continue
+			}
+			if loc == nil || l[0] < loc[0] {
+				rule, loc = &c.rules[i], l
+			}
+		}
+		if rule == nil {
+			break
+		}
+
+		match := make([]string, len(loc)/2)
+		for i := range match {
+			if loc[2*i] >= 0 {
+				match[i] = s[loc[2*i]:loc[2*i+1]]
+			}
+		}
+		built, ok := rule.build(match)
+		if !ok {
+			_, size := utf8.DecodeRuneInString(s[loc[0]:])
+			pos = loc[0] + size
+			continue
+		}
+
+		if start != loc[0] {
+			nodes = append(nodes, text(s[start:loc[0]]))
+		}
+
+		for _, n := range built {
+			if rule.trusted {
+				nodes = append(nodes, n)
+			} else {
+				nodes = appendFiltered(nodes, filterNode(c, n))
+			}
+		}
+
+		pos = loc[1]
+		start = pos
+	}
+
+	if start != len(s) {
+		nodes = append(nodes, text(s[start:]))
+	}
+
+	return nodes
+}
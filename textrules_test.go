@@ -0,0 +1,33 @@
+package htmlcleaner
+
+import (
+	"regexp"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+var textRulesConfig = DefaultConfig.Clone().
+	ReplaceText(regexp.MustCompile(`@(\w+)`), func(m []string) []*html.Node {
+		return []*html.Node{Link("/users/"+m[1], m[0])}
+	}).
+	ReplaceText(regexp.MustCompile(`#(\w+)`), func(m []string) []*html.Node {
+		return []*html.Node{Link("/tags/"+m[1], m[0])}
+	}).
+	ReplaceText(regexp.MustCompile(`\bevil\b`), func(m []string) []*html.Node {
+		return []*html.Node{Link("javascript:alert(1)", m[0])}
+	})
+
+var testTableTextRules = []testTable{
+	{"NoMatch", `hello world`, `hello world`, textRulesConfig},
+	{"Mention", `hi @bob!`, `hi <a href="/users/bob">@bob</a>!`, textRulesConfig},
+	{"Multiple", `<p>#go and @alice#x</p>`, `<p><a href="/tags/go">#go</a> and <a href="/users/alice">@alice</a><a href="/tags/x">#x</a></p>`, textRulesConfig},
+	{"SkipLink", `<a href="/">@bob</a>`, `<a href="/">@bob</a>`, textRulesConfig},
+	{"SkipCode", `<pre><b>@bob</b></pre><code>#go</code>`, `<pre><b>@bob</b></pre><code>#go</code>`, textRulesConfig},
+	{"Escaped", `<script>@bob</script>`, `&lt;script&gt;<a href="/users/bob">@bob</a>&lt;/script&gt;`, textRulesConfig},
+	{"Policy", `so evil`, `so <a>evil</a>`, textRulesConfig},
+}
+
+func TestTextRules(t *testing.T) {
+	doTableTest(Clean, t, testTableTextRules)
+}
@@ -0,0 +1,28 @@
+package htmlcleaner
+
+import "golang.org/x/net/html"
+
+// TokenFilter decides what Preprocess writes for a token. If handled is
+// true, replacement is written instead of the token's raw HTML, and the
+// token is not otherwise processed. The replacement is not escaped, so it is
+// only safe if Clean is used on the output of Preprocess.
+type TokenFilter func(token html.Token) (replacement string, handled bool)
+
+// FilterTokens adds a filter that Preprocess calls for each token before
+// deciding whether to escape it, such as to escape only certain tag names.
+// Filters are called in the order they were added, until one handles the
+// token. The receiver is returned to allow call chaining.
+func (c *Config) FilterTokens(f TokenFilter) *Config {
+	c.tokenFilters = append(c.tokenFilters, f)
+	return c
+}
+
+// filterToken calls the token filters until one handles token.
+func (c *Config) filterToken(token html.Token) (string, bool) {
+	for _, f := range c.tokenFilters {
+		if replacement, ok := f(token); ok {
+			return replacement, true
+		}
+	}
+	return "", false
+}
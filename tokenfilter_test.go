@@ -0,0 +1,30 @@
+package htmlcleaner
+
+import (
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+var tokenFilterConfig = DefaultConfig.Clone().FilterTokens(func(token html.Token) (string, bool) {
+	if token.Data == "blink" && (token.Type == html.StartTagToken || token.Type == html.EndTagToken) {
+		return "", true
+	}
+	return "", false
+}).FilterTokens(func(token html.Token) (string, bool) {
+	if token.Type == html.StartTagToken && token.Data == "b" && len(token.Attr) != 0 {
+		return html.EscapeString(token.String()), true
+	}
+	return "", false
+})
+
+var testTableTokenFilter = []testTable{
+	{"Removed", `<blink>a</blink>`, `a`, tokenFilterConfig},
+	{"Escaped", `<b title="x">a</b>`, `&lt;b title=&#34;x&#34;&gt;a</b>`, tokenFilterConfig},
+	{"Unhandled", `<b>a</b><script>b</script>`, `<b>a</b>&lt;script&gt;b&lt;/script&gt;`, tokenFilterConfig},
+	{"Text", `a <3 b`, `a <3 b`, tokenFilterConfig},
+}
+
+func TestFilterTokens(t *testing.T) {
+	doTableTest(Preprocess, t, testTableTokenFilter)
+}
@@ -0,0 +1,55 @@
+package htmlcleaner
+
+// Tracer receives timing information about cleaning operations. It is a
+// small interface so that it can be adapted to any tracing system, such as
+// OpenTelemetry, without this package depending on one.
+type Tracer interface {
+	// StartSpan is called when an operation begins. The name is one of
+	// "htmlcleaner.Clean", "htmlcleaner.CleanNodes", or
+	// "htmlcleaner.Preprocess".
+	StartSpan(name string) Span
+}
+
+// Span is a single traced operation started by a Tracer.
+type Span interface {
+	// SetAttribute is called for sizes and rule hit counts, such as
+	// "input_bytes" or "escaped_elements", before End is called.
+	SetAttribute(key string, value int)
+
+	// End is called when the operation finishes.
+	End()
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, int) {}
+func (noopSpan) End()                     {}
+
+func (c *Config) startSpan(name string) Span {
+	if c.Tracer == nil {
+		return noopSpan{}
+	}
+	return c.Tracer.StartSpan(name)
+}
+
+func (c *cleaner) setRuleAttributes(span Span) {
+	span.SetAttribute("escaped_elements", c.escapedElems)
+	span.SetAttribute("removed_attributes", c.removedAttrs)
+	span.SetAttribute("rejected_urls", c.rejectedURLs)
+	if c.skippedChecks != 0 {
+		span.SetAttribute("skipped_checks", c.skippedChecks)
+		span.SetAttribute("partially_validated", 1)
+	}
+	if c.lowContrast != 0 {
+		span.SetAttribute("low_contrast", c.lowContrast)
+	}
+	if c.limitedCharRefs != 0 {
+		span.SetAttribute("limited_char_refs", c.limitedCharRefs)
+	}
+	if c.expansionExceeded != 0 {
+		span.SetAttribute("expansion_exceeded", 1)
+	}
+	if c.regexpEvals != 0 {
+		span.SetAttribute("regexp_evaluations", c.regexpEvals)
+	}
+}
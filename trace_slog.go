@@ -0,0 +1,41 @@
+//go:build go1.21
+// +build go1.21
+
+package htmlcleaner
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SlogTracer returns a Tracer that logs each operation, along with its
+// duration and attributes, to logger at the specified level.
+func SlogTracer(logger *slog.Logger, level slog.Level) Tracer {
+	return slogTracer{logger: logger, level: level}
+}
+
+type slogTracer struct {
+	logger *slog.Logger
+	level  slog.Level
+}
+
+func (t slogTracer) StartSpan(name string) Span {
+	return &slogSpan{tracer: t, name: name, start: time.Now()}
+}
+
+type slogSpan struct {
+	tracer slogTracer
+	name   string
+	start  time.Time
+	attrs  []slog.Attr
+}
+
+func (s *slogSpan) SetAttribute(key string, value int) {
+	s.attrs = append(s.attrs, slog.Int(key, value))
+}
+
+func (s *slogSpan) End() {
+	attrs := append([]slog.Attr{slog.Duration("duration", time.Since(s.start))}, s.attrs...)
+	s.tracer.logger.LogAttrs(context.Background(), s.tracer.level, s.name, attrs...)
+}
@@ -0,0 +1,61 @@
+package htmlcleaner_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/BenLubar/htmlcleaner"
+)
+
+type recordingTracer struct {
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) StartSpan(name string) htmlcleaner.Span {
+	s := &recordingSpan{name: name, attrs: make(map[string]int)}
+	t.spans = append(t.spans, s)
+	return s
+}
+
+type recordingSpan struct {
+	name  string
+	attrs map[string]int
+	ended bool
+}
+
+func (s *recordingSpan) SetAttribute(key string, value int) { s.attrs[key] = value }
+func (s *recordingSpan) End()                               { s.ended = true }
+
+func TestTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	c := htmlcleaner.DefaultConfig.Clone()
+	c.Tracer = tracer
+
+	input := `<a href="javascript:x()" onclick="y()">z</a><script></script>`
+	htmlcleaner.Clean(c, htmlcleaner.Preprocess(c, input))
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(tracer.spans))
+	}
+
+	expected := []recordingSpan{
+		{"htmlcleaner.Preprocess", map[string]int{
+			"input_bytes":  61,
+			"output_bytes": 73,
+			"escaped_tags": 2,
+		}, true},
+		{"htmlcleaner.Clean", map[string]int{
+			"input_bytes":        73,
+			"output_bytes":       37,
+			"escaped_elements":   0,
+			"removed_attributes": 2,
+			"rejected_urls":      1,
+		}, true},
+	}
+
+	for i, s := range tracer.spans {
+		if !reflect.DeepEqual(*s, expected[i]) {
+			t.Errorf("span %d: expected %+v, actual %+v", i, expected[i], *s)
+		}
+	}
+}
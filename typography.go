@@ -0,0 +1,102 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// skipTypography are the elements whose text is left alone by
+// SmartPunctuation, because it is code or other literal text.
+var skipTypography = map[atom.Atom]bool{
+	atom.Code:      true,
+	atom.Kbd:       true,
+	atom.Listing:   true,
+	atom.Plaintext: true,
+	atom.Pre:       true,
+	atom.Samp:      true,
+	atom.Script:    true,
+	atom.Style:     true,
+	atom.Textarea:  true,
+	atom.Tt:        true,
+	atom.Var:       true,
+	atom.Xmp:       true,
+}
+
+var typographyReplacer = strings.NewReplacer(
+	"...", "…",
+	"--", "—",
+)
+
+// smartPunctuation applies SmartPunctuation to the text of nodes. The
+// character before each quote decides which way it faces, even if it is in
+// a different text node, such as in "<b>word</b>'s".
+func smartPunctuation(nodes []*html.Node) {
+	var prev rune
+
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			n.Data, prev = curlQuotes(typographyReplacer.Replace(n.Data), prev)
+		case html.ElementNode:
+			if skipTypography[n.DataAtom] {
+				// code is a word of its own
+				prev = 'x'
+				return
+			}
+			if n.DataAtom == atom.Br || isBlockElement[n.DataAtom] {
+				prev = 0
+			}
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				visit(c)
+			}
+			if isBlockElement[n.DataAtom] {
+				prev = 0
+			}
+		}
+	}
+	for _, n := range nodes {
+		visit(n)
+	}
+}
+
+// opensQuote reports whether a quote after prev is an opening quote.
+func opensQuote(prev rune) bool {
+	return prev == 0 || unicode.IsSpace(prev) || strings.ContainsRune("([{—–“‘", prev)
+}
+
+// curlQuotes replaces the straight quotes in s with curly quotes, given the
+// character before s, and returns the last character of the result.
+func curlQuotes(s string, prev rune) (string, rune) {
+	if !strings.ContainsAny(s, `"'`) {
+		if r, size := utf8.DecodeLastRuneInString(s); size != 0 {
+			prev = r
+		}
+		return s, prev
+	}
+
+	buf := make([]rune, 0, len(s))
+	for _, r := range s {
+		switch r {
+		case '"':
+			if opensQuote(prev) {
+				r = '“'
+			} else {
+				r = '”'
+			}
+		case '\'':
+			if opensQuote(prev) {
+				r = '‘'
+			} else {
+				r = '’'
+			}
+		}
+		buf = append(buf, r)
+		prev = r
+	}
+	return string(buf), prev
+}
@@ -0,0 +1,29 @@
+package htmlcleaner
+
+import "testing"
+
+var smartPunctuationConfig = func() *Config {
+	c := DefaultConfig.Clone()
+	c.SmartPunctuation = true
+	return c
+}()
+
+var testTableSmartPunctuation = []testTable{
+	{"Double", `"quoted"`, "“quoted”", smartPunctuationConfig},
+	{"Single", `'quoted'`, "‘quoted’", smartPunctuationConfig},
+	{"Apostrophe", `it's`, "it’s", smartPunctuationConfig},
+	{"Nested", `"a 'b' c"`, "“a ‘b’ c”", smartPunctuationConfig},
+	{"Parenthesis", `("a")`, "(“a”)", smartPunctuationConfig},
+	{"Dash", `a--b`, "a—b", smartPunctuationConfig},
+	{"Ellipsis", `wait...`, "wait…", smartPunctuationConfig},
+	{"AcrossElements", `<b>word</b>'s "<i>x</i>"`, "<b>word</b>’s “<i>x</i>”", smartPunctuationConfig},
+	{"AfterBlock", `<p>a</p>"b"`, "<p>a</p>“b”", smartPunctuationConfig},
+	{"Code", `<code>"a"--b...</code> "c"`, `<code>&#34;a&#34;--b...</code> ` + "“c”", smartPunctuationConfig},
+	{"Pre", `<pre>'a'</pre>`, `<pre>&#39;a&#39;</pre>`, smartPunctuationConfig},
+	{"Attributes", `<a href="/a--b" title="'a'">'a'</a>`, `<a href="/a--b" title="&#39;a&#39;">` + "‘a’</a>", smartPunctuationConfig},
+	{"Disabled", `"a"--b`, `&#34;a&#34;--b`, nil},
+}
+
+func TestSmartPunctuation(t *testing.T) {
+	doTableTest(Clean, t, testTableSmartPunctuation)
+}
@@ -0,0 +1,62 @@
+package htmlcleaner
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// scriptURL matches a javascript: or vbscript: URL at the start of an
+// attribute value or inside a CSS url(), after whitespace and control
+// characters are removed.
+var scriptURL = regexp.MustCompile(`(?:^|url\(['"]?)(?:java|vb)script:`)
+
+// unsafeAttr reports whether an attribute is an event handler such as
+// onclick, or holds a script URL. These attributes are removed regardless
+// of the Config unless AllowUnsafe is set.
+func unsafeAttr(attr *html.Attribute) bool {
+	if strings.HasPrefix(strings.ToLower(attr.Key), "on") {
+		return true
+	}
+
+	val := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, attr.Val)
+	return scriptURL.MatchString(strings.ToLower(val))
+}
+
+// eventHandlerAttrs are the event handler attributes allowed by Trusted.
+var eventHandlerAttrs = []string{
+	"onabort", "onblur", "onchange", "onclick", "oncontextmenu", "ondblclick",
+	"onerror", "onfocus", "oninput", "onkeydown", "onkeypress", "onkeyup",
+	"onload", "onmousedown", "onmouseenter", "onmouseleave", "onmousemove",
+	"onmouseout", "onmouseover", "onmouseup", "onreset", "onresize",
+	"onscroll", "onselect", "onsubmit", "ontoggle", "onwheel",
+}
+
+// Trusted allows content that must never be accepted from untrusted users:
+// javascript: and vbscript: URLs, event handler attributes such as onclick,
+// style attributes, and <script> elements. It sets AllowUnsafe. It is meant
+// for content written by administrators; use a separate Config for
+// everything else, and never call Trusted on DefaultConfig or a Config
+// shared with other code. The receiver is returned to allow call chaining.
+func (c *Config) Trusted() *Config {
+	c.AllowUnsafe = true
+
+	if validate := c.ValidateURL; validate != nil {
+		c.ValidateURL = func(u *url.URL) bool {
+			scheme := strings.ToLower(u.Scheme)
+			return scheme == "javascript" || scheme == "vbscript" || validate(u)
+		}
+	}
+
+	return c.GlobalAttr(eventHandlerAttrs...).
+		GlobalAttrAtom(atom.Style).
+		ElemAttrAtom(atom.Script, atom.Src, atom.Type, atom.Async, atom.Defer)
+}
@@ -0,0 +1,52 @@
+package htmlcleaner
+
+import "testing"
+
+var misconfigured = (&Config{}).
+	ElemAttr("a", "href", "onclick", "title").
+	ElemAttr("img", "src", "onerror").
+	ElemAttr("span", "style")
+
+var trusting = func() *Config {
+	c := misconfigured.Clone()
+	c.AllowUnsafe = true
+	return c
+}()
+
+var testTableUnsafe = []testTable{
+	{"EventHandler", `<a href="/" onclick="alert(1)">x</a>`, `<a href="/">x</a>`, misconfigured},
+	{"EventHandlerImg", `<img src="/a.png" onerror="alert(1)">`, `<img src="/a.png"/>`, misconfigured},
+	{"JavascriptURL", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`, misconfigured},
+	{"ObfuscatedURL", "<a href=\" Java\tScript&#x0A;:alert(1)\">x</a>", `<a>x</a>`, misconfigured},
+	{"VBScriptURL", `<a href="vbscript:msgbox(1)">x</a>`, `<a>x</a>`, misconfigured},
+	{"StyleURL", `<span style="background: url('javascript:alert(1)')">x</span>`, `<span>x</span>`, misconfigured},
+	{"Title", `<a title="learn javascript: the basics">x</a>`, `<a title="learn javascript: the basics">x</a>`, misconfigured},
+	{"AllowUnsafe", `<a href="javascript:alert(1)" onclick="alert(2)">x</a>`, `<a href="javascript:alert(1)" onclick="alert(2)">x</a>`, trusting},
+}
+
+func TestUnsafeAttrs(t *testing.T) {
+	doTableTest(Clean, t, testTableUnsafe)
+
+	if misconfigured.AllowsAttr("a", "onclick", "") {
+		t.Error("onclick should not be allowed")
+	}
+}
+
+var trustedConfig = DefaultConfig.Clone().Trusted()
+
+var testTableTrusted = []testTable{
+	{"JavascriptURL", `<a href="javascript:go()">x</a>`, `<a href="javascript:go()">x</a>`, trustedConfig},
+	{"OtherURLs", `<a href="ftp://example.com/">x</a>`, `<a>x</a>`, trustedConfig},
+	{"EventHandler", `<b onclick="go()">x</b>`, `<b onclick="go()">x</b>`, trustedConfig},
+	{"Style", `<b style="color: red">x</b>`, `<b style="color: red">x</b>`, trustedConfig},
+	{"Script", `<script src="/a.js"></script><script>if (a < b && c) go();</script>`, `<script src="/a.js"></script><script>if (a < b && c) go();</script>`, trustedConfig},
+	{"NotDefault", `<script>go()</script>`, `&lt;script&gt;go()&lt;/script&gt;`, nil},
+}
+
+func TestTrusted(t *testing.T) {
+	doTableTest(Clean, t, testTableTrusted)
+
+	if DefaultConfig.AllowUnsafe || DefaultConfig.AllowsElem("script") {
+		t.Error("Trusted changed DefaultConfig")
+	}
+}
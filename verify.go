@@ -0,0 +1,242 @@
+package htmlcleaner
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// VerifyError is reported by CheckOutput and passed to Config.Verify when
+// cleaned HTML contains something the Config does not allow.
+type VerifyError struct {
+	// The HTML that failed verification.
+	Output string
+
+	// A description of each violation, in document order.
+	Problems []string
+}
+
+func (err *VerifyError) Error() string {
+	return fmt.Sprintf("htmlcleaner: %s in %q", strings.Join(err.Problems, ", "), err.Output)
+}
+
+// ViolationKind is the kind of a Violation.
+type ViolationKind int
+
+const (
+	// ViolationElem is an element that is not allowed.
+	ViolationElem ViolationKind = iota
+
+	// ViolationAttr is an attribute that is not allowed.
+	ViolationAttr
+
+	// ViolationURL is an allowed attribute with a URL that is not
+	// allowed, such as one with a javascript: scheme.
+	ViolationURL
+
+	// ViolationComment is a comment in output from a Config with
+	// EscapeComments or RemoveComments set.
+	ViolationComment
+
+	// ViolationDepth is a node nested more deeply than DefaultMaxDepth,
+	// which would be omitted if the output were parsed again.
+	ViolationDepth
+)
+
+// Violation is a part of cleaned HTML that does not comply with a Config,
+// as returned by Verify.
+type Violation struct {
+	Kind ViolationKind
+
+	// The location of the node, in the same form as Difference.Path.
+	Path string
+
+	// The element, and the attribute and its value for ViolationAttr and
+	// ViolationURL. For ViolationComment, Val is the comment's text.
+	Elem, Attr, Val string
+}
+
+func (v Violation) String() string {
+	switch v.Kind {
+	case ViolationElem:
+		return fmt.Sprintf("disallowed element <%s>", v.Elem)
+	case ViolationAttr, ViolationURL:
+		return fmt.Sprintf("disallowed attribute %s=%q on <%s>", v.Attr, v.Val, v.Elem)
+	case ViolationComment:
+		return "comment " + v.Val
+	default:
+		return "too deeply nested at " + v.Path
+	}
+}
+
+// Verify parses cleanedFragment as HTML and returns each part of it that
+// does not comply with c, or the FallbackConfig if c is nil, in document
+// order. The same things are allowed as by CheckOutput. It is meant for
+// checking stored or sampled output, such as after a change to c.
+func Verify(c *Config, cleanedFragment string) []Violation {
+	if c == nil {
+		c = FallbackConfig()
+	}
+
+	var violations []Violation
+
+	// highlighted is true inside a <pre> element if HighlightCode is set
+	var visit func(prefix string, siblings []*html.Node, i, depth int, highlighted bool)
+	visit = func(prefix string, siblings []*html.Node, i, depth int, highlighted bool) {
+		n := siblings[i]
+		path := nodePath(prefix, siblings, i)
+
+		if depth > DefaultMaxDepth {
+			violations = append(violations, Violation{Kind: ViolationDepth, Path: path, Elem: n.Data})
+			return
+		}
+
+		switch {
+		case n.Type == html.ElementNode && highlighted:
+		case n.Type == html.ElementNode:
+			if !c.AllowsElem(n.Data) && !c.addsElem(n.DataAtom) && !c.addsGalleryElem(n.Data) {
+				violations = append(violations, Violation{Kind: ViolationElem, Path: path, Elem: n.Data})
+			}
+			for _, a := range n.Attr {
+				if c.addsAttr(n.DataAtom, a.Key, a.Val) || c.addsGalleryAttr(n.Data, a.Key) {
+					continue
+				}
+				kind := ViolationAttr
+				if a.Namespace == "" {
+					verdict := c.checkAttr(n.DataAtom, n.Data, &html.Attribute{Key: a.Key, Val: a.Val})
+					if verdict == attrOK {
+						continue
+					}
+					if verdict == attrBadURL {
+						kind = ViolationURL
+					}
+				}
+				violations = append(violations, Violation{Kind: kind, Path: path, Elem: n.Data, Attr: a.Key, Val: a.Val})
+			}
+		case n.Type == html.CommentNode:
+			if c.EscapeComments || c.RemoveComments {
+				violations = append(violations, Violation{Kind: ViolationComment, Path: path, Val: n.Data})
+			}
+		}
+
+		highlighted = highlighted || (n.DataAtom == atom.Pre && c.HighlightCode != nil)
+
+		children := childNodes(n)
+		for j := range children {
+			visit(path, children, j, depth+1, highlighted)
+		}
+	}
+	nodes := ParseDepth(cleanedFragment, 0)
+	for i := range nodes {
+		visit("", nodes, i, 1, false)
+	}
+
+	return violations
+}
+
+// CheckOutput parses output as HTML and returns a *VerifyError if it contains
+// an element, attribute, or URL that is not allowed by c, or a comment if
+// EscapeComments or RemoveComments is set. The <p> and <ul> elements added
+// by WrapText and for dangling <li> elements are allowed, as are the
+// elements and attributes added by options such as SandboxMedia,
+// ImageLoading, Gallery, and EmojiShortcodes. If HighlightCode is set,
+// elements inside <pre> elements are not checked. Unlike Verify, CheckOutput
+// does not limit the depth of the output.
+func (c *Config) CheckOutput(output string) error {
+	var problems []string
+	for _, v := range Verify(c, output) {
+		if v.Kind != ViolationDepth {
+			problems = append(problems, v.String())
+		}
+	}
+
+	if len(problems) != 0 {
+		return &VerifyError{Output: output, Problems: problems}
+	}
+	return nil
+}
+
+// addsElem reports whether the cleaner itself may add an element that is not
+// explicitly allowed.
+func (c *Config) addsElem(a atom.Atom) bool {
+	switch a {
+	case atom.P:
+		return c.WrapText
+	case atom.Ul:
+		return c.AllowsElem("li")
+	case atom.Details, atom.Summary:
+		return c.QuoteSummary != "" && c.AllowsElem("blockquote")
+	case atom.Span:
+		return c.NBSP == NBSPSpan || c.truncatesWords()
+	case atom.Wbr:
+		return c.BreakLongWords > 0 && c.BreakWithWBR
+	}
+	for _, rule := range c.textRules {
+		if _, ok := rule.adds[a]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// truncatesWords reports whether MaxWordTruncate may add <span title>
+// elements.
+func (c *Config) truncatesWords() bool {
+	return c.MaxWordLength > 0 && c.MaxWordAction == MaxWordTruncate
+}
+
+// addsAttr reports whether the cleaner itself may add an attribute that is
+// not explicitly allowed.
+func (c *Config) addsAttr(e atom.Atom, key, val string) bool {
+	if c.NBSP == NBSPSpan && e == atom.Span && key == "class" && val == c.NBSPClass {
+		return true
+	}
+	if c.truncatesWords() && e == atom.Span && key == "title" {
+		return true
+	}
+	if c.NodeIDPrefix != "" && key == nodeIDAttr && e != 0 && isBlockElement[e] {
+		return true
+	}
+	if c.AutoDir && key == "dir" && autoDirElems[e] {
+		return true
+	}
+	if e == atom.Img && c.ImageDimensions != nil && (key == "width" || key == "height") {
+		return true
+	}
+	if e == atom.Img && (c.MissingAlt != AltIgnore || c.AltText != nil) && key == "alt" {
+		return true
+	}
+
+	for _, rule := range c.textRules {
+		for _, attr := range rule.adds[e] {
+			if attr == key {
+				return true
+			}
+		}
+	}
+
+	_, forced := c.forcedAttrs(e)
+	for _, attr := range forced {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// verify checks the output of a cleaning operation if c.Verify is set, and
+// reports whether it can be used.
+func (c *Config) verify(output string) bool {
+	if c.Verify == nil {
+		return true
+	}
+
+	if err := c.CheckOutput(output); err != nil {
+		c.Verify(err)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,102 @@
+package htmlcleaner
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	var errs []error
+	c := DefaultConfig.Clone().EmojiShortcodes(func(name string) (string, string, bool) {
+		return "", "/emoji/" + name + ".png", true
+	})
+	c.Verify = func(err error) { errs = append(errs, err) }
+
+	if out := Clean(c, `<b>hello</b>`); out != `<b>hello</b>` || len(errs) != 0 {
+		t.Errorf("unexpected output %q, errors %v", out, errs)
+	}
+
+	const emoji = `hi <img class="emoji" src="/emoji/wave.png" alt=":wave:" title=":wave:"/>`
+	if out := Clean(c, `hi :wave:`); out != emoji || len(errs) != 0 {
+		t.Errorf("unexpected output %q, errors %v", out, errs)
+	}
+	if out := Render(CleanNodes(c, Parse(`hi :wave:`))...); out != emoji || len(errs) != 0 {
+		t.Errorf("unexpected nodes %q, errors %v", out, errs)
+	}
+
+	const expected = `hi &lt;mark&gt;wave&lt;/mark&gt;`
+	if out := Highlight(c, `hi wave`, []string{"wave"}, nil); out != expected {
+		t.Errorf("unexpected output %q", out)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if err, ok := errs[0].(*VerifyError); !ok || len(err.Problems) != 1 || err.Problems[0] != `disallowed element <mark>` {
+		t.Errorf("unexpected error %#v", errs[0])
+	}
+}
+
+func TestVerifyViolations(t *testing.T) {
+	c := (&Config{ValidateURL: SafeURLScheme, EscapeComments: true}).Elem("p").ElemAttr("a", "href")
+
+	output := `<p><a href="/ok">a</a><a href="javascript:x()" title="t">b</a></p><!--c--><i>d</i>`
+	expected := []Violation{
+		{Kind: ViolationURL, Path: "p[0]/a[1]", Elem: "a", Attr: "href", Val: "javascript:x()"},
+		{Kind: ViolationAttr, Path: "p[0]/a[1]", Elem: "a", Attr: "title", Val: "t"},
+		{Kind: ViolationComment, Path: "#comment[0]", Val: "c"},
+		{Kind: ViolationElem, Path: "i[0]", Elem: "i"},
+	}
+	if actual := Verify(c, output); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v", expected)
+		t.Errorf("actual   %+v", actual)
+	}
+
+	deep := strings.Repeat("<b>", DefaultMaxDepth+1)
+	violations := Verify(nil, deep)
+	if len(violations) != 1 || violations[0].Kind != ViolationDepth {
+		t.Errorf("expected a depth violation, got %+v", violations)
+	}
+	if err := DefaultConfig.CheckOutput(deep); err != nil {
+		t.Errorf("CheckOutput should not limit depth: %v", err)
+	}
+
+	if violations := Verify(nil, Clean(nil, output)); len(violations) != 0 {
+		t.Errorf("unexpected violations %+v", violations)
+	}
+}
+
+func TestCheckOutput(t *testing.T) {
+	li := (&Config{}).Elem("li")
+	wrap := (&Config{}).Elem("b").SetWrapText(true)
+	comments := DefaultConfig.Clone().SetEscapeComments(true)
+
+	for _, tt := range []struct {
+		config *Config
+		output string
+		ok     bool
+	}{
+		{nil, `<b title="x">a</b>`, true},
+		{nil, `<a href="https://example.com/">a</a>`, true},
+		{nil, `&lt;script&gt;`, true},
+		{nil, `<!-- x -->`, true},
+		{nil, `<script>x</script>`, false},
+		{nil, `<b onclick="x()">a</b>`, false},
+		{nil, `<a href="javascript:x()">a</a>`, false},
+		{li, `<ul><li>a</li></ul>`, true},
+		{li, `<ol><li>a</li></ol>`, false},
+		{wrap, `<p><b>a</b></p>`, true},
+		{(&Config{}).Elem("b"), `<p><b>a</b></p>`, false},
+		{comments, `<!-- x -->`, false},
+		{quoteSummaryConfig, `<blockquote><details><summary>x</summary><blockquote>a</blockquote></details></blockquote>`, true},
+		{(&Config{}).Elem("blockquote"), `<details><summary>x</summary></details>`, false},
+		{emojiConfig, `<img class="emoji" src="/emoji/party_parrot.gif" alt=":party_parrot:" title=":party_parrot:"/>`, true},
+		{emojiConfig, `<img onerror="x()"/>`, false},
+		{highlightConfig, `<pre><code class="language-go">a <span class="kw">b</span></code></pre>`, true},
+		{highlightConfig, `<p><span class="kw">b</span></p>`, false},
+	} {
+		if err := tt.config.CheckOutput(tt.output); (err == nil) != tt.ok {
+			t.Errorf("%q: unexpected result %v", tt.output, err)
+		}
+	}
+}
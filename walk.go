@@ -0,0 +1,157 @@
+package htmlcleaner
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// WalkAction tells Walk what to do after visiting a node.
+type WalkAction int
+
+const (
+	// WalkContinue visits the children of the node, then its following
+	// siblings.
+	WalkContinue WalkAction = iota
+
+	// WalkSkip skips the children of the node.
+	WalkSkip
+
+	// WalkStop ends the walk.
+	WalkStop
+)
+
+// Walk calls fn for n and each of its descendants, in document order. fn
+// may change the node it is passed, but must not add or remove nodes.
+func Walk(n *html.Node, fn func(*html.Node) WalkAction) {
+	walk(n, fn)
+}
+
+// walk reports whether the walk was stopped.
+func walk(n *html.Node, fn func(*html.Node) WalkAction) bool {
+	switch fn(n) {
+	case WalkSkip:
+		return false
+	case WalkStop:
+		return true
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if walk(c, fn) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindAll returns the elements in n, including n itself, that match a
+// simple selector. A selector is an element name or "*", optionally
+// followed by any number of ".class", "#id", "[attr]", and "[attr=value]"
+// conditions, such as "a[href]" or "span.highlight". An element name may
+// be left out if there is at least one condition. Selectors separated by
+// spaces match descendants, so "a img" matches <img> elements inside <a>
+// elements, which may be outside n.
+func FindAll(n *html.Node, selector string) []*html.Node {
+	sel := parseSelector(selector)
+
+	var found []*html.Node
+	Walk(n, func(n *html.Node) WalkAction {
+		if n.Type == html.ElementNode && sel.match(n, ancestors(n)) {
+			found = append(found, n)
+		}
+		return WalkContinue
+	})
+	return found
+}
+
+// ancestors returns the element ancestors of n, outermost first.
+func ancestors(n *html.Node) []*html.Node {
+	var list []*html.Node
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode {
+			list = append([]*html.Node{p}, list...)
+		}
+	}
+	return list
+}
+
+// compileCompound returns the element name of a selector without
+// combinators, or "" for any element, and a function that reports whether
+// an element matches it.
+func compileCompound(selector string) (string, func(*html.Node) bool) {
+	var conds []func(*html.Node) bool
+
+	end := strings.IndexAny(selector, ".#[")
+	if end == -1 {
+		end = len(selector)
+	}
+	name := strings.ToLower(selector[:end])
+	if name == "*" {
+		name = ""
+	}
+	if name != "" {
+		conds = append(conds, func(n *html.Node) bool { return n.Data == name })
+	}
+	selector = selector[end:]
+
+	for selector != "" {
+		kind := selector[0]
+		selector = selector[1:]
+
+		var arg string
+		if kind == '[' {
+			end = strings.IndexByte(selector, ']')
+			if end == -1 {
+				end = len(selector)
+			}
+			arg = selector[:end]
+			if end < len(selector) {
+				end++
+			}
+		} else {
+			end = strings.IndexAny(selector, ".#[")
+			if end == -1 {
+				end = len(selector)
+			}
+			arg = selector[:end]
+		}
+		selector = selector[end:]
+
+		switch kind {
+		case '.':
+			conds = append(conds, func(n *html.Node) bool {
+				class, _ := getAttr(n, "class")
+				for _, c := range strings.FieldsFunc(class, isHTMLSpace) {
+					if c == arg {
+						return true
+					}
+				}
+				return false
+			})
+		case '#':
+			conds = append(conds, func(n *html.Node) bool {
+				id, ok := getAttr(n, "id")
+				return ok && id == arg
+			})
+		case '[':
+			key, val, hasVal := arg, "", false
+			if i := strings.IndexByte(arg, '='); i != -1 {
+				key, val, hasVal = arg[:i], strings.Trim(arg[i+1:], `"'`), true
+			}
+			key = strings.ToLower(key)
+			conds = append(conds, func(n *html.Node) bool {
+				v, ok := getAttr(n, key)
+				return ok && (!hasVal || v == val)
+			})
+		}
+	}
+
+	return name, func(n *html.Node) bool {
+		for _, cond := range conds {
+			if !cond(n) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,60 @@
+package htmlcleaner
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestWalk(t *testing.T) {
+	root := Parse(`<div><p>a<b>b</b></p><p>c<i>d</i></p><p>e</p></div>`)[0]
+
+	var visited []string
+	Walk(root, func(n *html.Node) WalkAction {
+		if n.Type != html.ElementNode {
+			return WalkContinue
+		}
+		visited = append(visited, n.Data)
+		switch n.Data {
+		case "b":
+			return WalkSkip
+		case "i":
+			return WalkStop
+		}
+		return WalkContinue
+	})
+
+	if expected, actual := "div p b p i", strings.Join(visited, " "); actual != expected {
+		t.Errorf("expected %q, actual %q", expected, actual)
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	root := Parse(`<div id="root"><a href="/a" class="x y">1</a><a name="b">2</a>` +
+		`<span class="x">3</span><a href="/c" class="xy">4</a></div>`)[0]
+
+	for _, tt := range []struct {
+		selector string
+		expected string
+	}{
+		{"a", "124"},
+		{"*", "12341234"},
+		{"a[href]", "14"},
+		{"a[href=/c]", "4"},
+		{`[href="/a"]`, "1"},
+		{".x", "13"},
+		{"a.x.y", "1"},
+		{"#root", "1234"},
+		{"span#root", ""},
+		{"A[HREF]", "14"},
+	} {
+		actual := ""
+		for _, n := range FindAll(root, tt.selector) {
+			actual += textContent(n)
+		}
+		if actual != tt.expected {
+			t.Errorf("%q: expected %q, actual %q", tt.selector, tt.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package htmlcleaner
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var preservesWhitespace = map[atom.Atom]bool{
+	atom.Pre:       true,
+	atom.Code:      true,
+	atom.Textarea:  true,
+	atom.Listing:   true,
+	atom.Plaintext: true,
+	atom.Xmp:       true,
+	atom.Script:    true,
+	atom.Style:     true,
+}
+
+func isHTMLSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\f' || r == '\r'
+}
+
+// collapseSpace replaces each run of HTML whitespace in s with a single
+// space.
+func collapseSpace(s string) string {
+	var buf []byte
+	space := false
+	for i := 0; i < len(s); i++ {
+		if isHTMLSpace(rune(s[i])) {
+			if !space {
+				buf = append(buf, ' ')
+			}
+			space = true
+			continue
+		}
+		buf = append(buf, s[i])
+		space = false
+	}
+	return string(buf)
+}
+
+func collapseWhitespace(c *Config, nodes []*html.Node) []*html.Node {
+	parent := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range nodes {
+		parent.AppendChild(n)
+	}
+
+	collapseChildren(c, parent)
+
+	nodes = nodes[:0]
+	for parent.FirstChild != nil {
+		n := parent.FirstChild
+		parent.RemoveChild(n)
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+func collapseChildren(c *Config, parent *html.Node) {
+	for n := parent.FirstChild; n != nil; n = n.NextSibling {
+		switch n.Type {
+		case html.TextNode:
+			n.Data = collapseSpace(n.Data)
+		case html.ElementNode:
+			if !preservesWhitespace[n.DataAtom] {
+				collapseChildren(c, n)
+			}
+		}
+	}
+
+	if isBlockElement[parent.DataAtom] {
+		if n := parent.FirstChild; n != nil && n.Type == html.TextNode {
+			n.Data = strings.TrimLeft(n.Data, " ")
+		}
+		if n := parent.LastChild; n != nil && n.Type == html.TextNode {
+			n.Data = strings.TrimRight(n.Data, " ")
+		}
+	}
+
+	for n := parent.FirstChild; n != nil; {
+		next := n.NextSibling
+		if n.Type == html.TextNode && (n.Data == "" || (c.RemoveInterTagWhitespace && n.Data == " ")) {
+			parent.RemoveChild(n)
+		}
+		n = next
+	}
+}
@@ -0,0 +1,129 @@
+package htmlcleaner
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var voidElements = map[atom.Atom]bool{
+	atom.Area:   true,
+	atom.Base:   true,
+	atom.Br:     true,
+	atom.Col:    true,
+	atom.Embed:  true,
+	atom.Hr:     true,
+	atom.Img:    true,
+	atom.Input:  true,
+	atom.Keygen: true,
+	atom.Link:   true,
+	atom.Meta:   true,
+	atom.Param:  true,
+	atom.Source: true,
+	atom.Track:  true,
+	atom.Wbr:    true,
+}
+
+var xmlNamespaces = map[string]string{
+	"svg":  "http://www.w3.org/2000/svg",
+	"math": "http://www.w3.org/1998/Math/MathML",
+}
+
+var xmlName = regexp.MustCompile(`\A[A-Za-z_][-A-Za-z0-9_.]*\z`)
+
+var xmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&apos;",
+	"\t", "&#9;",
+	"\n", "&#10;",
+	"\r", "&#13;",
+)
+
+// RenderXHTML is like Render, but produces well-formed XML that can be
+// embedded in XHTML documents, Atom and RSS feeds, and EPUB files. Void
+// elements such as <br/> are self-closed, other elements always have an end
+// tag, the only character references used are the five defined by XML and
+// numeric references for whitespace in attributes, and characters that are
+// not allowed in XML are removed. SVG and MathML elements are given an xmlns
+// attribute. Elements and attributes whose names are not valid in XML are
+// left out, keeping the contents of the elements.
+func RenderXHTML(nodes ...*html.Node) string {
+	var buf bytes.Buffer
+	for _, n := range nodes {
+		renderXHTML(&buf, n)
+	}
+	return buf.String()
+}
+
+// xmlText removes the characters that are not allowed in XML 1.0.
+func xmlText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' || (r >= 0x20 && r <= 0xd7ff) || (r >= 0xe000 && r <= 0xfffd) || r >= 0x10000 {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+func renderXHTML(buf *bytes.Buffer, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		buf.WriteString(strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(xmlText(n.Data)))
+	case html.CommentNode:
+		data := xmlText(n.Data)
+		for strings.Contains(data, "--") {
+			data = strings.Replace(data, "--", "- -", -1)
+		}
+		if strings.HasSuffix(data, "-") {
+			data += " "
+		}
+		buf.WriteString("<!--" + data + "-->")
+	case html.DocumentNode:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			renderXHTML(buf, c)
+		}
+	case html.ElementNode:
+		if !xmlName.MatchString(n.Data) {
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				renderXHTML(buf, c)
+			}
+			return
+		}
+
+		buf.WriteString("<" + n.Data)
+		if ns, ok := xmlNamespaces[n.Namespace]; ok && (n.Parent == nil || n.Parent.Namespace != n.Namespace) {
+			buf.WriteString(` xmlns="` + ns + `" xmlns:xlink="http://www.w3.org/1999/xlink"`)
+		}
+		seen := make(map[string]bool)
+		for _, a := range n.Attr {
+			key := a.Key
+			if a.Namespace == "xlink" || a.Namespace == "xml" {
+				key = a.Namespace + ":" + key
+			} else if a.Namespace != "" || !xmlName.MatchString(key) || strings.HasPrefix(key, "xmlns") {
+				continue
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			buf.WriteString(" " + key + `="` + xmlEscaper.Replace(xmlText(a.Val)) + `"`)
+		}
+
+		if n.FirstChild == nil && (voidElements[n.DataAtom] || n.Namespace != "") {
+			buf.WriteString("/>")
+			return
+		}
+
+		buf.WriteString(">")
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			renderXHTML(buf, c)
+		}
+		buf.WriteString("</" + n.Data + ">")
+	}
+}
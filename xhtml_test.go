@@ -0,0 +1,45 @@
+package htmlcleaner
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func cleanXHTML(c *Config, fragment string) string {
+	return RenderXHTML(CleanNodes(c, Parse(fragment))...)
+}
+
+var xhtmlConfig = DefaultConfig.Clone().Elem("br", "hr", "svg", "circle").ElemAttr("x-y", "data:z")
+
+var testTableXHTML = []testTable{
+	{"Empty", ``, ``, xhtmlConfig},
+	{"Void", `a<br>b<hr><img src="/a.png" alt="">`, `a<br/>b<hr/><img src="/a.png" alt=""/>`, xhtmlConfig},
+	{"EmptyElement", `<b></b>`, `<b></b>`, xhtmlConfig},
+	{"Entities", `&nbsp;&copy;&lt;&amp;&quot;'`, "\u00a0©&lt;&amp;\"'", xhtmlConfig},
+	{"Attributes", `<a title="&quot;a&apos;
+b&lt;">x</a>`, `<a title="&quot;a&apos;&#10;b&lt;">x</a>`, xhtmlConfig},
+	{"Comment", `<!-- a -- b --->`, `<!-- a - - b - -->`, xhtmlConfig},
+	{"ControlCharacters", "a\x01b\uffffc", "abc", xhtmlConfig},
+	{"SVG", `<svg><circle></circle></svg>`, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><circle/></svg>`, xhtmlConfig},
+	{"InvalidAttribute", `<x-y data:z="1">a</x-y>`, `<x-y>a</x-y>`, xhtmlConfig},
+}
+
+func TestRenderXHTML(t *testing.T) {
+	doTableTest(cleanXHTML, t, testTableXHTML)
+
+	for _, tt := range testTableXHTML {
+		d := xml.NewDecoder(strings.NewReader("<root>" + cleanXHTML(tt.Config, tt.Input) + "</root>"))
+		for {
+			_, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Errorf("%s: not well-formed: %v", tt.Name, err)
+				break
+			}
+		}
+	}
+}